@@ -4,11 +4,26 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/samber/mo"
 )
 
+// ...
+var (
+	ErrInvalidTimestamp = errors.New(
+		"the timestamp is not greater than the previous one",
+	)
+	ErrInvalidPrevHash = errors.New(
+		"the previous hash is not equal to the hash of the previous block",
+	)
+	ErrInvalidProof  = errors.New("the validation via the proofer was failed")
+	ErrInvalidHeight = errors.New(
+		"the height is not next to the height of the previous block",
+	)
+)
+
 // Clock ...
 type Clock func() time.Time
 
@@ -22,6 +37,49 @@ type Proofer interface {
 	Difficulty(hash string) (int, error)
 }
 
+//go:generate mockery --name=WorkProofer --inpackage --case=underscore --testonly
+
+// WorkProofer ...
+//
+// It's an optional extension of the [Proofer] interface that returns
+// the expected quantity of the work spent on the block. Unlike
+// the difficulty, it grows exponentially with the target of the work,
+// so it's used for the comparison of the forks if it's supported.
+type WorkProofer interface {
+	Proofer
+
+	Work(hash string) (*big.Int, error)
+}
+
+//go:generate mockery --name=AncestryProofer --inpackage --case=underscore --testonly
+
+// AncestryProofer ...
+//
+// It's an optional extension of the [Proofer] interface that validates
+// the block taking into account the preceding blocks not stored yet
+// (e.g., the ones of the incoming fork). The ancestry is ordered
+// from the previous block backward and may be incomplete or empty,
+// so the proofer should look up the rest of the preceding blocks itself.
+type AncestryProofer interface {
+	Proofer
+
+	ValidateWithAncestry(block Block, ancestry BlockGroup) error
+}
+
+//go:generate mockery --name=EncodingProofer --inpackage --case=underscore --testonly
+
+// EncodingProofer ...
+//
+// It's an optional extension of the [Proofer] interface that returns
+// the version of the block encoding the hash is calculated on. It's used
+// for the proofers that don't mark the encoding in the hash itself,
+// otherwise the encoding is detected via the [HashBlockEncoding] function.
+type EncodingProofer interface {
+	Proofer
+
+	BlockEncoding(hash string) BlockEncodingVersion
+}
+
 // BlockDependencies ...
 type BlockDependencies struct {
 	Clock   Clock
@@ -34,6 +92,7 @@ type Block struct {
 	Data      Data
 	Hash      string
 	PrevHash  string
+	Height    int
 }
 
 // NewBlock ...
@@ -62,14 +121,17 @@ type NewBlockExParams struct {
 // NewBlockEx ...
 func NewBlockEx(ctx context.Context, params NewBlockExParams) (Block, error) {
 	var prevHash string
+	var height int
 	if prevBlock, isPresent := params.PrevBlock.Get(); isPresent {
 		prevHash = prevBlock.Hash
+		height = prevBlock.Height + 1
 	}
 
 	block := Block{
 		Timestamp: params.Dependencies.Clock(),
 		Data:      params.Data,
 		PrevHash:  prevHash,
+		Height:    height,
 	}
 
 	var err error
@@ -119,6 +181,10 @@ func NewGenesisBlockEx(
 }
 
 // MergedData ...
+//
+// It's the legacy block representation used by proofers for hashing.
+// It's ambiguous and depends on the time zone of the timestamp,
+// so use [Block.CanonicalData] for new blocks.
 func (block Block) MergedData() string {
 	return block.Timestamp.String() + block.Data.String() + block.PrevHash
 }
@@ -137,6 +203,9 @@ func (block Block) IsEqual(anotherBlock Block) error {
 	if block.PrevHash != anotherBlock.PrevHash {
 		return errors.New("previous hashes are not equal")
 	}
+	if block.Height != anotherBlock.Height {
+		return errors.New("heights are not equal")
+	}
 	return nil
 }
 
@@ -147,17 +216,25 @@ func (block Block) IsValid(prevBlock *Block, proofer Proofer) error {
 		prevTimestamp = prevBlock.Timestamp
 	}
 	if !block.Timestamp.After(prevTimestamp) {
-		return errors.New("the timestamp is not greater than the previous one")
+		return ErrInvalidTimestamp
 	}
 
-	if prevBlock != nil && block.PrevHash != prevBlock.Hash {
-		return errors.New(
-			"the previous hash is not equal to the hash of the previous block",
-		)
+	if prevBlock != nil {
+		if block.PrevHash != prevBlock.Hash {
+			return ErrInvalidPrevHash
+		}
+		// the legacy encoding doesn't include the height,
+		// so the legacy chains store the zero heights
+		isLegacyChain :=
+			blockEncoding(block.Hash, proofer) == LegacyBlockEncoding &&
+				blockEncoding(prevBlock.Hash, proofer) == LegacyBlockEncoding
+		if !isLegacyChain && block.Height != prevBlock.Height+1 {
+			return ErrInvalidHeight
+		}
 	}
 
 	if err := proofer.Validate(block); err != nil {
-		return fmt.Errorf("the validation via the proofer was failed: %w", err)
+		return fmt.Errorf("%w: %w", ErrInvalidProof, err)
 	}
 
 	return nil
@@ -165,5 +242,6 @@ func (block Block) IsValid(prevBlock *Block, proofer Proofer) error {
 
 // IsValidGenesisBlock ...
 func (block Block) IsValidGenesisBlock(proofer Proofer) error {
-	return block.IsValid(&Block{}, proofer)
+	// the genesis block has the zero height
+	return block.IsValid(&Block{Height: -1}, proofer)
 }
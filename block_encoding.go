@@ -0,0 +1,114 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrUnsupportedBlockEncoding ...
+var ErrUnsupportedBlockEncoding = errors.New("unsupported block encoding")
+
+// BlockEncodingVersion ...
+type BlockEncodingVersion int
+
+// ...
+const (
+	// LegacyBlockEncoding corresponds to the [Block.MergedData] method.
+	LegacyBlockEncoding BlockEncodingVersion = iota
+	// BinaryBlockEncodingV1 corresponds to the [Block.CanonicalData] method.
+	BinaryBlockEncodingV1
+
+	LatestBlockEncoding = BinaryBlockEncodingV1
+)
+
+// EncodedData ...
+//
+// It returns the block representation that proofers hash
+// in the specified encoding.
+func (block Block) EncodedData(version BlockEncodingVersion) ([]byte, error) {
+	switch version {
+	case LegacyBlockEncoding:
+		return []byte(block.MergedData()), nil
+	case BinaryBlockEncodingV1:
+		return block.CanonicalData(), nil
+	default:
+		return nil, fmt.Errorf(
+			"block encoding version %d: %w",
+			version,
+			ErrUnsupportedBlockEncoding,
+		)
+	}
+}
+
+// HashBlockEncoding ...
+//
+// It detects the version of the block encoding by the "v<N>:" prefix
+// of the hash. The hashes without the prefix are considered
+// to be in the legacy encoding.
+func HashBlockEncoding(hash string) BlockEncodingVersion {
+	rawVersion, isVersioned := strings.CutPrefix(hash, "v")
+	if !isVersioned {
+		return LegacyBlockEncoding
+	}
+
+	rawVersion, _, isVersioned = strings.Cut(rawVersion, ":")
+	if !isVersioned {
+		return LegacyBlockEncoding
+	}
+
+	version, err := strconv.Atoi(rawVersion)
+	if err != nil {
+		return LegacyBlockEncoding
+	}
+
+	return BlockEncodingVersion(version)
+}
+
+func blockEncoding(hash string, proofer Proofer) BlockEncodingVersion {
+	if encodingProofer, ok := proofer.(EncodingProofer); ok {
+		return encodingProofer.BlockEncoding(hash)
+	}
+
+	return HashBlockEncoding(hash)
+}
+
+// CanonicalData ...
+//
+// It encodes the block to the binary format
+// in the latest version of the block encoding.
+// It doesn't include the block hash.
+//
+// The format is the following (all integers are big-endian):
+//   - encoding version (1 byte);
+//   - timestamp: Unix seconds (8 bytes) and nanoseconds (4 bytes), in UTC;
+//   - height (8 bytes);
+//   - block data: its string representation prefixed by its length (uvarint);
+//   - previous hash: prefixed by its length (uvarint).
+func (block Block) CanonicalData() []byte {
+	data := block.Data.String()
+
+	buffer := make([]byte, 0, 1+8+4+8+2*binary.MaxVarintLen64+
+		len(data)+len(block.PrevHash))
+	buffer = append(buffer, byte(BinaryBlockEncodingV1))
+	buffer = binary.BigEndian.AppendUint64(
+		buffer,
+		uint64(block.Timestamp.Unix()),
+	)
+	buffer = binary.BigEndian.AppendUint32(
+		buffer,
+		uint32(block.Timestamp.Nanosecond()),
+	)
+	buffer = binary.BigEndian.AppendUint64(buffer, uint64(block.Height))
+	buffer = appendLengthPrefixed(buffer, data)
+	buffer = appendLengthPrefixed(buffer, block.PrevHash)
+
+	return buffer
+}
+
+func appendLengthPrefixed(buffer []byte, value string) []byte {
+	buffer = binary.AppendUvarint(buffer, uint64(len(value)))
+	return append(buffer, value...)
+}
@@ -0,0 +1,213 @@
+package blockchain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestBlock_EncodedData(test *testing.T) {
+	type fields struct {
+		Timestamp time.Time
+		Data      Data
+		Hash      string
+		PrevHash  string
+		Height    int
+	}
+	type args struct {
+		version BlockEncodingVersion
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		want    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/legacy block encoding",
+			fields: fields{
+				Timestamp: clock(),
+				Data: func() Data {
+					data := new(MockData)
+					data.On("String").Return("data")
+
+					return data
+				}(),
+				Hash:     "hash",
+				PrevHash: "previous hash",
+				Height:   1,
+			},
+			args: args{
+				version: LegacyBlockEncoding,
+			},
+			want:    []byte("2006-01-02 15:04:05 +0000 UTCdataprevious hash"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/binary block encoding",
+			fields: fields{
+				Timestamp: clock().Add(23 * time.Nanosecond),
+				Data: func() Data {
+					data := new(MockData)
+					data.On("String").Return("data")
+
+					return data
+				}(),
+				Hash:     "hash",
+				PrevHash: "previous hash",
+				Height:   1,
+			},
+			args: args{
+				version: BinaryBlockEncodingV1,
+			},
+			want: append(
+				[]byte{
+					0x01,                                           // version
+					0x00, 0x00, 0x00, 0x00, 0x43, 0xb9, 0x40, 0xe5, // seconds
+					0x00, 0x00, 0x00, 0x17, // nanoseconds
+					0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x01, // height
+					0x04, 'd', 'a', 't', 'a', // data
+					0x0d, // previous hash length
+				},
+				"previous hash"...,
+			),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			fields: fields{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+				Height:    1,
+			},
+			args: args{
+				version: 23,
+			},
+			want: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnsupportedBlockEncoding)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			block := Block{
+				Timestamp: data.fields.Timestamp,
+				Data:      data.fields.Data,
+				Hash:      data.fields.Hash,
+				PrevHash:  data.fields.PrevHash,
+				Height:    data.fields.Height,
+			}
+			got, gotErr := block.EncodedData(data.args.version)
+
+			mock.AssertExpectationsForObjects(test, data.fields.Data)
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestBlock_CanonicalData(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		block        Block
+		anotherBlock Block
+		wantIsEqual  assert.BoolAssertionFunc
+	}{
+		{
+			name: "with timestamps in different time zones",
+			block: Block{
+				Timestamp: clock(),
+				Data:      NewData("data"),
+				PrevHash:  "previous hash",
+			},
+			anotherBlock: Block{
+				Timestamp: clock().In(time.FixedZone("UTC+3", 3*60*60)),
+				Data:      NewData("data"),
+				PrevHash:  "previous hash",
+			},
+			wantIsEqual: assert.True,
+		},
+		{
+			name: "with a shifted boundary between the fields",
+			block: Block{
+				Timestamp: clock(),
+				Data:      NewData("data #"),
+				PrevHash:  "1",
+			},
+			anotherBlock: Block{
+				Timestamp: clock(),
+				Data:      NewData("data "),
+				PrevHash:  "#1",
+			},
+			wantIsEqual: assert.False,
+		},
+		{
+			name: "with different heights",
+			block: Block{
+				Timestamp: clock(),
+				Data:      NewData("data"),
+				PrevHash:  "previous hash",
+				Height:    1,
+			},
+			anotherBlock: Block{
+				Timestamp: clock(),
+				Data:      NewData("data"),
+				PrevHash:  "previous hash",
+				Height:    2,
+			},
+			wantIsEqual: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.block.CanonicalData()
+			gotAnother := data.anotherBlock.CanonicalData()
+
+			data.wantIsEqual(test, string(got) == string(gotAnother))
+		})
+	}
+}
+
+func TestHashBlockEncoding(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want BlockEncodingVersion
+	}{
+		{
+			name: "versioned hash",
+			args: args{hash: "v1:248:23:hash"},
+			want: BinaryBlockEncodingV1,
+		},
+		{
+			name: "legacy hash",
+			args: args{hash: "248:23:hash"},
+			want: LegacyBlockEncoding,
+		},
+		{
+			name: "legacy hash with the prefix-like beginning",
+			args: args{hash: "version:hash"},
+			want: LegacyBlockEncoding,
+		},
+		{
+			name: "legacy hash without the separator",
+			args: args{hash: "v1"},
+			want: LegacyBlockEncoding,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := HashBlockEncoding(data.args.hash)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
@@ -2,9 +2,35 @@ package blockchain
 
 import (
 	"fmt"
+	"math/big"
 	"time"
 )
 
+// BlockValidationError ...
+//
+// It describes the block that failed the validation. The block index
+// is the index in the validated block group.
+type BlockValidationError struct {
+	BlockIndex int
+	BlockHash  string
+	Err        error
+}
+
+// Error ...
+func (err BlockValidationError) Error() string {
+	return fmt.Sprintf(
+		"block #%d (%s) is not valid: %s",
+		err.BlockIndex,
+		err.BlockHash,
+		err.Err,
+	)
+}
+
+// Unwrap ...
+func (err BlockValidationError) Unwrap() error {
+	return err.Err
+}
+
 // ValidationMode ...
 type ValidationMode int
 
@@ -68,6 +94,55 @@ func (blocks BlockGroup) IsLastBlockValid(
 	return err
 }
 
+// IsValidFork ...
+//
+// It validates the blocks as a fork that follows the fork block (i.e.,
+// the common ancestor): the chain linkage, the proofs, and the timestamps,
+// including the ones relative to the fork block. The error is
+// the [BlockValidationError] one describing the first invalid block
+// starting from the fork block.
+//
+// If the proofer implements the [AncestryProofer] interface, the blocks
+// preceding the validated one within the fork are passed as its ancestry.
+func (blocks BlockGroup) IsValidFork(forkBlock Block, proofer Proofer) error {
+	ancestryProofer, isAncestryProofer := proofer.(AncestryProofer)
+	for index := len(blocks) - 1; index >= 0; index-- {
+		prevBlock := &forkBlock
+		if index < len(blocks)-1 {
+			prevBlock = &blocks[index+1]
+		}
+
+		blockProofer := proofer
+		if isAncestryProofer {
+			blockProofer = ancestryBoundProofer{
+				AncestryProofer: ancestryProofer,
+				ancestry:        blocks[index+1:],
+			}
+		}
+
+		if err := blocks[index].IsValid(prevBlock, blockProofer); err != nil {
+			return BlockValidationError{
+				BlockIndex: index,
+				BlockHash:  blocks[index].Hash,
+				Err:        err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// it passes the ancestry to the wrapped proofer on the validation
+type ancestryBoundProofer struct {
+	AncestryProofer
+
+	ancestry BlockGroup
+}
+
+func (proofer ancestryBoundProofer) Validate(block Block) error {
+	return proofer.ValidateWithAncestry(block, proofer.ancestry)
+}
+
 // FindDifferences ...
 func (blocks BlockGroup) FindDifferences(anotherBlocks BlockGroup) (
 	leftIndex int,
@@ -109,6 +184,45 @@ func (blocks BlockGroup) Difficulty(proofer Proofer) (int, error) {
 	return totalDifficulty, nil
 }
 
+// Work ...
+//
+// It sums the work of the blocks if the proofer implements
+// the [WorkProofer] interface, and their difficulties otherwise.
+func (blocks BlockGroup) Work(proofer Proofer) (*big.Int, error) {
+	workProofer, isWorkProofer := proofer.(WorkProofer)
+
+	totalWork := big.NewInt(0)
+	for index, block := range blocks {
+		var work *big.Int
+		if isWorkProofer {
+			var err error
+			work, err = workProofer.Work(block.Hash)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"unable to calculate the work of the block #%d: %w",
+					index,
+					err,
+				)
+			}
+		} else {
+			difficulty, err := proofer.Difficulty(block.Hash)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"unable to calculate the difficulty of the block #%d: %w",
+					index,
+					err,
+				)
+			}
+
+			work = big.NewInt(int64(difficulty))
+		}
+
+		totalWork.Add(totalWork, work)
+	}
+
+	return totalWork, nil
+}
+
 func normalizeTimestamp(timestamp time.Time) time.Time {
 	return timestamp.
 		In(time.UTC). // set the same location for all timestamps
@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"math/big"
 	"testing"
 	"testing/iotest"
 	"time"
@@ -203,6 +204,32 @@ func TestBlockGroup_IsValid(test *testing.T) {
 			},
 			want: assert.Error,
 		},
+		{
+			name: "failure due to heights",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "v1:next hash",
+					PrevHash:  "v1:hash",
+					Height:    0,
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "v1:hash",
+					PrevHash:  "",
+				},
+			},
+			args: args{
+				prependedChunk: nil,
+				validationMode: AsFullBlockchain,
+				proofer:        new(MockProofer),
+			},
+			want: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidHeight)
+			},
+		},
 		{
 			name: "failure due to the block at the end (as a full blockchain)",
 			blocks: BlockGroup{
@@ -546,6 +573,277 @@ func TestBlockGroup_IsLastBlockValid(test *testing.T) {
 	}
 }
 
+func TestBlockGroup_IsValidFork(test *testing.T) {
+	forkBlock := Block{
+		Timestamp: clock(),
+		Data:      new(MockData),
+		Hash:      "hash",
+		PrevHash:  "previous hash",
+	}
+
+	type args struct {
+		forkBlock Block
+		proofer   Proofer
+	}
+
+	for _, data := range []struct {
+		name   string
+		blocks BlockGroup
+		args   args
+		want   assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "success without blocks",
+			blocks: nil,
+			args: args{
+				forkBlock: forkBlock,
+				proofer:   new(MockProofer),
+			},
+			want: assert.NoError,
+		},
+		{
+			name: "success with blocks",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #2",
+					PrevHash:  "next hash #1",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #1",
+					PrevHash:  "hash",
+				},
+			},
+			args: args{
+				forkBlock: forkBlock,
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					for _, block := range (BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "next hash #2",
+							PrevHash:  "next hash #1",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data:      new(MockData),
+							Hash:      "next hash #1",
+							PrevHash:  "hash",
+						},
+					}) {
+						proofer.On("Validate", block).Return(nil)
+					}
+
+					return proofer
+				}(),
+			},
+			want: assert.NoError,
+		},
+		{
+			name: "success with the ancestry proofer",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #2",
+					PrevHash:  "next hash #1",
+					Height:    2,
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #1",
+					PrevHash:  "hash",
+					Height:    1,
+				},
+			},
+			args: args{
+				forkBlock: forkBlock,
+				proofer: func() Proofer {
+					firstBlock := Block{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "next hash #1",
+						PrevHash:  "hash",
+						Height:    1,
+					}
+					secondBlock := Block{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      new(MockData),
+						Hash:      "next hash #2",
+						PrevHash:  "next hash #1",
+						Height:    2,
+					}
+
+					proofer := new(MockAncestryProofer)
+					proofer.
+						On("ValidateWithAncestry", firstBlock, BlockGroup{}).
+						Return(nil)
+					proofer.
+						On("ValidateWithAncestry", secondBlock, BlockGroup{firstBlock}).
+						Return(nil)
+
+					return proofer
+				}(),
+			},
+			want: assert.NoError,
+		},
+		{
+			name: "error with the block not linked to the fork block",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #2",
+					PrevHash:  "next hash #1",
+					Height:    2,
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #1",
+					PrevHash:  "another hash",
+				},
+			},
+			args: args{
+				forkBlock: forkBlock,
+				proofer:   new(MockProofer),
+			},
+			want: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.Equal(test, BlockValidationError{
+					BlockIndex: 1,
+					BlockHash:  "next hash #1",
+					Err:        ErrInvalidPrevHash,
+				}, err, msgAndArgs...)
+			},
+		},
+		{
+			name: "error with the block not next to the fork block by height",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "v1:next hash #2",
+					PrevHash:  "v1:next hash #1",
+					Height:    1,
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "v1:next hash #1",
+					PrevHash:  "hash",
+					Height:    0,
+				},
+			},
+			args: args{
+				forkBlock: forkBlock,
+				proofer:   new(MockProofer),
+			},
+			want: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.Equal(test, BlockValidationError{
+					BlockIndex: 1,
+					BlockHash:  "v1:next hash #1",
+					Err:        ErrInvalidHeight,
+				}, err, msgAndArgs...)
+			},
+		},
+		{
+			name: "error with the invalid block",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #2",
+					PrevHash:  "next hash #1",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash #1",
+					PrevHash:  "hash",
+				},
+			},
+			args: args{
+				forkBlock: forkBlock,
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.
+						On("Validate", Block{
+							Timestamp: clock().Add(time.Hour),
+							Data:      new(MockData),
+							Hash:      "next hash #1",
+							PrevHash:  "hash",
+						}).
+						Return(nil)
+					proofer.
+						On("Validate", Block{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "next hash #2",
+							PrevHash:  "next hash #1",
+						}).
+						Return(iotest.ErrTimeout)
+
+					return proofer
+				}(),
+			},
+			want: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				var validationErr BlockValidationError
+				return assert.ErrorAs(test, err, &validationErr, msgAndArgs...) &&
+					assert.Equal(test, 0, validationErr.BlockIndex, msgAndArgs...) &&
+					assert.ErrorIs(test, err, ErrInvalidProof, msgAndArgs...) &&
+					assert.ErrorIs(test, err, iotest.ErrTimeout, msgAndArgs...)
+			},
+		},
+		{
+			name: "error with the timestamp not greater than the fork block one",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "next hash #1",
+					PrevHash:  "hash",
+					Height:    1,
+				},
+			},
+			args: args{
+				forkBlock: forkBlock,
+				proofer:   new(MockProofer),
+			},
+			want: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTimestamp, msgAndArgs...)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := data.blocks.IsValidFork(data.args.forkBlock, data.args.proofer)
+
+			mock.AssertExpectationsForObjects(test, data.args.proofer)
+			data.want(test, err)
+		})
+	}
+}
+
 func TestBlockGroup_FindDifferences(test *testing.T) {
 	type args struct {
 		anotherBlocks BlockGroup
@@ -959,3 +1257,148 @@ func TestBlockGroup_Difficulty(test *testing.T) {
 		})
 	}
 }
+
+func TestBlockGroup_Work(test *testing.T) {
+	type args struct {
+		proofer Proofer
+	}
+
+	for _, data := range []struct {
+		name     string
+		blocks   BlockGroup
+		args     args
+		wantWork *big.Int
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:   "success without blocks",
+			blocks: nil,
+			args: args{
+				proofer: new(MockWorkProofer),
+			},
+			wantWork: big.NewInt(0),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success with blocks/regular proofer",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash",
+					PrevHash:  "hash",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "",
+				},
+			},
+			args: args{
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "next hash").Return(42, nil)
+					proofer.On("Difficulty", "hash").Return(23, nil)
+
+					return proofer
+				}(),
+			},
+			wantWork: big.NewInt(65),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "success with blocks/work proofer",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash",
+					PrevHash:  "hash",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "",
+				},
+			},
+			args: args{
+				proofer: func() Proofer {
+					proofer := new(MockWorkProofer)
+					proofer.On("Work", "next hash").Return(big.NewInt(1<<42), nil)
+					proofer.On("Work", "hash").Return(big.NewInt(1<<23), nil)
+
+					return proofer
+				}(),
+			},
+			wantWork: big.NewInt(1<<42 + 1<<23),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "error/regular proofer",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash",
+					PrevHash:  "hash",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "",
+				},
+			},
+			args: args{
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "next hash").Return(0, iotest.ErrTimeout)
+
+					return proofer
+				}(),
+			},
+			wantWork: nil,
+			wantErr:  assert.Error,
+		},
+		{
+			name: "error/work proofer",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "next hash",
+					PrevHash:  "hash",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "",
+				},
+			},
+			args: args{
+				proofer: func() Proofer {
+					proofer := new(MockWorkProofer)
+					proofer.On("Work", "next hash").Return(nil, iotest.ErrTimeout)
+
+					return proofer
+				}(),
+			},
+			wantWork: nil,
+			wantErr:  assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotWork, gotErr := data.blocks.Work(data.args.proofer)
+
+			for _, block := range data.blocks {
+				mock.AssertExpectationsForObjects(test, block.Data)
+			}
+			mock.AssertExpectationsForObjects(test, data.args.proofer)
+			assert.Equal(test, data.wantWork, gotWork)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package blockchain
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const blockBinaryFormatVersion byte = 1
+
+type blockJSON struct {
+	Timestamp time.Time
+	Data      *dataJSON
+	Hash      string
+	PrevHash  string
+	Height    int
+}
+
+type dataJSON struct {
+	Type   string
+	Text   *string `json:",omitempty"`
+	Binary []byte  `json:",omitempty"`
+}
+
+// MarshalBinary ...
+//
+// The block data is encoded via the [EncodeData] function.
+func (block Block) MarshalBinary() ([]byte, error) {
+	timestamp, err := block.Timestamp.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the timestamp: %w", err)
+	}
+
+	var data []byte
+	if block.Data != nil {
+		if data, err = EncodeData(block.Data); err != nil {
+			return nil, fmt.Errorf("unable to encode the data: %w", err)
+		}
+	}
+
+	buffer := []byte{blockBinaryFormatVersion}
+	buffer = appendLengthPrefixed(buffer, string(timestamp))
+	buffer = appendLengthPrefixed(buffer, string(data))
+	buffer = appendLengthPrefixed(buffer, block.Hash)
+	buffer = appendLengthPrefixed(buffer, block.PrevHash)
+	buffer = binary.AppendVarint(buffer, int64(block.Height))
+
+	return buffer, nil
+}
+
+// UnmarshalBinary ...
+//
+// The block data is decoded via the [DecodeData] function.
+func (block *Block) UnmarshalBinary(rawBlock []byte) error {
+	if len(rawBlock) == 0 || rawBlock[0] != blockBinaryFormatVersion {
+		return errors.New("unsupported binary format version")
+	}
+	rawBlock = rawBlock[1:]
+
+	var fields [4][]byte
+	for index := range fields {
+		var err error
+		if fields[index], rawBlock, err = readLengthPrefixed(rawBlock); err != nil {
+			return fmt.Errorf("unable to read field #%d: %w", index, err)
+		}
+	}
+
+	height, heightSize := binary.Varint(rawBlock)
+	if heightSize <= 0 {
+		return errors.New("unable to read the height")
+	}
+	if len(rawBlock) != heightSize {
+		return errors.New("the block contains extra bytes")
+	}
+
+	var timestamp time.Time
+	if err := timestamp.UnmarshalBinary(fields[0]); err != nil {
+		return fmt.Errorf("unable to unmarshal the timestamp: %w", err)
+	}
+
+	var data Data
+	if len(fields[1]) != 0 {
+		var err error
+		if data, err = DecodeData(fields[1]); err != nil {
+			return fmt.Errorf("unable to decode the data: %w", err)
+		}
+	}
+
+	*block = Block{
+		Timestamp: timestamp,
+		Data:      data,
+		Hash:      string(fields[2]),
+		PrevHash:  string(fields[3]),
+		Height:    int(height),
+	}
+	return nil
+}
+
+// MarshalJSON ...
+//
+// The block data is represented by its type and its payload: the text one
+// or the binary one, depending on how the data was marshalled
+// (see the [TypedData] interface).
+func (block Block) MarshalJSON() ([]byte, error) {
+	var data *dataJSON
+	if block.Data != nil {
+		typedData, err := encodeTypedData(block.Data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode the data: %w", err)
+		}
+
+		data = &dataJSON{Type: typedData.dataType}
+		if typedData.isBinary {
+			data.Binary = typedData.payload
+		} else {
+			text := string(typedData.payload)
+			data.Text = &text
+		}
+	}
+
+	return json.Marshal(blockJSON{
+		Timestamp: block.Timestamp,
+		Data:      data,
+		Hash:      block.Hash,
+		PrevHash:  block.PrevHash,
+		Height:    block.Height,
+	})
+}
+
+// UnmarshalJSON ...
+func (block *Block) UnmarshalJSON(rawBlock []byte) error {
+	var unmarshalledBlock blockJSON
+	if err := json.Unmarshal(rawBlock, &unmarshalledBlock); err != nil {
+		return err
+	}
+
+	var data Data
+	if unmarshalledBlock.Data != nil {
+		payload := unmarshalledBlock.Data.Binary
+		if unmarshalledBlock.Data.Text != nil {
+			payload = []byte(*unmarshalledBlock.Data.Text)
+		}
+
+		var err error
+		data, err = decodeTypedData(unmarshalledBlock.Data.Type, payload)
+		if err != nil {
+			return fmt.Errorf("unable to decode the data: %w", err)
+		}
+	}
+
+	*block = Block{
+		Timestamp: unmarshalledBlock.Timestamp,
+		Data:      data,
+		Hash:      unmarshalledBlock.Hash,
+		PrevHash:  unmarshalledBlock.PrevHash,
+		Height:    unmarshalledBlock.Height,
+	}
+	return nil
+}
+
+// MarshalBinary ...
+//
+// The blocks are encoded via the [Block.MarshalBinary] method.
+func (blocks BlockGroup) MarshalBinary() ([]byte, error) {
+	buffer := binary.AppendUvarint(nil, uint64(len(blocks)))
+	for index, block := range blocks {
+		rawBlock, err := block.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal block #%d: %w", index, err)
+		}
+
+		buffer = appendLengthPrefixed(buffer, string(rawBlock))
+	}
+
+	return buffer, nil
+}
+
+// UnmarshalBinary ...
+func (blocks *BlockGroup) UnmarshalBinary(rawBlocks []byte) error {
+	blockCount, blockCountSize := binary.Uvarint(rawBlocks)
+	if blockCountSize <= 0 {
+		return errors.New("unable to read the block count")
+	}
+
+	rawBlocks = rawBlocks[blockCountSize:]
+	if blockCount > uint64(len(rawBlocks)) {
+		return errors.New("the block count exceeds the data size")
+	}
+
+	var unmarshalledBlocks BlockGroup
+	for index := 0; index < int(blockCount); index++ {
+		var rawBlock []byte
+		var err error
+		if rawBlock, rawBlocks, err = readLengthPrefixed(rawBlocks); err != nil {
+			return fmt.Errorf("unable to read block #%d: %w", index, err)
+		}
+
+		var block Block
+		if err := block.UnmarshalBinary(rawBlock); err != nil {
+			return fmt.Errorf("unable to unmarshal block #%d: %w", index, err)
+		}
+
+		unmarshalledBlocks = append(unmarshalledBlocks, block)
+	}
+	if len(rawBlocks) != 0 {
+		return errors.New("the block group contains extra bytes")
+	}
+
+	*blocks = unmarshalledBlocks
+	return nil
+}
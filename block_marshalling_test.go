@@ -0,0 +1,316 @@
+package blockchain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlock_MarshalBinary(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		block Block
+	}{
+		{
+			name: "with the text data",
+			block: Block{
+				Timestamp: clock(),
+				Data:      NewData("data"),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+				Height:    1,
+			},
+		},
+		{
+			name: "with the typed data",
+			block: Block{
+				Timestamp: clock().In(time.FixedZone("", 3*60*60)),
+				Data: NewMerkleData([]Data{
+					NewData("one"),
+					testTypedData{value: 23},
+				}),
+				Hash:     "hash",
+				PrevHash: "previous hash",
+				Height:   1,
+			},
+		},
+		{
+			name: "without data",
+			block: Block{
+				Timestamp: clock(),
+				Data:      nil,
+				Hash:      "hash",
+				PrevHash:  "",
+				Height:    0,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			rawBlock, err := data.block.MarshalBinary()
+			require.NoError(test, err)
+
+			var gotBlock Block
+			gotErr := gotBlock.UnmarshalBinary(rawBlock)
+
+			assert.Equal(test, data.block, gotBlock)
+			assert.NoError(test, gotErr)
+		})
+	}
+}
+
+func TestBlock_UnmarshalBinary_withError(test *testing.T) {
+	rawBlock, err := Block{
+		Timestamp: clock(),
+		Data:      NewData("data"),
+		Hash:      "hash",
+		PrevHash:  "previous hash",
+		Height:    1,
+	}.MarshalBinary()
+	require.NoError(test, err)
+
+	for _, data := range []struct {
+		name     string
+		rawBlock []byte
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "with an empty block",
+			rawBlock: nil,
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "with an unsupported format version",
+			rawBlock: append([]byte{23}, rawBlock[1:]...),
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "with a truncated block",
+			rawBlock: rawBlock[:len(rawBlock)-2],
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "with extra bytes",
+			rawBlock: append(append([]byte(nil), rawBlock...), 0x00),
+			wantErr:  assert.Error,
+		},
+		{
+			name: "with an unknown data type",
+			rawBlock: func() []byte {
+				timestamp, err := clock().MarshalBinary()
+				require.NoError(test, err)
+
+				rawBlock := []byte{blockBinaryFormatVersion}
+				rawBlock = appendLengthPrefixed(rawBlock, string(timestamp))
+				rawBlock = appendLengthPrefixed(rawBlock, "\x07unknowndata")
+				rawBlock = appendLengthPrefixed(rawBlock, "hash")
+				rawBlock = appendLengthPrefixed(rawBlock, "previous hash")
+				rawBlock = append(rawBlock, 0x02) // height
+				return rawBlock
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnknownDataType)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var block Block
+			err := block.UnmarshalBinary(data.rawBlock)
+
+			assert.Equal(test, Block{}, block)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestBlock_MarshalJSON(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		block    Block
+		wantJSON string
+	}{
+		{
+			name: "with the text data",
+			block: Block{
+				Timestamp: clock(),
+				Data:      NewData("data"),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+				Height:    1,
+			},
+			wantJSON: `{
+				"Timestamp": "2006-01-02T15:04:05Z",
+				"Data": {"Type": "text", "Text": "data"},
+				"Hash": "hash",
+				"PrevHash": "previous hash",
+				"Height": 1
+			}`,
+		},
+		{
+			name: "with the binary data",
+			block: Block{
+				Timestamp: clock(),
+				Data:      NewMerkleData([]Data{NewData("one")}),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+				Height:    1,
+			},
+			wantJSON: `{
+				"Timestamp": "2006-01-02T15:04:05Z",
+				"Data": {"Type": "merkle", "Binary": "AQgEdGV4dG9uZQ=="},
+				"Hash": "hash",
+				"PrevHash": "previous hash",
+				"Height": 1
+			}`,
+		},
+		{
+			name: "without data",
+			block: Block{
+				Timestamp: clock(),
+				Data:      nil,
+				Hash:      "hash",
+				PrevHash:  "",
+				Height:    0,
+			},
+			wantJSON: `{
+				"Timestamp": "2006-01-02T15:04:05Z",
+				"Data": null,
+				"Hash": "hash",
+				"PrevHash": "",
+				"Height": 0
+			}`,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			rawBlock, err := json.Marshal(data.block)
+			require.NoError(test, err)
+			assert.JSONEq(test, data.wantJSON, string(rawBlock))
+
+			var gotBlock Block
+			gotErr := json.Unmarshal(rawBlock, &gotBlock)
+
+			assert.Equal(test, data.block, gotBlock)
+			assert.NoError(test, gotErr)
+		})
+	}
+}
+
+func TestBlock_UnmarshalJSON_withError(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		rawBlock string
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "with an invalid JSON",
+			rawBlock: `{"Timestamp": 23}`,
+			wantErr:  assert.Error,
+		},
+		{
+			name:     "with an unknown data type",
+			rawBlock: `{"Data": {"Type": "unknown", "Text": "data"}}`,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnknownDataType)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var block Block
+			err := json.Unmarshal([]byte(data.rawBlock), &block)
+
+			assert.Equal(test, Block{}, block)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestBlockGroup_MarshalBinary(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		blocks BlockGroup
+	}{
+		{
+			name: "nonempty",
+			blocks: BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      testTypedData{value: 23},
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+					Height:    1,
+				},
+				{
+					Timestamp: clock(),
+					Data:      NewData("data"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+					Height:    0,
+				},
+			},
+		},
+		{
+			name:   "empty",
+			blocks: nil,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			rawBlocks, err := data.blocks.MarshalBinary()
+			require.NoError(test, err)
+
+			var gotBlocks BlockGroup
+			gotErr := gotBlocks.UnmarshalBinary(rawBlocks)
+
+			assert.Equal(test, data.blocks, gotBlocks)
+			assert.NoError(test, gotErr)
+		})
+	}
+}
+
+func TestBlockGroup_UnmarshalBinary_withError(test *testing.T) {
+	rawBlocks, err := BlockGroup{
+		{
+			Timestamp: clock(),
+			Data:      NewData("data"),
+			Hash:      "hash",
+			PrevHash:  "",
+			Height:    0,
+		},
+	}.MarshalBinary()
+	require.NoError(test, err)
+
+	for _, data := range []struct {
+		name      string
+		rawBlocks []byte
+	}{
+		{
+			name:      "with an empty block group",
+			rawBlocks: nil,
+		},
+		{
+			name:      "with a too large block count",
+			rawBlocks: []byte{0x17},
+		},
+		{
+			name:      "with a truncated block",
+			rawBlocks: rawBlocks[:len(rawBlocks)-2],
+		},
+		{
+			name:      "with an invalid block",
+			rawBlocks: []byte{0x01, 0x02, 0x17, 0x00},
+		},
+		{
+			name:      "with extra bytes",
+			rawBlocks: append(append([]byte(nil), rawBlocks...), 0x00),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var blocks BlockGroup
+			err := blocks.UnmarshalBinary(data.rawBlocks)
+
+			assert.Nil(test, blocks)
+			assert.Error(test, err)
+		})
+	}
+}
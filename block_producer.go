@@ -0,0 +1,249 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/samber/mo"
+)
+
+// ErrEmptyMempool ...
+var ErrEmptyMempool = errors.New("empty mempool")
+
+// BlockProducerParams ...
+//
+// The batch size is unlimited by default.
+type BlockProducerParams struct {
+	Blockchain   *Blockchain
+	Mempool      *Mempool
+	MaxBatchSize mo.Option[int]
+}
+
+// BlockProducer ...
+//
+// It batches the pending data from the mempool into the next block
+// as the [MerkleData] items. The batched data is taken from the mempool
+// for the time of the mining and returned to it if the block isn't added,
+// so concurrent productions never batch the same data. The invalid data
+// is dropped instead (see [BlockProducer.ProduceBlock]).
+//
+// It's safe for concurrent use.
+type BlockProducer struct {
+	params BlockProducerParams
+}
+
+// NewBlockProducer ...
+func NewBlockProducer(params BlockProducerParams) (*BlockProducer, error) {
+	if maxBatchSize, isPresent := params.MaxBatchSize.Get(); isPresent &&
+		maxBatchSize <= 0 {
+		return nil, fmt.Errorf(
+			"the maximal batch size %d should be positive",
+			maxBatchSize,
+		)
+	}
+
+	return &BlockProducer{params: params}, nil
+}
+
+// ProduceBlock ...
+//
+// If the state engine from the dependencies of the blockchain implements
+// the [ValidatingStateEngine] interface, the taken data items are checked
+// against it in their order, as if they were batched into the next block.
+// The first rejected item is found via the binary search, because
+// the prefixes of the accepted items are accepted too, so the batch
+// is checked by a logarithmic quantity of validations per rejected item.
+//
+// The items rejected with the [ErrInvalidData] error are dropped
+// from the mempool, so they can't block the production. The other
+// rejected items (e.g., the ones depending on the pending data) are returned
+// to the mempool after the batching. If all the taken items are rejected,
+// the next ones are taken.
+//
+// If there is no pending data, it returns the [ErrEmptyMempool] error.
+func (producer *BlockProducer) ProduceBlock(ctx context.Context) error {
+	takenItems, err := producer.takeAcceptedItems()
+	if err != nil {
+		return err
+	}
+
+	takenData := make([]Data, 0, len(takenItems))
+	for _, item := range takenItems {
+		takenData = append(takenData, item.data)
+	}
+
+	if err := producer.params.Blockchain.AddBlockEx(
+		ctx,
+		NewMerkleData(takenData),
+	); err != nil {
+		producer.params.Mempool.putBack(takenItems)
+		return fmt.Errorf("unable to add the block: %w", err)
+	}
+
+	return nil
+}
+
+// Merge ...
+//
+// It merges the blockchain with another one (see [Blockchain.Merge]).
+// If the fork is replaced, the data of the added blocks is removed
+// from the mempool, and the data of the orphaned blocks is returned to it
+// (unless it's included in the added blocks).
+func (producer *BlockProducer) Merge(loader Loader, chunkSize int) error {
+	foundFork, isReplaced, err :=
+		producer.params.Blockchain.merge(loader, chunkSize)
+	if replacedFork, isPresent := foundFork.Get(); isPresent && isReplaced {
+		addedData := collectBlockData(replacedFork.rightDifferences)
+		producer.params.Mempool.Remove(addedData)
+
+		var orphanedData []Data
+		for _, data := range collectBlockData(replacedFork.leftDifferences) {
+			if !containsData(addedData, data) {
+				orphanedData = append(orphanedData, data)
+			}
+		}
+		producer.params.Mempool.restore(orphanedData)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to merge the blockchain: %w", err)
+	}
+
+	return nil
+}
+
+func (producer *BlockProducer) takeAcceptedItems() ([]mempoolItem, error) {
+	var retainedItems []mempoolItem
+	defer func() {
+		producer.params.Mempool.putBack(retainedItems)
+	}()
+
+	for {
+		takenItems := producer.params.Mempool.take(
+			producer.params.MaxBatchSize.OrEmpty(),
+		)
+		if len(takenItems) == 0 {
+			return nil, ErrEmptyMempool
+		}
+
+		acceptedItems, rejectedItems, err := producer.checkItems(takenItems)
+		if err != nil {
+			retainedItems = append(retainedItems, takenItems...)
+			return nil, fmt.Errorf("unable to check the pending data: %w", err)
+		}
+
+		retainedItems = append(retainedItems, rejectedItems...)
+		if len(acceptedItems) != 0 {
+			return acceptedItems, nil
+		}
+	}
+}
+
+// it returns the rejected items that should be retained in the mempool;
+// the rejected items are excluded from the checks of the subsequent ones;
+// the checked block isn't mined, so it has no hash
+func (producer *BlockProducer) checkItems(items []mempoolItem) (
+	acceptedItems []mempoolItem,
+	retainedItems []mempoolItem,
+	err error,
+) {
+	blockchain := producer.params.Blockchain
+	stateEngine, ok :=
+		blockchain.dependencies.StateEngine.OrEmpty().(ValidatingStateEngine)
+	if !ok {
+		return items, nil, nil
+	}
+
+	blockchain.lock.RLock()
+	defer blockchain.lock.RUnlock()
+
+	var acceptedData []Data
+	makeBlock := func(items []mempoolItem) (Block, error) {
+		data := slices.Clone(acceptedData)
+		for _, item := range items {
+			data = append(data, item.data)
+		}
+
+		blockData, err := blockchain.prependCoinbase(
+			NewMerkleData(data),
+			blockchain.lastBlock.Height+1,
+		)
+		if err != nil {
+			return Block{}, fmt.Errorf("unable to prepend the coinbase: %w", err)
+		}
+
+		return Block{
+			Data:     blockData,
+			PrevHash: blockchain.lastBlock.Hash,
+			Height:   blockchain.lastBlock.Height + 1,
+		}, nil
+	}
+
+	for len(items) != 0 {
+		block, err := makeBlock(items)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		validationErr := stateEngine.ValidateBlock(block)
+		if validationErr == nil {
+			acceptedItems = append(acceptedItems, items...)
+			break
+		}
+
+		// the prefix of the low quantity of the items is accepted,
+		// and the item with the high index is rejected
+		low, high := 0, len(items)-1
+		for low < high {
+			middle := low + (high-low)/2
+
+			block, err := makeBlock(items[:middle+1])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if err := stateEngine.ValidateBlock(block); err != nil {
+				high = middle
+				validationErr = err
+
+				continue
+			}
+
+			low = middle + 1
+		}
+
+		acceptedItems = append(acceptedItems, items[:low]...)
+		for _, item := range items[:low] {
+			acceptedData = append(acceptedData, item.data)
+		}
+		if !errors.Is(validationErr, ErrInvalidData) {
+			retainedItems = append(retainedItems, items[low])
+		}
+
+		items = items[low+1:]
+	}
+
+	return acceptedItems, retainedItems, nil
+}
+
+// the block groups are ordered from the last block to the first one,
+// so the blocks are traversed in reverse to collect the data in the order
+// of its inclusion; the coinbases are skipped
+func collectBlockData(blocks BlockGroup) []Data {
+	var data []Data
+	for index := len(blocks) - 1; index >= 0; index-- {
+		blockData := []Data{blocks[index].Data}
+		if merkleData, ok := blocks[index].Data.(MerkleData); ok {
+			blockData = merkleData.Items()
+		}
+
+		for _, dataItem := range blockData {
+			if _, isCoinbase := dataItem.(CoinbaseData); !isCoinbase {
+				data = append(data, dataItem)
+			}
+		}
+	}
+
+	return data
+}
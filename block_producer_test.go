@@ -0,0 +1,776 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBlockProducer(test *testing.T) {
+	type args struct {
+		params BlockProducerParams
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success without the maximal batch size",
+			args: args{
+				params: BlockProducerParams{},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the maximal batch size",
+			args: args{
+				params: BlockProducerParams{
+					MaxBatchSize: mo.Some(23),
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				params: BlockProducerParams{
+					MaxBatchSize: mo.Some(0),
+				},
+			},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			_, err := NewBlockProducer(data.args.params)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestBlockProducer_ProduceBlock(test *testing.T) {
+	type fields struct {
+		dependencies Dependencies
+		pendingData  []Data
+		maxBatchSize mo.Option[int]
+	}
+
+	for _, data := range []struct {
+		name          string
+		fields        fields
+		wantPending   []Data
+		wantLastBlock Block
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock: clock,
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.
+								On("HashEx", mock.Anything, Block{
+									Timestamp: clock(),
+									Data: NewMerkleData([]Data{
+										NewData("one"),
+										NewData("two"),
+									}),
+									PrevHash: "hash",
+									Height:   1,
+								}).
+								Return("next hash", nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						storage := new(MockGroupStorage)
+						storage.
+							On("StoreBlock", Block{
+								Timestamp: clock(),
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("two"),
+								}),
+								Hash:     "next hash",
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+
+						return storage
+					}(),
+				},
+				pendingData:  []Data{NewData("one"), NewData("two"), NewData("three")},
+				maxBatchSize: mo.Some(2),
+			},
+			wantPending: []Data{NewData("three")},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data: NewMerkleData([]Data{
+					NewData("one"),
+					NewData("two"),
+				}),
+				Hash:     "next hash",
+				PrevHash: "hash",
+				Height:   1,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the invalid data dropped",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock: clock,
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.
+								On("HashEx", mock.Anything, Block{
+									Timestamp: clock(),
+									Data: NewMerkleData([]Data{
+										NewData("one"),
+										NewData("three"),
+									}),
+									PrevHash: "hash",
+									Height:   1,
+								}).
+								Return("next hash", nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						storage := new(MockGroupStorage)
+						storage.
+							On("StoreBlock", Block{
+								Timestamp: clock(),
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("three"),
+								}),
+								Hash:     "next hash",
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+
+						return storage
+					}(),
+					StateEngine: mo.Some[StateEngine](func() StateEngine {
+						stateEngine := new(MockValidatingStateEngine)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("two"),
+									NewData("three"),
+								}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(errors.Join(iotest.ErrTimeout, ErrInvalidData))
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("two"),
+								}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(errors.Join(iotest.ErrTimeout, ErrInvalidData))
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data:     NewMerkleData([]Data{NewData("one")}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("three"),
+								}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+						stateEngine.
+							On("ApplyBlock", Block{
+								Timestamp: clock(),
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("three"),
+								}),
+								Hash:     "next hash",
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+
+						return stateEngine
+					}()),
+				},
+				pendingData:  []Data{NewData("one"), NewData("two"), NewData("three")},
+				maxBatchSize: mo.None[int](),
+			},
+			wantPending: []Data{},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data: NewMerkleData([]Data{
+					NewData("one"),
+					NewData("three"),
+				}),
+				Hash:     "next hash",
+				PrevHash: "hash",
+				Height:   1,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the rejected data retained",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock: clock,
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.
+								On("HashEx", mock.Anything, Block{
+									Timestamp: clock(),
+									Data: NewMerkleData([]Data{
+										NewData("one"),
+										NewData("three"),
+									}),
+									PrevHash: "hash",
+									Height:   1,
+								}).
+								Return("next hash", nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						storage := new(MockGroupStorage)
+						storage.
+							On("StoreBlock", Block{
+								Timestamp: clock(),
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("three"),
+								}),
+								Hash:     "next hash",
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+
+						return storage
+					}(),
+					StateEngine: mo.Some[StateEngine](func() StateEngine {
+						stateEngine := new(MockValidatingStateEngine)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("two"),
+									NewData("three"),
+								}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(iotest.ErrTimeout)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("two"),
+								}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(iotest.ErrTimeout)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data:     NewMerkleData([]Data{NewData("one")}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("three"),
+								}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+						stateEngine.
+							On("ApplyBlock", Block{
+								Timestamp: clock(),
+								Data: NewMerkleData([]Data{
+									NewData("one"),
+									NewData("three"),
+								}),
+								Hash:     "next hash",
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(nil)
+
+						return stateEngine
+					}()),
+				},
+				pendingData:  []Data{NewData("one"), NewData("two"), NewData("three")},
+				maxBatchSize: mo.None[int](),
+			},
+			wantPending: []Data{NewData("two")},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data: NewMerkleData([]Data{
+					NewData("one"),
+					NewData("three"),
+				}),
+				Hash:     "next hash",
+				PrevHash: "hash",
+				Height:   1,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/empty mempool",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock:   clock,
+						Proofer: new(MockProofer),
+					},
+					Storage: new(MockGroupStorage),
+				},
+				pendingData:  nil,
+				maxBatchSize: mo.None[int](),
+			},
+			wantPending: []Data{},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      NewData("genesis"),
+				Hash:      "hash",
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrEmptyMempool)
+			},
+		},
+		{
+			name: "error/empty mempool after rejecting the data",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock:   clock,
+						Proofer: new(MockProofer),
+					},
+					Storage: new(MockGroupStorage),
+					StateEngine: mo.Some[StateEngine](func() StateEngine {
+						stateEngine := new(MockValidatingStateEngine)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data:     NewMerkleData([]Data{NewData("one")}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(iotest.ErrTimeout)
+						stateEngine.
+							On("ValidateBlock", Block{
+								Data:     NewMerkleData([]Data{NewData("two")}),
+								PrevHash: "hash",
+								Height:   1,
+							}).
+							Return(iotest.ErrTimeout)
+
+						return stateEngine
+					}()),
+				},
+				pendingData:  []Data{NewData("one"), NewData("two")},
+				maxBatchSize: mo.Some(1),
+			},
+			wantPending: []Data{NewData("one"), NewData("two")},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      NewData("genesis"),
+				Hash:      "hash",
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrEmptyMempool)
+			},
+		},
+		{
+			name: "error/unable to add the block",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock: clock,
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.
+								On("HashEx", mock.Anything, Block{
+									Timestamp: clock(),
+									Data: NewMerkleData([]Data{
+										NewData("one"),
+										NewData("two"),
+										NewData("three"),
+									}),
+									PrevHash: "hash",
+									Height:   1,
+								}).
+								Return("", iotest.ErrTimeout)
+
+							return proofer
+						}(),
+					},
+					Storage: new(MockGroupStorage),
+				},
+				pendingData:  []Data{NewData("one"), NewData("two"), NewData("three")},
+				maxBatchSize: mo.None[int](),
+			},
+			wantPending: []Data{NewData("one"), NewData("two"), NewData("three")},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      NewData("genesis"),
+				Hash:      "hash",
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			blockchain := &Blockchain{
+				dependencies: data.fields.dependencies,
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      NewData("genesis"),
+					Hash:      "hash",
+				},
+			}
+
+			mempool, err := NewMempool(MempoolParams{})
+			require.NoError(test, err)
+
+			for _, dataItem := range data.fields.pendingData {
+				err := mempool.Add(dataItem)
+				require.NoError(test, err)
+			}
+
+			producer, err := NewBlockProducer(BlockProducerParams{
+				Blockchain:   blockchain,
+				Mempool:      mempool,
+				MaxBatchSize: data.fields.maxBatchSize,
+			})
+			require.NoError(test, err)
+
+			gotErr := producer.ProduceBlock(context.Background())
+
+			mock.AssertExpectationsForObjects(
+				test,
+				data.fields.dependencies.Proofer,
+				data.fields.dependencies.Storage,
+			)
+			if stateEngine, isPresent :=
+				data.fields.dependencies.StateEngine.Get(); isPresent {
+				mock.AssertExpectationsForObjects(test, stateEngine)
+			}
+			assert.Equal(test, data.wantPending, mempool.Pending(0))
+			assert.Equal(test, data.wantLastBlock, blockchain.lastBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestBlockProducer_Merge(test *testing.T) {
+	type fields struct {
+		dependencies Dependencies
+		pendingData  []Data
+	}
+	type args struct {
+		loader    Loader
+		chunkSize int
+	}
+
+	for _, data := range []struct {
+		name        string
+		fields      fields
+		args        args
+		wantPending []Data
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with replacing the fork",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #2.1").Return(23, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      NewMerkleData([]Data{NewData("four")}),
+									Hash:      "hash #3.2",
+									PrevHash:  "hash #2.2",
+									Height:    2,
+								}).
+								Return(nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(time.Hour),
+									Data: NewMerkleData([]Data{
+										NewData("two"),
+										NewData("three"),
+									}),
+									Hash:     "hash #2.2",
+									PrevHash: "hash #1",
+									Height:   1,
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #2.2").Return(23, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						coinbase := new(MockCoinbaseData)
+						coinbase.On("String").Return("coinbase")
+
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data: NewMerkleData([]Data{
+									coinbase,
+									NewData("one"),
+									NewData("two"),
+								}),
+								Hash:     "hash #2.1",
+								PrevHash: "hash #1",
+								Height:   1,
+							},
+							{
+								Timestamp: clock(),
+								Data:      NewData("genesis"),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+						newLastBlock := Block{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      NewMerkleData([]Data{NewData("four")}),
+							Hash:      "hash #3.2",
+							PrevHash:  "hash #2.2",
+							Height:    2,
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.On("DeleteBlockGroup", blocks[:1]).Return(nil)
+						storage.
+							On("StoreBlockGroup", mock.AnythingOfType("blockchain.BlockGroup")).
+							Return(nil)
+						storage.On("LoadLastBlock").Return(newLastBlock, nil)
+
+						return storage
+					}(),
+				},
+				pendingData: []Data{NewData("three"), NewData("five")},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      NewMerkleData([]Data{NewData("four")}),
+							Hash:      "hash #3.2",
+							PrevHash:  "hash #2.2",
+							Height:    2,
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: NewMerkleData([]Data{
+								NewData("two"),
+								NewData("three"),
+							}),
+							Hash:     "hash #2.2",
+							PrevHash: "hash #1",
+							Height:   1,
+						},
+						{
+							Timestamp: clock(),
+							Data:      NewData("genesis"),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantPending: []Data{NewData("five"), NewData("one")},
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "success without replacing the fork",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(time.Hour),
+									Data:      NewMerkleData([]Data{NewData("three")}),
+									Hash:      "hash #2.2",
+									PrevHash:  "hash #1",
+									Height:    1,
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #2.2").Return(23, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      NewMerkleData([]Data{NewData("one")}),
+								Hash:      "hash #2.1",
+								PrevHash:  "hash #1",
+								Height:    1,
+							},
+							{
+								Timestamp: clock(),
+								Data:      NewData("genesis"),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+						return storage
+					}(),
+				},
+				pendingData: []Data{NewData("three"), NewData("five")},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data:      NewMerkleData([]Data{NewData("three")}),
+							Hash:      "hash #2.2",
+							PrevHash:  "hash #1",
+							Height:    1,
+						},
+						{
+							Timestamp: clock(),
+							Data:      NewData("genesis"),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantPending: []Data{NewData("three"), NewData("five")},
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "error",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: new(MockProofer),
+					},
+					Storage: func() GroupStorage {
+						storage := new(MockGroupStorage)
+						storage.
+							On("LoadBlocks", nil, 23).
+							Return(nil, nil, iotest.ErrTimeout)
+
+						return storage
+					}(),
+				},
+				pendingData: []Data{NewData("three"), NewData("five")},
+			},
+			args: args{
+				loader: func() Loader {
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(nil, nil, nil).Maybe()
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantPending: []Data{NewData("three"), NewData("five")},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			blockchain := &Blockchain{
+				dependencies: data.fields.dependencies,
+			}
+
+			mempool, err := NewMempool(MempoolParams{})
+			require.NoError(test, err)
+
+			for _, dataItem := range data.fields.pendingData {
+				err := mempool.Add(dataItem)
+				require.NoError(test, err)
+			}
+
+			producer, err := NewBlockProducer(BlockProducerParams{
+				Blockchain: blockchain,
+				Mempool:    mempool,
+			})
+			require.NoError(test, err)
+
+			gotErr := producer.Merge(data.args.loader, data.args.chunkSize)
+
+			mock.AssertExpectationsForObjects(
+				test,
+				data.fields.dependencies.Proofer,
+				data.fields.dependencies.Storage,
+				data.args.loader,
+			)
+			assert.Equal(test, data.wantPending, mempool.Pending(0))
+			data.wantErr(test, gotErr)
+		})
+	}
+}
@@ -22,6 +22,7 @@ func TestNewBlock(test *testing.T) {
 				Timestamp: clock(),
 				Data:      data,
 				PrevHash:  "previous hash",
+				Height:    1,
 			},
 		).
 		Return("hash", nil)
@@ -37,6 +38,7 @@ func TestNewBlock(test *testing.T) {
 		Data:      data,
 		Hash:      "hash",
 		PrevHash:  "previous hash",
+		Height:    1,
 	}
 	mock.AssertExpectationsForObjects(test, data, proofer)
 	assert.Equal(test, wantedBlock, block)
@@ -71,6 +73,7 @@ func TestNewBlockEx(test *testing.T) {
 										Timestamp: clock(),
 										Data:      new(MockData),
 										PrevHash:  "previous hash",
+										Height:    23,
 									},
 								).
 								Return("hash", nil)
@@ -80,7 +83,8 @@ func TestNewBlockEx(test *testing.T) {
 					},
 					Data: new(MockData),
 					PrevBlock: mo.Some(Block{
-						Hash: "previous hash",
+						Hash:   "previous hash",
+						Height: 22,
 					}),
 				},
 			},
@@ -89,6 +93,7 @@ func TestNewBlockEx(test *testing.T) {
 				Data:      new(MockData),
 				Hash:      "hash",
 				PrevHash:  "previous hash",
+				Height:    23,
 			},
 			wantErr: assert.NoError,
 		},
@@ -109,6 +114,7 @@ func TestNewBlockEx(test *testing.T) {
 										Timestamp: clock(),
 										Data:      new(MockData),
 										PrevHash:  "previous hash",
+										Height:    23,
 									},
 								).
 								Return("", iotest.ErrTimeout)
@@ -118,7 +124,8 @@ func TestNewBlockEx(test *testing.T) {
 					},
 					Data: new(MockData),
 					PrevBlock: mo.Some(Block{
-						Hash: "previous hash",
+						Hash:   "previous hash",
+						Height: 22,
 					}),
 				},
 			},
@@ -287,6 +294,7 @@ func TestBlock_IsEqual(test *testing.T) {
 		Data      Data
 		Hash      string
 		PrevHash  string
+		Height    int
 	}
 	type args struct {
 		anotherBlock Block
@@ -408,6 +416,31 @@ func TestBlock_IsEqual(test *testing.T) {
 			},
 			want: assert.Error,
 		},
+		{
+			name: "not equal due to heights",
+			fields: fields{
+				Timestamp: clock(),
+				Data: func() Data {
+					data := new(MockData)
+					data.On("Equal", mock.AnythingOfType("*blockchain.MockData")).Return(true)
+
+					return data
+				}(),
+				Hash:     "hash",
+				PrevHash: "previous hash",
+				Height:   1,
+			},
+			args: args{
+				anotherBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+					Height:    2,
+				},
+			},
+			want: assert.Error,
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			block := Block{
@@ -415,6 +448,7 @@ func TestBlock_IsEqual(test *testing.T) {
 				Data:      data.fields.Data,
 				Hash:      data.fields.Hash,
 				PrevHash:  data.fields.PrevHash,
+				Height:    data.fields.Height,
 			}
 			got := block.IsEqual(data.args.anotherBlock)
 
@@ -434,6 +468,7 @@ func TestBlock_IsValid(test *testing.T) {
 		Data      Data
 		Hash      string
 		PrevHash  string
+		Height    int
 	}
 	type args struct {
 		prevBlock *Block
@@ -575,6 +610,53 @@ func TestBlock_IsValid(test *testing.T) {
 			},
 			want: assert.Error,
 		},
+		{
+			name: "failure due to heights",
+			fields: fields{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "v1:hash",
+				PrevHash:  "v1:previous hash",
+				Height:    0,
+			},
+			args: args{
+				prevBlock: &Block{
+					Timestamp: clock().Add(-time.Hour),
+					Hash:      "v1:previous hash",
+					Height:    0,
+				},
+				proofer: new(MockProofer),
+			},
+			want: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidHeight)
+			},
+		},
+		{
+			name: "failure due to heights (via the encoding proofer)",
+			fields: fields{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+				Height:    0,
+			},
+			args: args{
+				prevBlock: &Block{
+					Timestamp: clock().Add(-time.Hour),
+					Hash:      "previous hash",
+					Height:    0,
+				},
+				proofer: func() Proofer {
+					proofer := new(MockEncodingProofer)
+					proofer.On("BlockEncoding", "hash").Return(BinaryBlockEncodingV1)
+
+					return proofer
+				}(),
+			},
+			want: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidHeight)
+			},
+		},
 		{
 			name: "failure due to proofers",
 			fields: fields{
@@ -611,6 +693,7 @@ func TestBlock_IsValid(test *testing.T) {
 				Data:      data.fields.Data,
 				Hash:      data.fields.Hash,
 				PrevHash:  data.fields.PrevHash,
+				Height:    data.fields.Height,
 			}
 			got := block.IsValid(data.args.prevBlock, data.args.proofer)
 
@@ -626,6 +709,7 @@ func TestBlock_IsValidGenesisBlock(test *testing.T) {
 		Data      Data
 		Hash      string
 		PrevHash  string
+		Height    int
 	}
 	type args struct {
 		proofer Proofer
@@ -688,6 +772,22 @@ func TestBlock_IsValidGenesisBlock(test *testing.T) {
 			},
 			want: assert.Error,
 		},
+		{
+			name: "failure due to heights",
+			fields: fields{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "v1:hash",
+				PrevHash:  "",
+				Height:    1,
+			},
+			args: args{
+				proofer: new(MockProofer),
+			},
+			want: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidHeight)
+			},
+		},
 		{
 			name: "failure due to proofers",
 			fields: fields{
@@ -720,6 +820,7 @@ func TestBlock_IsValidGenesisBlock(test *testing.T) {
 				Data:      data.fields.Data,
 				Hash:      data.fields.Hash,
 				PrevHash:  data.fields.PrevHash,
+				Height:    data.fields.Height,
 			}
 			got := block.IsValidGenesisBlock(data.args.proofer)
 
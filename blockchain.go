@@ -4,24 +4,83 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/samber/mo"
 )
 
-// ErrEqualDifficulties ...
-var ErrEqualDifficulties = errors.New("equal difficulties")
+// ...
+var (
+	ErrEqualDifficulties = errors.New("equal difficulties")
+	ErrInvalidFork       = errors.New("invalid fork")
+	ErrLastBlockChanged  = errors.New("last block changed")
+	ErrReorgTooDeep      = errors.New("reorganization is too deep")
+)
+
+//go:generate mockery --name=CoinbaseData --inpackage --case=underscore --testonly
+
+// CoinbaseData ...
+//
+// It's the reward entry for the miner of the block. Such data is bound
+// to its block by the height, so it's never returned to the mempool
+// from the orphan blocks.
+type CoinbaseData interface {
+	Data
+
+	CoinbaseHeight() int
+}
+
+// CoinbaseFactory ...
+//
+// It creates the reward entry for the miner of the block
+// with the specified height.
+type CoinbaseFactory func(height int) (CoinbaseData, error)
 
 // Dependencies ...
+//
+// The fork choice is [HeaviestWorkForkChoice] by default.
+//
+// If the coinbase factory is specified, the coinbase is included
+// in each added block (but not in the genesis one).
+//
+// If the state engine is specified, it should already reflect the blocks
+// from the storage. The blockchain applies the added blocks to it
+// and switches it between the forks on the merging; a block rejected
+// by the state engine isn't added.
+//
+// If the maximal reorganization depth is specified, the merging is refused
+// when it would delete more blocks.
 type Dependencies struct {
 	BlockDependencies
 
-	Storage GroupStorage
+	Storage         GroupStorage
+	ForkChoice      mo.Option[ForkChoice]
+	CoinbaseFactory mo.Option[CoinbaseFactory]
+	StateEngine     mo.Option[StateEngine]
+	MaxReorgDepth   mo.Option[int]
 }
 
 // Blockchain ...
+//
+// It's safe for concurrent use: the loading of blocks is performed
+// concurrently, while the changes are serialized. The mining of a new block
+// is performed outside the lock and is canceled when the last block
+// is changed by another call.
+//
+// The changes are published to the subscribers as events
+// (see [Blockchain.Subscribe]).
 type Blockchain struct {
 	dependencies Dependencies
-	lastBlock    Block
+
+	events eventBus
+
+	lock      sync.RWMutex
+	lastBlock Block
+	// it's incremented on every change of the last block
+	lastBlockVersion int
+	// it's created lazily and canceled on every change of the last block
+	lastBlockCtx       context.Context
+	cancelLastBlockCtx context.CancelCauseFunc
 }
 
 // NewBlockchain ...
@@ -68,8 +127,28 @@ func NewBlockchainEx(
 			return nil, fmt.Errorf("unable to create a new genesis block: %w", err)
 		}
 
+		stateEngine, isStateEnginePresent := params.Dependencies.StateEngine.Get()
+		if isStateEnginePresent {
+			if err := stateEngine.ApplyBlock(genesisBlock); err != nil {
+				return nil, fmt.Errorf(
+					"unable to apply the genesis block to the state: %w",
+					err,
+				)
+			}
+		}
+
 		if err = params.Dependencies.Storage.StoreBlock(genesisBlock); err != nil {
-			return nil, fmt.Errorf("unable to store the genesis block: %w", err)
+			err = fmt.Errorf("unable to store the genesis block: %w", err)
+			if isStateEnginePresent {
+				if undoErr := stateEngine.UndoBlock(genesisBlock); undoErr != nil {
+					err = errors.Join(err, fmt.Errorf(
+						"unable to undo the genesis block in the state: %w",
+						undoErr,
+					))
+				}
+			}
+
+			return nil, err
 		}
 
 		lastBlock = genesisBlock
@@ -82,15 +161,61 @@ func NewBlockchainEx(
 	return blockchain, nil
 }
 
+// Subscribe ...
+//
+// The events are received from the channel of the subscription in the order
+// of the changes. The events of the changes are published after the changes
+// are unlocked, so the subscriber can load the blocks meanwhile. With
+// the [BlockOnOverflow] policy, the methods changing the blockchain return
+// only after the delivery, so the subscriber shouldn't wait for the changes
+// of the blockchain meanwhile.
+func (blockchain *Blockchain) Subscribe(params SubscriptionParams) (
+	*Subscription,
+	error,
+) {
+	return blockchain.events.subscribe(params, mo.None[EventHandler]())
+}
+
+// SubscribeFunc ...
+//
+// It's similar to the [Blockchain.Subscribe] method, but the events
+// are passed to the handler in a separate goroutine. The handler isn't called
+// after unsubscribing, except for the call in progress.
+func (blockchain *Blockchain) SubscribeFunc(
+	handler EventHandler,
+	params SubscriptionParams,
+) (*Subscription, error) {
+	return blockchain.events.subscribe(params, mo.Some(handler))
+}
+
 // LoadBlocks ...
-func (blockchain Blockchain) LoadBlocks(cursor interface{}, count int) (
+func (blockchain *Blockchain) LoadBlocks(cursor interface{}, count int) (
 	blocks BlockGroup,
 	nextCursor interface{},
 	err error,
 ) {
+	blockchain.lock.RLock()
+	defer blockchain.lock.RUnlock()
+
 	return blockchain.dependencies.Storage.LoadBlocks(cursor, count)
 }
 
+// LoadBlockByHeight ...
+//
+// It returns the [errors.ErrUnsupported] error if the storage doesn't
+// implement the [IndexedLoader] interface.
+func (blockchain *Blockchain) LoadBlockByHeight(height int) (Block, error) {
+	blockchain.lock.RLock()
+	defer blockchain.lock.RUnlock()
+
+	indexedLoader, ok := blockchain.dependencies.Storage.(IndexedLoader)
+	if !ok {
+		return Block{}, errors.ErrUnsupported
+	}
+
+	return indexedLoader.LoadBlockByHeight(height)
+}
+
 // AddBlock ...
 //
 // Deprecated: Use [AddBlockEx] instead.
@@ -103,73 +228,399 @@ func (blockchain *Blockchain) AddBlock(data Data) error {
 }
 
 // AddBlockEx ...
+//
+// If the coinbase factory is specified in the dependencies, the block data
+// is wrapped in the [MerkleData] one with the coinbase as the first item
+// (the items of the original [MerkleData] are included directly).
+//
+// If the state engine is specified in the dependencies, the block is applied
+// to it before the storing, so the block rejected by it isn't stored.
+//
+// If the last block is changed (e.g., by the [Blockchain.Merge] method)
+// during the mining, the latter is canceled via the context,
+// and the [ErrLastBlockChanged] error is returned.
 func (blockchain *Blockchain) AddBlockEx(ctx context.Context, data Data) error {
-	block, err := NewBlockEx(ctx, NewBlockExParams{
+	prevBlock, prevBlockVersion, prevBlockCtx := blockchain.watchLastBlock()
+
+	data, err := blockchain.prependCoinbase(data, prevBlock.Height+1)
+	if err != nil {
+		return fmt.Errorf("unable to prepend the coinbase: %w", err)
+	}
+
+	miningCtx, cancelMining := context.WithCancelCause(ctx)
+	defer cancelMining(nil)
+
+	stopWatching := context.AfterFunc(prevBlockCtx, func() {
+		cancelMining(context.Cause(prevBlockCtx))
+	})
+	defer stopWatching()
+
+	block, err := NewBlockEx(miningCtx, NewBlockExParams{
 		Dependencies: blockchain.dependencies.BlockDependencies,
 		Data:         data,
-		PrevBlock:    mo.Some(blockchain.lastBlock),
+		PrevBlock:    mo.Some(prevBlock),
 	})
 	if err != nil {
+		if cause := context.Cause(miningCtx); errors.Is(cause, ErrLastBlockChanged) {
+			err = errors.Join(err, cause)
+		}
+
 		return fmt.Errorf("unable to create a new block: %w", err)
 	}
 
+	// the events are published after the unlocking
+	defer blockchain.events.flush()
+
+	blockchain.lock.Lock()
+	defer blockchain.lock.Unlock()
+
+	// the mining can be finished before its cancellation
+	if blockchain.lastBlockVersion != prevBlockVersion {
+		return ErrLastBlockChanged
+	}
+
+	stateEngine, isStateEnginePresent := blockchain.dependencies.StateEngine.Get()
+	if isStateEnginePresent {
+		if err := stateEngine.ApplyBlock(block); err != nil {
+			return fmt.Errorf("unable to apply the block to the state: %w", err)
+		}
+	}
+
 	if err := blockchain.dependencies.Storage.StoreBlock(block); err != nil {
-		return fmt.Errorf("unable to store the block: %w", err)
+		err = fmt.Errorf("unable to store the block: %w", err)
+		if isStateEnginePresent {
+			if undoErr := stateEngine.UndoBlock(block); undoErr != nil {
+				err = errors.Join(err, fmt.Errorf(
+					"unable to undo the block in the state: %w",
+					undoErr,
+				))
+			}
+		}
+
+		return err
 	}
 
-	blockchain.lastBlock = block
+	blockchain.setLastBlock(block)
+	blockchain.events.enqueue(BlockAddedEvent{Block: block})
+
 	return nil
 }
 
 // Merge ...
+//
+// Before replacing anything, it validates the right differences as a fork
+// that follows the common ancestor. If they are invalid, it returns
+// the [ErrInvalidFork] error joined with the [BlockValidationError] one.
+//
+// The differences are compared by the fork choice from the dependencies.
+// By default, they are compared by their work (see [BlockGroup.Work]),
+// so the proofers implementing the [WorkProofer] interface compare forks
+// by the cumulative work instead of the summed difficulties. If the fork
+// choice prefers neither fork, the [ErrEqualDifficulties] error is returned.
+// If the right fork is preferred, but the left differences are longer
+// than the maximal reorganization depth from the dependencies,
+// the [ErrReorgTooDeep] error is returned.
+//
+// If the state engine is specified in the dependencies, the left differences
+// are undone in it and the right ones are applied before the replacing.
+// If the state engine rejects the right differences, the merging is refused
+// and the state is restored. The state is switched back if the replacing
+// fails.
+//
+// If the storage implements the [TransactionalStorage] interface, the left
+// differences are replaced with the right ones atomically. Otherwise,
+// the deleted left differences are restored if the storing of the right ones
+// fails.
+//
+// The differences are searched without blocking other calls. If the last block
+// is changed meanwhile, the [ErrLastBlockChanged] error is returned.
+// Otherwise, the in-flight mining jobs are canceled on replacing
+// the differences.
+//
+// On replacing, the [BlocksRevertedEvent] event (if there are left
+// differences) and the [BlocksAppliedEvent] one are published. If the found
+// differences aren't replaced, the [MergeRejectedEvent] event is published.
 func (blockchain *Blockchain) Merge(loader Loader, chunkSize int) error {
-	leftDifferences, rightDifferences, err :=
-		FindDifferences(blockchain, loader, chunkSize)
-	if err != nil {
-		return fmt.Errorf("unable to find differences: %w", err)
+	_, _, err := blockchain.merge(loader, chunkSize)
+	return err
+}
+
+// MergeEx ...
+//
+// It's similar to the [Blockchain.Merge] method, but it also returns
+// the report about the found fork. The report is returned even if the fork
+// isn't merged, but it's empty if the fork isn't found.
+//
+// The work values of the report are calculated after the merging,
+// so if their calculation fails, the report without them is returned
+// along with the error, even if the fork is merged.
+func (blockchain *Blockchain) MergeEx(loader Loader, chunkSize int) (
+	ReorgReport,
+	error,
+) {
+	foundFork, isReplaced, err := blockchain.merge(loader, chunkSize)
+	if foundFork, isPresent := foundFork.Get(); isPresent {
+		report, reportErr := newReorgReport(
+			foundFork,
+			isReplaced,
+			blockchain.dependencies.Proofer,
+		)
+		if reportErr != nil {
+			err = errors.Join(err, reportErr)
+		}
+
+		return report, err
 	}
 
-	leftDifficulty, err :=
-		leftDifferences.Difficulty(blockchain.dependencies.Proofer)
+	return ReorgReport{}, err
+}
+
+// it returns the found fork (if any) and whether it's replaced,
+// which is true even if the reloading of the last block fails,
+// because the differences are already replaced
+func (blockchain *Blockchain) merge(loader Loader, chunkSize int) (
+	foundFork mo.Option[fork],
+	isReplaced bool,
+	err error,
+) {
+	blockchain.lock.RLock()
+	lastBlockVersion := blockchain.lastBlockVersion
+	blockchain.lock.RUnlock()
+
+	differences, err := findFork(blockchain, loader, chunkSize)
 	if err != nil {
-		return fmt.Errorf(
-			"unable to calculate the difficulty of the left differences: %w",
+		return mo.None[fork](), false, fmt.Errorf(
+			"unable to find differences: %w",
 			err,
 		)
 	}
 
-	rightDifficulty, err :=
-		rightDifferences.Difficulty(blockchain.dependencies.Proofer)
+	isReplaced, err = blockchain.mergeFork(differences, lastBlockVersion)
+	return mo.Some(differences), isReplaced, err
+}
+
+// it publishes the [MergeRejectedEvent] event if the fork isn't replaced
+func (blockchain *Blockchain) mergeFork(
+	foundFork fork,
+	lastBlockVersion int,
+) (isReplaced bool, err error) {
+	leftDifferences, rightDifferences :=
+		foundFork.leftDifferences, foundFork.rightDifferences
+	// the events are published after the unlocking
+	defer blockchain.events.flush()
+	defer func() {
+		if !isReplaced {
+			blockchain.events.enqueue(MergeRejectedEvent{
+				LeftDifferences:  leftDifferences,
+				RightDifferences: rightDifferences,
+				Err:              err,
+			})
+		}
+	}()
+
+	if err := rightDifferences.IsValidFork(
+		foundFork.commonBlock,
+		blockchain.dependencies.Proofer,
+	); err != nil {
+		return false, fmt.Errorf(
+			"the right differences are not valid: %w",
+			errors.Join(err, ErrInvalidFork),
+		)
+	}
+
+	forkChoice := blockchain.dependencies.ForkChoice.
+		OrElse(HeaviestWorkForkChoice{})
+	result, err := forkChoice.CompareForks(
+		leftDifferences,
+		rightDifferences,
+		blockchain.dependencies.Proofer,
+	)
 	if err != nil {
-		return fmt.Errorf(
-			"unable to calculate the difficulty of the right differences: %w",
-			err,
+		return false, fmt.Errorf("unable to compare the forks: %w", err)
+	}
+
+	if result > 0 {
+		return false, nil
+	}
+	if result == 0 {
+		return false, ErrEqualDifficulties
+	}
+
+	maxReorgDepth, isMaxReorgDepthPresent :=
+		blockchain.dependencies.MaxReorgDepth.Get()
+	if isMaxReorgDepthPresent && len(leftDifferences) > maxReorgDepth {
+		return false, errors.Join(
+			fmt.Errorf(
+				"the reorganization depth %d exceeds the maximal one %d",
+				len(leftDifferences),
+				maxReorgDepth,
+			),
+			ErrReorgTooDeep,
 		)
 	}
 
-	if leftDifficulty > rightDifficulty {
-		return nil
+	// the fork choice can prefer the empty right differences,
+	// but they have no last block to replace the current one with
+	if len(rightDifferences) == 0 {
+		return false, errors.Join(
+			errors.New("the right differences are empty"),
+			ErrInvalidFork,
+		)
 	}
-	if leftDifficulty == rightDifficulty {
-		return ErrEqualDifficulties
+
+	// if the right fork is preferred...
+	blockchain.lock.Lock()
+	defer blockchain.lock.Unlock()
+
+	if blockchain.lastBlockVersion != lastBlockVersion {
+		return false, ErrLastBlockChanged
 	}
 
-	// if leftDifficulty < rightDifficulty...
-	if err = blockchain.dependencies.Storage.
-		DeleteBlockGroup(leftDifferences); err != nil {
-		return fmt.Errorf("unable to delete the left differences: %w", err)
+	stateEngine, isStateEnginePresent := blockchain.dependencies.StateEngine.Get()
+	if isStateEnginePresent {
+		if err := switchState(
+			stateEngine,
+			leftDifferences,
+			rightDifferences,
+		); err != nil {
+			return false, fmt.Errorf(
+				"unable to switch the state to the right differences: %w",
+				err,
+			)
+		}
 	}
 
-	if err = blockchain.dependencies.Storage.
-		StoreBlockGroup(rightDifferences); err != nil {
-		return fmt.Errorf("unable to store the right differences: %w", err)
+	if err := blockchain.replaceBlockGroup(
+		leftDifferences,
+		rightDifferences,
+	); err != nil {
+		if isStateEnginePresent {
+			if switchErr := switchState(
+				stateEngine,
+				rightDifferences,
+				leftDifferences,
+			); switchErr != nil {
+				err = errors.Join(err, fmt.Errorf(
+					"unable to switch the state back to the left differences: %w",
+					switchErr,
+				))
+			}
+		}
+
+		return false, err
+	}
+
+	if len(leftDifferences) != 0 {
+		blockchain.events.enqueue(BlocksRevertedEvent{Blocks: leftDifferences})
 	}
+	blockchain.events.enqueue(BlocksAppliedEvent{Blocks: rightDifferences})
 
 	lastBlock, err := blockchain.dependencies.Storage.LoadLastBlock()
 	if err != nil {
-		return fmt.Errorf("unable to load the last block: %w", err)
+		// the storage is already changed, so the last block is changed too
+		// (to the last one of the stored blocks), and the mining on top
+		// of the deleted blocks should be canceled anyway
+		blockchain.setLastBlock(rightDifferences[0])
+
+		return true, fmt.Errorf("unable to load the last block: %w", err)
 	}
+	blockchain.setLastBlock(lastBlock)
+
+	return true, nil
+}
+
+func (blockchain *Blockchain) prependCoinbase(data Data, height int) (
+	Data,
+	error,
+) {
+	coinbaseFactory, isPresent := blockchain.dependencies.CoinbaseFactory.Get()
+	if !isPresent {
+		return data, nil
+	}
+
+	coinbase, err := coinbaseFactory(height)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the coinbase: %w", err)
+	}
+
+	items := []Data{coinbase}
+	if merkleData, ok := data.(MerkleData); ok {
+		items = append(items, merkleData.Items()...)
+	} else {
+		items = append(items, data)
+	}
+
+	return NewMerkleData(items), nil
+}
+
+func (blockchain *Blockchain) watchLastBlock() (
+	lastBlock Block,
+	lastBlockVersion int,
+	lastBlockCtx context.Context,
+) {
+	blockchain.lock.Lock()
+	defer blockchain.lock.Unlock()
+
+	if blockchain.lastBlockCtx == nil {
+		blockchain.lastBlockCtx, blockchain.cancelLastBlockCtx =
+			context.WithCancelCause(context.Background())
+	}
+
+	return blockchain.lastBlock,
+		blockchain.lastBlockVersion,
+		blockchain.lastBlockCtx
+}
+
+// it should be called under the lock
+func (blockchain *Blockchain) setLastBlock(lastBlock Block) {
 	blockchain.lastBlock = lastBlock
+	blockchain.lastBlockVersion++
+
+	if blockchain.cancelLastBlockCtx != nil {
+		blockchain.cancelLastBlockCtx(ErrLastBlockChanged)
+		blockchain.lastBlockCtx, blockchain.cancelLastBlockCtx = nil, nil
+	}
+}
+
+func (blockchain *Blockchain) replaceBlockGroup(
+	deletedBlocks BlockGroup,
+	storedBlocks BlockGroup,
+) error {
+	storage := blockchain.dependencies.Storage
+	if transactionalStorage, ok := storage.(TransactionalStorage); ok {
+		err := transactionalStorage.ReplaceBlockGroup(deletedBlocks, storedBlocks)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, errors.ErrUnsupported) {
+			return fmt.Errorf("unable to replace the left differences: %w", err)
+		}
+	}
+
+	if err := storage.DeleteBlockGroup(deletedBlocks); err != nil {
+		return fmt.Errorf("unable to delete the left differences: %w", err)
+	}
+
+	if err := storage.StoreBlockGroup(storedBlocks); err != nil {
+		err = fmt.Errorf("unable to store the right differences: %w", err)
+
+		// the right differences can be stored partially
+		if rollbackErr := storage.DeleteBlockGroup(storedBlocks); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf(
+				"unable to delete the right differences on the rollback: %w",
+				rollbackErr,
+			))
+		}
+
+		if rollbackErr := storage.StoreBlockGroup(deletedBlocks); rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf(
+				"unable to restore the left differences on the rollback: %w",
+				rollbackErr,
+			))
+		}
+
+		return err
+	}
 
 	return nil
 }
@@ -2,6 +2,10 @@ package blockchain
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
 	"testing"
 	"testing/iotest"
 	"time"
@@ -9,6 +13,7 @@ import (
 	"github.com/samber/mo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewBlockchain(test *testing.T) {
@@ -320,6 +325,110 @@ func TestNewBlockchainEx(test *testing.T) {
 			},
 			wantErr: assert.NoError,
 		},
+		{
+			name: "success/with an empty storage and the state engine",
+			args: args{
+				ctx: context.Background(),
+				params: NewBlockchainExParams{
+					Dependencies: Dependencies{
+						BlockDependencies: BlockDependencies{
+							Clock: clock,
+							Proofer: func() Proofer {
+								proofer := new(MockProofer)
+								proofer.
+									On("HashEx", context.Background(), mock.Anything).
+									Return("hash", nil)
+
+								return proofer
+							}(),
+						},
+
+						Storage: func() GroupStorage {
+							storage := new(MockGroupStorage)
+							storage.
+								On("LoadLastBlock").
+								Return(Block{}, ErrEmptyStorage)
+							storage.
+								On("StoreBlock", mock.AnythingOfType("blockchain.Block")).
+								Return(nil)
+
+							return storage
+						}(),
+						StateEngine: mo.Some[StateEngine](func() StateEngine {
+							stateEngine := new(MockStateEngine)
+							stateEngine.
+								On("ApplyBlock", Block{
+									Timestamp: clock(),
+									Data:      new(MockData),
+									Hash:      "hash",
+									PrevHash:  "",
+								}).
+								Return(nil)
+
+							return stateEngine
+						}()),
+					},
+					GenesisBlockData: mo.Some[Data](new(MockData)),
+				},
+			},
+			want: assert.NotNil,
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/unable to apply the genesis block",
+			args: args{
+				ctx: context.Background(),
+				params: NewBlockchainExParams{
+					Dependencies: Dependencies{
+						BlockDependencies: BlockDependencies{
+							Clock: clock,
+							Proofer: func() Proofer {
+								proofer := new(MockProofer)
+								proofer.
+									On("HashEx", context.Background(), mock.Anything).
+									Return("hash", nil)
+
+								return proofer
+							}(),
+						},
+
+						Storage: func() GroupStorage {
+							storage := new(MockGroupStorage)
+							storage.
+								On("LoadLastBlock").
+								Return(Block{}, ErrEmptyStorage)
+
+							return storage
+						}(),
+						StateEngine: mo.Some[StateEngine](func() StateEngine {
+							stateEngine := new(MockStateEngine)
+							stateEngine.
+								On("ApplyBlock", Block{
+									Timestamp: clock(),
+									Data:      new(MockData),
+									Hash:      "hash",
+									PrevHash:  "",
+								}).
+								Return(iotest.ErrTimeout)
+
+							return stateEngine
+						}()),
+					},
+					GenesisBlockData: mo.Some[Data](new(MockData)),
+				},
+			},
+			want:          assert.Nil,
+			wantLastBlock: Block{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
 		{
 			name: "error/unable to load the last block/regular error",
 			args: args{
@@ -485,6 +594,10 @@ func TestNewBlockchainEx(test *testing.T) {
 				data.args.params.Dependencies.Proofer,
 				data.args.params.Dependencies.Storage,
 			)
+			if stateEngine, isPresent :=
+				data.args.params.Dependencies.StateEngine.Get(); isPresent {
+				mock.AssertExpectationsForObjects(test, stateEngine)
+			}
 			if got != nil {
 				mock.AssertExpectationsForObjects(test, got.lastBlock.Data)
 			}
@@ -596,6 +709,62 @@ func TestBlockchain_LoadBlocks(test *testing.T) {
 	}
 }
 
+func TestBlockchain_LoadBlockByHeight(test *testing.T) {
+	block := Block{
+		Timestamp: clock(),
+		Data:      new(MockData),
+		Hash:      "hash",
+		PrevHash:  "previous hash",
+		Height:    1,
+	}
+
+	for _, data := range []struct {
+		name      string
+		storage   GroupStorage
+		wantBlock Block
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			storage: func() GroupStorage {
+				loader := new(MockIndexedLoader)
+				loader.On("LoadBlockByHeight", 1).Return(block, nil)
+
+				return indexedGroupStorage{
+					MockGroupStorage:  new(MockGroupStorage),
+					MockIndexedLoader: loader,
+				}
+			}(),
+			wantBlock: block,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "error",
+			storage:   new(MockGroupStorage),
+			wantBlock: Block{},
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.ErrorIs(test, err, errors.ErrUnsupported, msgAndArgs...)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			blockchain := Blockchain{
+				dependencies: Dependencies{
+					Storage: data.storage,
+				},
+			}
+			gotBlock, gotErr := blockchain.LoadBlockByHeight(1)
+
+			assert.Equal(test, data.wantBlock, gotBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
 func TestBlockchain_AddBlock(test *testing.T) {
 	type fields struct {
 		dependencies Dependencies
@@ -623,11 +792,12 @@ func TestBlockchain_AddBlock(test *testing.T) {
 							proofer.
 								On(
 									"HashEx",
-									context.Background(),
+									mock.Anything,
 									Block{
 										Timestamp: clock(),
 										Data:      new(MockData),
 										PrevHash:  "hash",
+										Height:    1,
 									},
 								).
 								Return("next hash", nil)
@@ -643,6 +813,7 @@ func TestBlockchain_AddBlock(test *testing.T) {
 								Data:      new(MockData),
 								Hash:      "next hash",
 								PrevHash:  "hash",
+								Height:    1,
 							}).
 							Return(nil)
 
@@ -664,6 +835,7 @@ func TestBlockchain_AddBlock(test *testing.T) {
 				Data:      new(MockData),
 				Hash:      "next hash",
 				PrevHash:  "hash",
+				Height:    1,
 			},
 			wantErr: assert.NoError,
 		},
@@ -678,11 +850,12 @@ func TestBlockchain_AddBlock(test *testing.T) {
 							proofer.
 								On(
 									"HashEx",
-									context.Background(),
+									mock.Anything,
 									Block{
 										Timestamp: clock(),
 										Data:      new(MockData),
 										PrevHash:  "hash",
+										Height:    1,
 									},
 								).
 								Return("next hash", nil)
@@ -698,6 +871,7 @@ func TestBlockchain_AddBlock(test *testing.T) {
 								Data:      new(MockData),
 								Hash:      "next hash",
 								PrevHash:  "hash",
+								Height:    1,
 							}).
 							Return(iotest.ErrTimeout)
 
@@ -772,11 +946,12 @@ func TestBlockchain_AddBlockEx(test *testing.T) {
 							proofer.
 								On(
 									"HashEx",
-									context.Background(),
+									mock.Anything,
 									Block{
 										Timestamp: clock(),
 										Data:      new(MockData),
 										PrevHash:  "hash",
+										Height:    1,
 									},
 								).
 								Return("next hash", nil)
@@ -792,6 +967,7 @@ func TestBlockchain_AddBlockEx(test *testing.T) {
 								Data:      new(MockData),
 								Hash:      "next hash",
 								PrevHash:  "hash",
+								Height:    1,
 							}).
 							Return(nil)
 
@@ -814,6 +990,7 @@ func TestBlockchain_AddBlockEx(test *testing.T) {
 				Data:      new(MockData),
 				Hash:      "next hash",
 				PrevHash:  "hash",
+				Height:    1,
 			},
 			wantErr: assert.NoError,
 		},
@@ -828,11 +1005,12 @@ func TestBlockchain_AddBlockEx(test *testing.T) {
 							proofer.
 								On(
 									"HashEx",
-									context.Background(),
+									mock.Anything,
 									Block{
 										Timestamp: clock(),
 										Data:      new(MockData),
 										PrevHash:  "hash",
+										Height:    1,
 									},
 								).
 								Return("", iotest.ErrTimeout)
@@ -872,11 +1050,12 @@ func TestBlockchain_AddBlockEx(test *testing.T) {
 							proofer.
 								On(
 									"HashEx",
-									context.Background(),
+									mock.Anything,
 									Block{
 										Timestamp: clock(),
 										Data:      new(MockData),
 										PrevHash:  "hash",
+										Height:    1,
 									},
 								).
 								Return("next hash", nil)
@@ -892,6 +1071,7 @@ func TestBlockchain_AddBlockEx(test *testing.T) {
 								Data:      new(MockData),
 								Hash:      "next hash",
 								PrevHash:  "hash",
+								Height:    1,
 							}).
 							Return(iotest.ErrTimeout)
 
@@ -940,6 +1120,112 @@ func TestBlockchain_AddBlockEx(test *testing.T) {
 	}
 }
 
+func TestBlockchain_AddBlockEx_withCoinbase(test *testing.T) {
+	type args struct {
+		data Data
+	}
+
+	for _, data := range []struct {
+		name          string
+		args          args
+		wantBlockData func(coinbase CoinbaseData) Data
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with regular data",
+			args: args{
+				data: NewData("data"),
+			},
+			wantBlockData: func(coinbase CoinbaseData) Data {
+				return NewMerkleData([]Data{coinbase, NewData("data")})
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with Merkle data",
+			args: args{
+				data: NewMerkleData([]Data{NewData("one"), NewData("two")}),
+			},
+			wantBlockData: func(coinbase CoinbaseData) Data {
+				return NewMerkleData([]Data{
+					coinbase,
+					NewData("one"),
+					NewData("two"),
+				})
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				data: NewData("data"),
+			},
+			wantBlockData: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			coinbase := new(MockCoinbaseData)
+			coinbase.On("String").Return("coinbase").Maybe()
+
+			proofer := new(MockProofer)
+			storage := new(MockGroupStorage)
+			wantLastBlock := Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+				Height:    23,
+			}
+			if data.wantBlockData != nil {
+				wantLastBlock = Block{
+					Timestamp: clock(),
+					Data:      data.wantBlockData(coinbase),
+					PrevHash:  "hash",
+					Height:    24,
+				}
+				proofer.On("HashEx", mock.Anything, wantLastBlock).Return("next hash", nil)
+
+				wantLastBlock.Hash = "next hash"
+				storage.On("StoreBlock", wantLastBlock).Return(nil)
+			}
+
+			blockchain := &Blockchain{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock:   clock,
+						Proofer: proofer,
+					},
+					Storage: storage,
+					CoinbaseFactory: mo.Some[CoinbaseFactory](
+						func(height int) (CoinbaseData, error) {
+							if data.wantBlockData == nil || height != 24 {
+								return nil, iotest.ErrTimeout
+							}
+
+							return coinbase, nil
+						},
+					),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+					Height:    23,
+				},
+			}
+			err := blockchain.AddBlockEx(context.Background(), data.args.data)
+
+			mock.AssertExpectationsForObjects(test, coinbase, proofer, storage)
+			assert.Equal(test, wantLastBlock, blockchain.lastBlock)
+			data.wantErr(test, err)
+		})
+	}
+}
+
 func TestBlockchain_Merge(test *testing.T) {
 	type fields struct {
 		dependencies Dependencies
@@ -966,6 +1252,14 @@ func TestBlockchain_Merge(test *testing.T) {
 							proofer := new(MockProofer)
 							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
 							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
 							proofer.On("Difficulty", "hash #3").Return(12, nil)
 
 							return proofer
@@ -1066,6 +1360,14 @@ func TestBlockchain_Merge(test *testing.T) {
 							proofer := new(MockProofer)
 							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
 							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
 							proofer.On("Difficulty", "hash #3").Return(100, nil)
 
 							return proofer
@@ -1189,46 +1491,29 @@ func TestBlockchain_Merge(test *testing.T) {
 			wantErr: assert.NoError,
 		},
 		{
-			name: "error with searching differences",
-			fields: fields{
-				dependencies: Dependencies{
-					BlockDependencies: BlockDependencies{
-						Proofer: new(MockProofer),
-					},
-					Storage: func() GroupStorage {
-						storage := new(MockGroupStorage)
-						storage.On("LoadBlocks", nil, 23).Return(nil, nil, iotest.ErrTimeout)
-
-						return storage
-					}(),
-				},
-				lastBlock: Block{
-					Timestamp: clock(),
-					Data:      new(MockData),
-					Hash:      "hash",
-					PrevHash:  "previous hash",
-				},
-			},
-			args: args{
-				loader:    new(MockLoader),
-				chunkSize: 23,
-			},
-			wantLastBlock: Block{
-				Timestamp: clock(),
-				Data:      new(MockData),
-				Hash:      "hash",
-				PrevHash:  "previous hash",
-			},
-			wantErr: assert.Error,
-		},
-		{
-			name: "error with calculating the difficulty of the left differences",
+			name: "success with the work proofer",
 			fields: fields{
 				dependencies: Dependencies{
 					BlockDependencies: BlockDependencies{
 						Proofer: func() Proofer {
-							proofer := new(MockProofer)
-							proofer.On("Difficulty", "hash #3.2").Return(0, iotest.ErrTimeout)
+							proofer := new(MockWorkProofer)
+							proofer.
+								On("Work", "hash #3.2").
+								Return(new(big.Int).Lsh(big.NewInt(1), 69), nil)
+							proofer.
+								On("Work", "hash #3.1").
+								Return(new(big.Int).Lsh(big.NewInt(1), 70), nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.
+								On("Work", "hash #3").
+								Return(new(big.Int).Lsh(big.NewInt(1), 70), nil)
 
 							return proofer
 						}(),
@@ -1317,10 +1602,10 @@ func TestBlockchain_Merge(test *testing.T) {
 				Hash:      "hash",
 				PrevHash:  "previous hash",
 			},
-			wantErr: assert.Error,
+			wantErr: assert.NoError,
 		},
 		{
-			name: "error with calculating the difficulty of the right differences",
+			name: "success with the fork choice",
 			fields: fields{
 				dependencies: Dependencies{
 					BlockDependencies: BlockDependencies{
@@ -1328,7 +1613,15 @@ func TestBlockchain_Merge(test *testing.T) {
 							proofer := new(MockProofer)
 							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
 							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
-							proofer.On("Difficulty", "hash #3").Return(0, iotest.ErrTimeout)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(65, nil)
 
 							return proofer
 						}(),
@@ -1360,22 +1653,54 @@ func TestBlockchain_Merge(test *testing.T) {
 								PrevHash:  "",
 							},
 						}
-
-						storage := new(MockGroupStorage)
-						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
-
-						return storage
-					}(),
-				},
-				lastBlock: Block{
-					Timestamp: clock(),
-					Data:      new(MockData),
-					Hash:      "hash",
-					PrevHash:  "previous hash",
-				},
-			},
-			args: args{
-				loader: func() Loader {
+						blocksForDeleting := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+						}
+						blocksForStoring := BlockGroup{
+							{
+								Timestamp: clock().Add(2 * time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #3",
+								PrevHash:  "hash #2",
+							},
+						}
+						newLastBlock := Block{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.On("DeleteBlockGroup", blocksForDeleting).Return(nil)
+						storage.On("StoreBlockGroup", blocksForStoring).Return(nil)
+						storage.On("LoadLastBlock").Return(newLastBlock, nil)
+
+						return storage
+					}(),
+					ForkChoice: mo.Some[ForkChoice](LowestHashForkChoice{}),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
 					blocks := BlockGroup{
 						{
 							Timestamp: clock().Add(2 * time.Hour),
@@ -1412,15 +1737,15 @@ func TestBlockchain_Merge(test *testing.T) {
 				chunkSize: 23,
 			},
 			wantLastBlock: Block{
-				Timestamp: clock(),
+				Timestamp: clock().Add(2 * time.Hour),
 				Data:      new(MockData),
-				Hash:      "hash",
-				PrevHash:  "previous hash",
+				Hash:      "hash #3",
+				PrevHash:  "hash #2",
 			},
-			wantErr: assert.Error,
+			wantErr: assert.NoError,
 		},
 		{
-			name: "error with leftDifficulty == rightDifficulty",
+			name: "success with the transactional storage",
 			fields: fields{
 				dependencies: Dependencies{
 					BlockDependencies: BlockDependencies{
@@ -1428,7 +1753,15 @@ func TestBlockchain_Merge(test *testing.T) {
 							proofer := new(MockProofer)
 							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
 							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
-							proofer.On("Difficulty", "hash #3").Return(65, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(100, nil)
 
 							return proofer
 						}(),
@@ -1460,11 +1793,44 @@ func TestBlockchain_Merge(test *testing.T) {
 								PrevHash:  "",
 							},
 						}
+						blocksForDeleting := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+						}
+						blocksForStoring := BlockGroup{
+							{
+								Timestamp: clock().Add(2 * time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #3",
+								PrevHash:  "hash #2",
+							},
+						}
+						newLastBlock := Block{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						}
 
 						storage := new(MockGroupStorage)
 						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.On("LoadLastBlock").Return(newLastBlock, nil)
 
-						return storage
+						storage.
+							On("ReplaceBlockGroup", blocksForDeleting, blocksForStoring).
+							Return(nil)
+
+						return transactionalGroupStorage{MockGroupStorage: storage}
 					}(),
 				},
 				lastBlock: Block{
@@ -1512,21 +1878,15 @@ func TestBlockchain_Merge(test *testing.T) {
 				chunkSize: 23,
 			},
 			wantLastBlock: Block{
-				Timestamp: clock(),
+				Timestamp: clock().Add(2 * time.Hour),
 				Data:      new(MockData),
-				Hash:      "hash",
-				PrevHash:  "previous hash",
-			},
-			wantErr: func(
-				test assert.TestingT,
-				err error,
-				msgAndArgs ...interface{},
-			) bool {
-				return assert.Equal(test, ErrEqualDifficulties, err, msgAndArgs...)
+				Hash:      "hash #3",
+				PrevHash:  "hash #2",
 			},
+			wantErr: assert.NoError,
 		},
 		{
-			name: "error with deleting the left differences",
+			name: "success with the unsupported transactional storage",
 			fields: fields{
 				dependencies: Dependencies{
 					BlockDependencies: BlockDependencies{
@@ -1534,6 +1894,14 @@ func TestBlockchain_Merge(test *testing.T) {
 							proofer := new(MockProofer)
 							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
 							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
 							proofer.On("Difficulty", "hash #3").Return(100, nil)
 
 							return proofer
@@ -1580,14 +1948,32 @@ func TestBlockchain_Merge(test *testing.T) {
 								PrevHash:  "hash #2",
 							},
 						}
+						blocksForStoring := BlockGroup{
+							{
+								Timestamp: clock().Add(2 * time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #3",
+								PrevHash:  "hash #2",
+							},
+						}
+						newLastBlock := Block{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						}
 
 						storage := new(MockGroupStorage)
 						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.On("DeleteBlockGroup", blocksForDeleting).Return(nil)
+						storage.On("StoreBlockGroup", blocksForStoring).Return(nil)
+						storage.On("LoadLastBlock").Return(newLastBlock, nil)
+
 						storage.
-							On("DeleteBlockGroup", blocksForDeleting).
-							Return(iotest.ErrTimeout)
+							On("ReplaceBlockGroup", blocksForDeleting, blocksForStoring).
+							Return(errors.ErrUnsupported)
 
-						return storage
+						return transactionalGroupStorage{MockGroupStorage: storage}
 					}(),
 				},
 				lastBlock: Block{
@@ -1634,6 +2020,39 @@ func TestBlockchain_Merge(test *testing.T) {
 				}(),
 				chunkSize: 23,
 			},
+			wantLastBlock: Block{
+				Timestamp: clock().Add(2 * time.Hour),
+				Data:      new(MockData),
+				Hash:      "hash #3",
+				PrevHash:  "hash #2",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error with searching differences",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: new(MockProofer),
+					},
+					Storage: func() GroupStorage {
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(nil, nil, iotest.ErrTimeout)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader:    new(MockLoader),
+				chunkSize: 23,
+			},
 			wantLastBlock: Block{
 				Timestamp: clock(),
 				Data:      new(MockData),
@@ -1643,15 +2062,20 @@ func TestBlockchain_Merge(test *testing.T) {
 			wantErr: assert.Error,
 		},
 		{
-			name: "error with storing the right differences",
+			name: "error with the invalid proof of the right differences",
 			fields: fields{
 				dependencies: Dependencies{
 					BlockDependencies: BlockDependencies{
 						Proofer: func() Proofer {
 							proofer := new(MockProofer)
-							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
-							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
-							proofer.On("Difficulty", "hash #3").Return(100, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(iotest.ErrTimeout)
 
 							return proofer
 						}(),
@@ -1683,33 +2107,9 @@ func TestBlockchain_Merge(test *testing.T) {
 								PrevHash:  "",
 							},
 						}
-						blocksForDeleting := BlockGroup{
-							{
-								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
-								Data:      new(MockData),
-								Hash:      "hash #3.2",
-								PrevHash:  "hash #3.1",
-							},
-							{
-								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
-								Data:      new(MockData),
-								Hash:      "hash #3.1",
-								PrevHash:  "hash #2",
-							},
-						}
-						blocksForStoring := BlockGroup{
-							{
-								Timestamp: clock().Add(2 * time.Hour),
-								Data:      new(MockData),
-								Hash:      "hash #3",
-								PrevHash:  "hash #2",
-							},
-						}
 
 						storage := new(MockGroupStorage)
 						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
-						storage.On("DeleteBlockGroup", blocksForDeleting).Return(nil)
-						storage.On("StoreBlockGroup", blocksForStoring).Return(iotest.ErrTimeout)
 
 						return storage
 					}(),
@@ -1764,21 +2164,25 @@ func TestBlockchain_Merge(test *testing.T) {
 				Hash:      "hash",
 				PrevHash:  "previous hash",
 			},
-			wantErr: assert.Error,
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				var validationErr BlockValidationError
+				return assert.ErrorIs(test, err, ErrInvalidFork, msgAndArgs...) &&
+					assert.ErrorIs(test, err, ErrInvalidProof, msgAndArgs...) &&
+					assert.ErrorAs(test, err, &validationErr, msgAndArgs...) &&
+					assert.Equal(test, 0, validationErr.BlockIndex, msgAndArgs...) &&
+					assert.Equal(test, "hash #3", validationErr.BlockHash, msgAndArgs...)
+			},
 		},
 		{
-			name: "error with loading the last block",
+			name: "error with the invalid linkage of the right differences",
 			fields: fields{
 				dependencies: Dependencies{
 					BlockDependencies: BlockDependencies{
-						Proofer: func() Proofer {
-							proofer := new(MockProofer)
-							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
-							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
-							proofer.On("Difficulty", "hash #3").Return(100, nil)
-
-							return proofer
-						}(),
+						Proofer: new(MockProofer),
 					},
 					Storage: func() GroupStorage {
 						blocks := BlockGroup{
@@ -1807,34 +2211,9 @@ func TestBlockchain_Merge(test *testing.T) {
 								PrevHash:  "",
 							},
 						}
-						blocksForDeleting := BlockGroup{
-							{
-								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
-								Data:      new(MockData),
-								Hash:      "hash #3.2",
-								PrevHash:  "hash #3.1",
-							},
-							{
-								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
-								Data:      new(MockData),
-								Hash:      "hash #3.1",
-								PrevHash:  "hash #2",
-							},
-						}
-						blocksForStoring := BlockGroup{
-							{
-								Timestamp: clock().Add(2 * time.Hour),
-								Data:      new(MockData),
-								Hash:      "hash #3",
-								PrevHash:  "hash #2",
-							},
-						}
 
 						storage := new(MockGroupStorage)
 						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
-						storage.On("DeleteBlockGroup", blocksForDeleting).Return(nil)
-						storage.On("StoreBlockGroup", blocksForStoring).Return(nil)
-						storage.On("LoadLastBlock").Return(Block{}, iotest.ErrTimeout)
 
 						return storage
 					}(),
@@ -1853,7 +2232,7 @@ func TestBlockchain_Merge(test *testing.T) {
 							Timestamp: clock().Add(2 * time.Hour),
 							Data:      new(MockData),
 							Hash:      "hash #3",
-							PrevHash:  "hash #2",
+							PrevHash:  "hash #1",
 						},
 						{
 							Timestamp: clock().Add(time.Hour),
@@ -1889,14 +2268,1119 @@ func TestBlockchain_Merge(test *testing.T) {
 				Hash:      "hash",
 				PrevHash:  "previous hash",
 			},
-			wantErr: assert.Error,
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				var validationErr BlockValidationError
+				return assert.ErrorIs(test, err, ErrInvalidFork, msgAndArgs...) &&
+					assert.ErrorIs(test, err, ErrInvalidPrevHash, msgAndArgs...) &&
+					assert.ErrorAs(test, err, &validationErr, msgAndArgs...) &&
+					assert.Equal(test, 0, validationErr.BlockIndex, msgAndArgs...) &&
+					assert.Equal(test, "hash #3", validationErr.BlockHash, msgAndArgs...)
+			},
 		},
-	} {
-		test.Run(data.name, func(test *testing.T) {
-			blockchain := &Blockchain{
-				dependencies: data.fields.dependencies,
-				lastBlock:    data.fields.lastBlock,
-			}
+		{
+			name: "error with the invalid timestamp of the right differences",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: new(MockProofer),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(30 * time.Minute),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				var validationErr BlockValidationError
+				return assert.ErrorIs(test, err, ErrInvalidFork, msgAndArgs...) &&
+					assert.ErrorIs(test, err, ErrInvalidTimestamp, msgAndArgs...) &&
+					assert.ErrorAs(test, err, &validationErr, msgAndArgs...) &&
+					assert.Equal(test, 0, validationErr.BlockIndex, msgAndArgs...) &&
+					assert.Equal(test, "hash #3", validationErr.BlockHash, msgAndArgs...)
+			},
+		},
+		{
+			name: "error with calculating the difficulty of the left differences",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3.2").Return(0, iotest.ErrTimeout)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with calculating the difficulty of the right differences",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(0, iotest.ErrTimeout)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with leftDifficulty == rightDifficulty",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(65, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.Equal(test, ErrEqualDifficulties, err, msgAndArgs...)
+			},
+		},
+		{
+			name: "error with deleting the left differences",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(100, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+						blocksForDeleting := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.
+							On("DeleteBlockGroup", blocksForDeleting).
+							Return(iotest.ErrTimeout)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with storing the right differences",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(100, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+						blocksForDeleting := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+						}
+						blocksForStoring := BlockGroup{
+							{
+								Timestamp: clock().Add(2 * time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #3",
+								PrevHash:  "hash #2",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.On("DeleteBlockGroup", blocksForDeleting).Return(nil)
+						storage.On("StoreBlockGroup", blocksForStoring).Return(iotest.ErrTimeout)
+						storage.On("DeleteBlockGroup", blocksForStoring).Return(nil)
+						storage.On("StoreBlockGroup", blocksForDeleting).Return(nil)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with replacing in the transactional storage",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(100, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+						blocksForDeleting := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+						}
+						blocksForStoring := BlockGroup{
+							{
+								Timestamp: clock().Add(2 * time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #3",
+								PrevHash:  "hash #2",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+						storage.
+							On("ReplaceBlockGroup", blocksForDeleting, blocksForStoring).
+							Return(iotest.ErrTimeout)
+
+						return transactionalGroupStorage{MockGroupStorage: storage}
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with restoring the left differences",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(100, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+						blocksForDeleting := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+						}
+						blocksForStoring := BlockGroup{
+							{
+								Timestamp: clock().Add(2 * time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #3",
+								PrevHash:  "hash #2",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.On("DeleteBlockGroup", blocksForDeleting).Return(nil)
+						storage.On("StoreBlockGroup", blocksForStoring).Return(iotest.ErrTimeout)
+						storage.On("DeleteBlockGroup", blocksForStoring).Return(nil)
+						storage.On("StoreBlockGroup", blocksForDeleting).Return(iotest.ErrTimeout)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+			},
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with loading the last block",
+			fields: fields{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: func() Proofer {
+							proofer := new(MockProofer)
+							proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+							proofer.On("Difficulty", "hash #3.1").Return(42, nil)
+							proofer.
+								On("Validate", Block{
+									Timestamp: clock().Add(2 * time.Hour),
+									Data:      new(MockData),
+									Hash:      "hash #3",
+									PrevHash:  "hash #2",
+								}).
+								Return(nil)
+							proofer.On("Difficulty", "hash #3").Return(100, nil)
+
+							return proofer
+						}(),
+					},
+					Storage: func() GroupStorage {
+						blocks := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+							{
+								Timestamp: clock().Add(time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+							},
+							{
+								Timestamp: clock(),
+								Data:      new(MockData),
+								Hash:      "hash #1",
+								PrevHash:  "",
+							},
+						}
+						blocksForDeleting := BlockGroup{
+							{
+								Timestamp: clock().Add(2*time.Hour + 40*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.2",
+								PrevHash:  "hash #3.1",
+							},
+							{
+								Timestamp: clock().Add(2*time.Hour + 20*time.Minute),
+								Data:      new(MockData),
+								Hash:      "hash #3.1",
+								PrevHash:  "hash #2",
+							},
+						}
+						blocksForStoring := BlockGroup{
+							{
+								Timestamp: clock().Add(2 * time.Hour),
+								Data:      new(MockData),
+								Hash:      "hash #3",
+								PrevHash:  "hash #2",
+							},
+						}
+
+						storage := new(MockGroupStorage)
+						storage.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+						storage.On("DeleteBlockGroup", blocksForDeleting).Return(nil)
+						storage.On("StoreBlockGroup", blocksForStoring).Return(nil)
+						storage.On("LoadLastBlock").Return(Block{}, iotest.ErrTimeout)
+
+						return storage
+					}(),
+				},
+				lastBlock: Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash",
+					PrevHash:  "previous hash",
+				},
+			},
+			args: args{
+				loader: func() Loader {
+					blocks := BlockGroup{
+						{
+							Timestamp: clock().Add(2 * time.Hour),
+							Data:      new(MockData),
+							Hash:      "hash #3",
+							PrevHash:  "hash #2",
+						},
+						{
+							Timestamp: clock().Add(time.Hour),
+							Data: func() Data {
+								data := new(MockData)
+								data.
+									On("Equal", mock.AnythingOfType("*blockchain.MockData")).
+									Return(true)
+
+								return data
+							}(),
+							Hash:     "hash #2",
+							PrevHash: "hash #1",
+						},
+						{
+							Timestamp: clock(),
+							Data:      new(MockData),
+							Hash:      "hash #1",
+							PrevHash:  "",
+						},
+					}
+
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantLastBlock: Block{
+				Timestamp: clock().Add(2 * time.Hour),
+				Data:      new(MockData),
+				Hash:      "hash #3",
+				PrevHash:  "hash #2",
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			blockchain := &Blockchain{
+				dependencies: data.fields.dependencies,
+				lastBlock:    data.fields.lastBlock,
+			}
 			gotErr := blockchain.Merge(data.args.loader, data.args.chunkSize)
 
 			mock.AssertExpectationsForObjects(
@@ -1912,3 +3396,724 @@ func TestBlockchain_Merge(test *testing.T) {
 		})
 	}
 }
+
+func TestBlockchain_concurrently(test *testing.T) {
+	proofer := new(MockProofer)
+	proofer.
+		On("HashEx", mock.Anything, mock.AnythingOfType("blockchain.Block")).
+		Return(func(ctx context.Context, block Block) (string, error) {
+			return fmt.Sprintf("hash #%d", block.Height), nil
+		})
+
+	storage := new(MockGroupStorage)
+	storage.On("LoadBlocks", nil, 10).Return(nil, nil, nil)
+	storage.On("StoreBlock", mock.AnythingOfType("blockchain.Block")).Return(nil)
+
+	blockchain := &Blockchain{
+		dependencies: Dependencies{
+			BlockDependencies: BlockDependencies{
+				Clock:   time.Now,
+				Proofer: proofer,
+			},
+			Storage: storage,
+		},
+		lastBlock: Block{
+			Timestamp: clock(),
+			Data:      NewData("genesis block"),
+			Hash:      "hash #0",
+			PrevHash:  "",
+		},
+	}
+
+	const goroutineCount = 10
+	errs := make([]error, goroutineCount)
+	var waitGroup sync.WaitGroup
+	for index := 0; index < goroutineCount; index++ {
+		waitGroup.Add(2)
+
+		go func() {
+			defer waitGroup.Done()
+
+			errs[index] = blockchain.AddBlockEx(
+				context.Background(),
+				NewData(fmt.Sprintf("block #%d", index)),
+			)
+		}()
+
+		go func() {
+			defer waitGroup.Done()
+
+			_, _, err := blockchain.LoadBlocks(nil, 10)
+			assert.NoError(test, err)
+		}()
+	}
+	waitGroup.Wait()
+
+	var addedBlockCount int
+	for _, err := range errs {
+		if err == nil {
+			addedBlockCount++
+			continue
+		}
+
+		assert.ErrorIs(test, err, ErrLastBlockChanged)
+	}
+
+	storage.AssertNumberOfCalls(test, "StoreBlock", addedBlockCount)
+	assert.Equal(test, addedBlockCount, blockchain.lastBlock.Height)
+	assert.Equal(
+		test,
+		fmt.Sprintf("hash #%d", addedBlockCount),
+		blockchain.lastBlock.Hash,
+	)
+}
+
+func TestBlockchain_Merge_cancelingMining(test *testing.T) {
+	genesisBlock := Block{
+		Timestamp: clock(),
+		Data:      NewData("genesis block"),
+		Hash:      "hash #0",
+		PrevHash:  "",
+	}
+	remoteBlock := Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      NewData("remote block"),
+		Hash:      "remote hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+
+	for _, data := range []struct {
+		name       string
+		loadingErr error
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "success",
+			loadingErr: nil,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "error/unable to load the last block",
+			loadingErr: iotest.ErrTimeout,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			isMiningStarted := make(chan struct{})
+			proofer := new(MockProofer)
+			proofer.
+				On("HashEx", mock.Anything, mock.AnythingOfType("blockchain.Block")).
+				Return(func(ctx context.Context, block Block) (string, error) {
+					close(isMiningStarted)
+
+					<-ctx.Done()
+					return "", ctx.Err()
+				})
+			proofer.On("Validate", remoteBlock).Return(nil)
+			proofer.On("Difficulty", "remote hash #1").Return(23, nil)
+
+			storage := new(MockGroupStorage)
+			storage.
+				On("LoadBlocks", nil, 10).
+				Return(BlockGroup{genesisBlock}, 1, nil)
+			storage.On("DeleteBlockGroup", BlockGroup{}).Return(nil)
+			storage.On("StoreBlockGroup", BlockGroup{remoteBlock}).Return(nil)
+			storage.On("LoadLastBlock").Return(remoteBlock, data.loadingErr)
+
+			loader := new(MockLoader)
+			loader.
+				On("LoadBlocks", nil, 10).
+				Return(BlockGroup{remoteBlock, genesisBlock}, 2, nil)
+
+			blockchain := &Blockchain{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock:   func() time.Time { return clock().Add(time.Minute) },
+						Proofer: proofer,
+					},
+					Storage: storage,
+				},
+				lastBlock: genesisBlock,
+			}
+
+			addingErr := make(chan error)
+			go func() {
+				addingErr <- blockchain.AddBlockEx(
+					context.Background(),
+					NewData("local block"),
+				)
+			}()
+			<-isMiningStarted
+
+			mergingErr := blockchain.Merge(loader, 10)
+
+			mock.AssertExpectationsForObjects(test, proofer, storage, loader)
+			data.wantErr(test, mergingErr)
+			select {
+			case err := <-addingErr:
+				assert.ErrorIs(test, err, ErrLastBlockChanged)
+			case <-time.After(time.Second):
+				require.FailNow(test, "the mining isn't canceled")
+			}
+			assert.Equal(test, remoteBlock, blockchain.lastBlock)
+		})
+	}
+}
+
+func TestBlockchain_Merge_withChangedLastBlock(test *testing.T) {
+	genesisBlock := Block{
+		Timestamp: clock(),
+		Data:      NewData("genesis block"),
+		Hash:      "hash #0",
+		PrevHash:  "",
+	}
+	localBlock := Block{
+		Timestamp: clock().Add(time.Minute),
+		Data:      NewData("local block"),
+		Hash:      "local hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+	remoteBlock := Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      NewData("remote block"),
+		Hash:      "remote hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+
+	proofer := new(MockProofer)
+	proofer.
+		On("HashEx", mock.Anything, Block{
+			Timestamp: localBlock.Timestamp,
+			Data:      localBlock.Data,
+			PrevHash:  localBlock.PrevHash,
+			Height:    localBlock.Height,
+		}).
+		Return("local hash #1", nil)
+	proofer.On("Validate", remoteBlock).Return(nil)
+	proofer.On("Difficulty", "remote hash #1").Return(23, nil)
+
+	storage := new(MockGroupStorage)
+	storage.
+		On("LoadBlocks", nil, 10).
+		Return(BlockGroup{genesisBlock}, 1, nil)
+	storage.On("StoreBlock", localBlock).Return(nil)
+
+	blockchain := &Blockchain{
+		dependencies: Dependencies{
+			BlockDependencies: BlockDependencies{
+				Clock:   func() time.Time { return localBlock.Timestamp },
+				Proofer: proofer,
+			},
+			Storage: storage,
+		},
+		lastBlock: genesisBlock,
+	}
+
+	loader := new(MockLoader)
+	loader.
+		On("LoadBlocks", nil, 10).
+		Run(func(mock.Arguments) {
+			// change the last block during the search of differences
+			err := blockchain.AddBlockEx(
+				context.Background(),
+				NewData("local block"),
+			)
+			require.NoError(test, err)
+		}).
+		Return(BlockGroup{remoteBlock, genesisBlock}, 2, nil)
+
+	err := blockchain.Merge(loader, 10)
+
+	mock.AssertExpectationsForObjects(test, proofer, storage, loader)
+	assert.ErrorIs(test, err, ErrLastBlockChanged)
+	assert.Equal(test, localBlock, blockchain.lastBlock)
+}
+
+type indexedGroupStorage struct {
+	*MockGroupStorage
+	*MockIndexedLoader
+}
+
+func (storage indexedGroupStorage) LoadBlocks(
+	cursor interface{},
+	count int,
+) (BlockGroup, interface{}, error) {
+	return storage.MockGroupStorage.LoadBlocks(cursor, count)
+}
+
+type transactionalGroupStorage struct {
+	*MockGroupStorage
+}
+
+func (storage transactionalGroupStorage) ReplaceBlockGroup(
+	deletedBlocks BlockGroup,
+	storedBlocks BlockGroup,
+) error {
+	return storage.Called(deletedBlocks, storedBlocks).Error(0)
+}
+
+func TestBlockchain_Merge_withEmptyRightDifferences(test *testing.T) {
+	blocks := BlockGroup{
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      NewData("data #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+		},
+		{
+			Timestamp: clock(),
+			Data:      NewData("data #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+	}
+
+	storage := new(MockGroupStorage)
+	storage.On("LoadBlocks", nil, 10).Return(blocks, 12, nil)
+
+	loader := new(MockLoader)
+	loader.On("LoadBlocks", nil, 10).Return(blocks[1:], 11, nil)
+
+	proofer := new(MockProofer)
+
+	forkChoice := new(MockForkChoice)
+	forkChoice.
+		On("CompareForks", blocks[:1], BlockGroup{}, proofer).
+		Return(-1, nil)
+
+	blockchain := &Blockchain{
+		dependencies: Dependencies{
+			BlockDependencies: BlockDependencies{
+				Proofer: proofer,
+			},
+			Storage:    storage,
+			ForkChoice: mo.Some[ForkChoice](forkChoice),
+		},
+		lastBlock: blocks[0],
+	}
+	err := blockchain.Merge(loader, 10)
+
+	mock.AssertExpectationsForObjects(test, storage, loader, forkChoice)
+	assert.Equal(test, blocks[0], blockchain.lastBlock)
+	assert.ErrorIs(test, err, ErrInvalidFork)
+}
+
+func TestBlockchain_AddBlockEx_withStateEngine(test *testing.T) {
+	prevBlock := Block{
+		Timestamp: clock(),
+		Data:      NewData("genesis block"),
+		Hash:      "hash #0",
+		PrevHash:  "",
+	}
+	block := Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      NewData("block"),
+		Hash:      "hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+
+	for _, data := range []struct {
+		name          string
+		prepareMocks  func(stateEngine *MockStateEngine, storage *MockGroupStorage)
+		wantLastBlock Block
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			prepareMocks: func(
+				stateEngine *MockStateEngine,
+				storage *MockGroupStorage,
+			) {
+				stateEngine.On("ApplyBlock", block).Return(nil)
+				storage.On("StoreBlock", block).Return(nil)
+			},
+			wantLastBlock: block,
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "error with applying",
+			prepareMocks: func(
+				stateEngine *MockStateEngine,
+				storage *MockGroupStorage,
+			) {
+				stateEngine.On("ApplyBlock", block).Return(iotest.ErrTimeout)
+			},
+			wantLastBlock: prevBlock,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+		{
+			name: "error with storing",
+			prepareMocks: func(
+				stateEngine *MockStateEngine,
+				storage *MockGroupStorage,
+			) {
+				stateEngine.On("ApplyBlock", block).Return(nil)
+				storage.On("StoreBlock", block).Return(iotest.ErrTimeout)
+				stateEngine.On("UndoBlock", block).Return(nil)
+			},
+			wantLastBlock: prevBlock,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := new(MockProofer)
+			proofer.
+				On("HashEx", mock.Anything, mock.AnythingOfType("blockchain.Block")).
+				Return("hash #1", nil)
+
+			stateEngine := new(MockStateEngine)
+			storage := new(MockGroupStorage)
+			data.prepareMocks(stateEngine, storage)
+
+			blockchain := &Blockchain{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Clock:   func() time.Time { return clock().Add(time.Hour) },
+						Proofer: proofer,
+					},
+					Storage:     storage,
+					StateEngine: mo.Some[StateEngine](stateEngine),
+				},
+				lastBlock: prevBlock,
+			}
+			err := blockchain.AddBlockEx(context.Background(), NewData("block"))
+
+			mock.AssertExpectationsForObjects(test, proofer, stateEngine, storage)
+			assert.Equal(test, data.wantLastBlock, blockchain.lastBlock)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestBlockchain_Merge_withStateEngine(test *testing.T) {
+	genesisBlock := Block{
+		Timestamp: clock(),
+		Data:      NewData("genesis block"),
+		Hash:      "hash #0",
+		PrevHash:  "",
+	}
+	localBlock := Block{
+		Timestamp: clock().Add(time.Minute),
+		Data:      NewData("local block"),
+		Hash:      "local hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+	remoteBlocks := BlockGroup{
+		{
+			Timestamp: clock().Add(2 * time.Hour),
+			Data:      NewData("remote block #2"),
+			Hash:      "remote hash #2",
+			PrevHash:  "remote hash #1",
+			Height:    2,
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      NewData("remote block #1"),
+			Hash:      "remote hash #1",
+			PrevHash:  "hash #0",
+			Height:    1,
+		},
+	}
+
+	for _, data := range []struct {
+		name          string
+		prepareMocks  func(stateEngine *MockStateEngine, storage *MockGroupStorage)
+		wantLastBlock Block
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			prepareMocks: func(
+				stateEngine *MockStateEngine,
+				storage *MockGroupStorage,
+			) {
+				stateEngine.On("UndoBlock", localBlock).Return(nil)
+				stateEngine.On("ApplyBlock", remoteBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", remoteBlocks[0]).Return(nil)
+
+				storage.On("DeleteBlockGroup", BlockGroup{localBlock}).Return(nil)
+				storage.On("StoreBlockGroup", remoteBlocks).Return(nil)
+				storage.On("LoadLastBlock").Return(remoteBlocks[0], nil)
+			},
+			wantLastBlock: remoteBlocks[0],
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "error with switching the state",
+			prepareMocks: func(
+				stateEngine *MockStateEngine,
+				storage *MockGroupStorage,
+			) {
+				stateEngine.On("UndoBlock", localBlock).Return(nil)
+				stateEngine.On("ApplyBlock", remoteBlocks[1]).Return(nil)
+				stateEngine.
+					On("ApplyBlock", remoteBlocks[0]).
+					Return(iotest.ErrTimeout)
+				stateEngine.On("UndoBlock", remoteBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", localBlock).Return(nil)
+			},
+			wantLastBlock: localBlock,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+		{
+			name: "error with replacing the blocks",
+			prepareMocks: func(
+				stateEngine *MockStateEngine,
+				storage *MockGroupStorage,
+			) {
+				stateEngine.On("UndoBlock", localBlock).Return(nil)
+				stateEngine.On("ApplyBlock", remoteBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", remoteBlocks[0]).Return(nil)
+
+				storage.
+					On("DeleteBlockGroup", BlockGroup{localBlock}).
+					Return(iotest.ErrTimeout)
+
+				stateEngine.On("UndoBlock", remoteBlocks[0]).Return(nil)
+				stateEngine.On("UndoBlock", remoteBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", localBlock).Return(nil)
+			},
+			wantLastBlock: localBlock,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := new(MockProofer)
+			proofer.On("Validate", remoteBlocks[1]).Return(nil)
+			proofer.On("Validate", remoteBlocks[0]).Return(nil)
+			proofer.On("Difficulty", "local hash #1").Return(23, nil)
+			proofer.On("Difficulty", "remote hash #1").Return(23, nil)
+			proofer.On("Difficulty", "remote hash #2").Return(23, nil)
+
+			stateEngine := new(MockStateEngine)
+			storage := new(MockGroupStorage)
+			storage.
+				On("LoadBlocks", nil, 10).
+				Return(BlockGroup{localBlock, genesisBlock}, 2, nil)
+			data.prepareMocks(stateEngine, storage)
+
+			loader := new(MockLoader)
+			loader.
+				On("LoadBlocks", nil, 10).
+				Return(append(remoteBlocks, genesisBlock), 3, nil)
+
+			blockchain := &Blockchain{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: proofer,
+					},
+					Storage:     storage,
+					StateEngine: mo.Some[StateEngine](stateEngine),
+				},
+				lastBlock: localBlock,
+			}
+			err := blockchain.Merge(loader, 10)
+
+			mock.AssertExpectationsForObjects(
+				test,
+				proofer,
+				stateEngine,
+				storage,
+				loader,
+			)
+			assert.Equal(test, data.wantLastBlock, blockchain.lastBlock)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestBlockchain_MergeEx(test *testing.T) {
+	genesisBlock := Block{
+		Timestamp: clock(),
+		Data:      NewData("genesis block"),
+		Hash:      "hash #0",
+		PrevHash:  "",
+	}
+	localBlock := Block{
+		Timestamp: clock().Add(time.Minute),
+		Data:      NewData("local block"),
+		Hash:      "local hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+	remoteBlocks := BlockGroup{
+		{
+			Timestamp: clock().Add(2 * time.Hour),
+			Data:      NewData("remote block #2"),
+			Hash:      "remote hash #2",
+			PrevHash:  "remote hash #1",
+			Height:    2,
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      NewData("remote block #1"),
+			Hash:      "remote hash #1",
+			PrevHash:  "hash #0",
+			Height:    1,
+		},
+	}
+
+	type fields struct {
+		maxReorgDepth mo.Option[int]
+	}
+
+	for _, data := range []struct {
+		name          string
+		fields        fields
+		prepareMocks  func(proofer *MockProofer, storage *MockGroupStorage)
+		wantReport    ReorgReport
+		wantLastBlock Block
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/merged fork",
+			fields: fields{
+				maxReorgDepth: mo.Some(1),
+			},
+			prepareMocks: func(proofer *MockProofer, storage *MockGroupStorage) {
+				proofer.On("Difficulty", "local hash #1").Return(23, nil)
+
+				storage.On("DeleteBlockGroup", BlockGroup{localBlock}).Return(nil)
+				storage.On("StoreBlockGroup", remoteBlocks).Return(nil)
+				storage.On("LoadLastBlock").Return(remoteBlocks[0], nil)
+			},
+			wantReport: ReorgReport{
+				CommonBlock:   genesisBlock,
+				RemovedBlocks: BlockGroup{localBlock},
+				AddedBlocks:   remoteBlocks,
+				LeftWork:      big.NewInt(23),
+				RightWork:     big.NewInt(84),
+			},
+			wantLastBlock: remoteBlocks[0],
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "success/rejected fork",
+			fields: fields{
+				maxReorgDepth: mo.None[int](),
+			},
+			prepareMocks: func(proofer *MockProofer, storage *MockGroupStorage) {
+				proofer.On("Difficulty", "local hash #1").Return(100, nil)
+			},
+			wantReport: ReorgReport{
+				CommonBlock:   genesisBlock,
+				RemovedBlocks: nil,
+				AddedBlocks:   nil,
+				LeftWork:      big.NewInt(100),
+				RightWork:     big.NewInt(84),
+			},
+			wantLastBlock: localBlock,
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "error/too deep reorganization",
+			fields: fields{
+				maxReorgDepth: mo.Some(0),
+			},
+			prepareMocks: func(proofer *MockProofer, storage *MockGroupStorage) {
+				proofer.On("Difficulty", "local hash #1").Return(23, nil)
+			},
+			wantReport: ReorgReport{
+				CommonBlock:   genesisBlock,
+				RemovedBlocks: nil,
+				AddedBlocks:   nil,
+				LeftWork:      big.NewInt(23),
+				RightWork:     big.NewInt(84),
+			},
+			wantLastBlock: localBlock,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrReorgTooDeep)
+			},
+		},
+		{
+			name: "error/unable to calculate the difficulty",
+			fields: fields{
+				maxReorgDepth: mo.None[int](),
+			},
+			prepareMocks: func(proofer *MockProofer, storage *MockGroupStorage) {
+				proofer.On("Difficulty", "local hash #1").Return(0, iotest.ErrTimeout)
+			},
+			wantReport: ReorgReport{
+				CommonBlock:   genesisBlock,
+				RemovedBlocks: nil,
+				AddedBlocks:   nil,
+				LeftWork:      nil,
+				RightWork:     nil,
+			},
+			wantLastBlock: localBlock,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := new(MockProofer)
+			proofer.On("Validate", remoteBlocks[1]).Return(nil)
+			proofer.On("Validate", remoteBlocks[0]).Return(nil)
+			proofer.On("Difficulty", "remote hash #1").Return(42, nil)
+			proofer.On("Difficulty", "remote hash #2").Return(42, nil)
+
+			storage := new(MockGroupStorage)
+			storage.
+				On("LoadBlocks", nil, 10).
+				Return(BlockGroup{localBlock, genesisBlock}, 2, nil)
+			data.prepareMocks(proofer, storage)
+
+			loader := new(MockLoader)
+			loader.
+				On("LoadBlocks", nil, 10).
+				Return(append(remoteBlocks, genesisBlock), 3, nil)
+
+			blockchain := &Blockchain{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: proofer,
+					},
+					Storage:       storage,
+					MaxReorgDepth: data.fields.maxReorgDepth,
+				},
+				lastBlock: localBlock,
+			}
+			gotReport, gotErr := blockchain.MergeEx(loader, 10)
+
+			mock.AssertExpectationsForObjects(test, storage, loader)
+			assert.Equal(test, data.wantReport, gotReport)
+			assert.Equal(test, data.wantLastBlock, blockchain.lastBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestBlockchain_MergeEx_withoutFork(test *testing.T) {
+	storage := new(MockGroupStorage)
+	storage.On("LoadBlocks", nil, 10).Return(nil, nil, iotest.ErrTimeout)
+
+	blockchain := &Blockchain{
+		dependencies: Dependencies{
+			BlockDependencies: BlockDependencies{
+				Proofer: new(MockProofer),
+			},
+			Storage: storage,
+		},
+	}
+	gotReport, gotErr := blockchain.MergeEx(new(MockLoader), 10)
+
+	mock.AssertExpectationsForObjects(test, storage)
+	assert.Equal(test, ReorgReport{}, gotReport)
+	assert.ErrorIs(test, gotErr, iotest.ErrTimeout)
+}
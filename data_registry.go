@@ -0,0 +1,158 @@
+package blockchain
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// TextDataType ...
+//
+// It's the type of block data that doesn't implement the [TypedData]
+// interface. Such data is encoded via its text representation and decoded
+// as a string wrapped via the [NewData] function.
+const TextDataType = "text"
+
+// ErrUnknownDataType ...
+var ErrUnknownDataType = errors.New("unknown data type")
+
+type encodedData struct {
+	dataType string
+	payload  []byte
+	isBinary bool
+}
+
+var (
+	dataDecoderLock sync.RWMutex
+	dataDecoders    = make(map[string]DataDecoder)
+)
+
+// TypedData ...
+//
+// Block data implementing this interface is restored with its concrete type
+// by the decoder registered for its type via the [RegisterDataDecoder]
+// function.
+//
+// The data is encoded via the [encoding.BinaryMarshaler] interface,
+// the [encoding.TextMarshaler] interface or its string representation,
+// in that order of priority.
+type TypedData interface {
+	Data
+
+	DataType() string
+}
+
+// DataDecoder ...
+type DataDecoder func(rawData []byte) (Data, error)
+
+// RegisterDataDecoder ...
+//
+// It panics if the data type is empty or a decoder is already registered
+// for it, so it's supposed to be called at the initialization.
+func RegisterDataDecoder(dataType string, decoder DataDecoder) {
+	dataDecoderLock.Lock()
+	defer dataDecoderLock.Unlock()
+
+	if dataType == "" {
+		panic("blockchain: the data type is empty")
+	}
+	if _, isFound := dataDecoders[dataType]; isFound {
+		panic("blockchain: the decoder is already registered for data type " +
+			dataType)
+	}
+
+	dataDecoders[dataType] = decoder
+}
+
+// EncodeData ...
+//
+// It encodes the block data along with its type, so the result can be
+// decoded via the [DecodeData] function.
+func EncodeData(data Data) ([]byte, error) {
+	typedData, err := encodeTypedData(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer []byte
+	buffer = appendLengthPrefixed(buffer, typedData.dataType)
+	buffer = append(buffer, typedData.payload...)
+
+	return buffer, nil
+}
+
+// DecodeData ...
+func DecodeData(rawData []byte) (Data, error) {
+	rawDataType, payload, err := readLengthPrefixed(rawData)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the data type: %w", err)
+	}
+
+	return decodeTypedData(string(rawDataType), payload)
+}
+
+func init() {
+	RegisterDataDecoder(TextDataType, func(rawData []byte) (Data, error) {
+		return NewData(string(rawData)), nil
+	})
+}
+
+func encodeTypedData(data Data) (encodedData, error) {
+	result := encodedData{dataType: TextDataType}
+	if typedData, ok := data.(TypedData); ok {
+		result.dataType = typedData.DataType()
+	}
+
+	var err error
+	switch marshaler := data.(type) {
+	case encoding.BinaryMarshaler:
+		result.payload, err = marshaler.MarshalBinary()
+		result.isBinary = true
+	case encoding.TextMarshaler:
+		result.payload, err = marshaler.MarshalText()
+	default:
+		result.payload = []byte(data.String())
+	}
+	if err != nil {
+		return encodedData{}, fmt.Errorf("unable to marshal the data: %w", err)
+	}
+
+	return result, nil
+}
+
+func decodeTypedData(dataType string, payload []byte) (Data, error) {
+	dataDecoderLock.RLock()
+	decoder, isFound := dataDecoders[dataType]
+	dataDecoderLock.RUnlock()
+
+	if !isFound {
+		return nil, fmt.Errorf("data type %q: %w", dataType, ErrUnknownDataType)
+	}
+
+	data, err := decoder(payload)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"unable to decode the data of type %q: %w",
+			dataType,
+			err,
+		)
+	}
+
+	return data, nil
+}
+
+func readLengthPrefixed(buffer []byte) (value []byte, rest []byte, err error) {
+	length, lengthSize := binary.Uvarint(buffer)
+	if lengthSize <= 0 {
+		return nil, nil, errors.New("unable to read the length")
+	}
+
+	buffer = buffer[lengthSize:]
+	if uint64(len(buffer)) < length {
+		return nil, nil, errors.New("the value is truncated")
+	}
+
+	return buffer[:length], buffer[length:], nil
+}
@@ -0,0 +1,209 @@
+package blockchain
+
+import (
+	"strconv"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testDataType = "test"
+
+func init() {
+	RegisterDataDecoder(testDataType, func(rawData []byte) (Data, error) {
+		value, err := strconv.Atoi(string(rawData))
+		if err != nil {
+			return nil, err
+		}
+
+		return testTypedData{value: value}, nil
+	})
+}
+
+func TestRegisterDataDecoder(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		dataType string
+	}{
+		{
+			name:     "with an empty data type",
+			dataType: "",
+		},
+		{
+			name:     "with an already registered data type",
+			dataType: TextDataType,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			assert.Panics(test, func() {
+				RegisterDataDecoder(data.dataType, func([]byte) (Data, error) {
+					return nil, nil
+				})
+			})
+		})
+	}
+}
+
+func TestEncodeData(test *testing.T) {
+	type args struct {
+		data Data
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with the encoding.TextMarshaler interface",
+			args: args{
+				data: NewData("data"),
+			},
+			want:    []byte("\x04textdata"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success without the encoding.TextMarshaler interface",
+			args: args{
+				data: func() Data {
+					data := new(MockData)
+					data.On("String").Return("data")
+
+					return data
+				}(),
+			},
+			want:    []byte("\x04textdata"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the TypedData interface",
+			args: args{
+				data: testTypedData{value: 23},
+			},
+			want:    []byte("\x04test23"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				data: NewData(func() TextMarshaler {
+					marshaler := new(MockTextMarshaler)
+					marshaler.On("MarshalText").Return(nil, iotest.ErrTimeout)
+
+					return marshaler
+				}()),
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, gotErr := EncodeData(data.args.data)
+
+			if mockData, ok := data.args.data.(*MockData); ok {
+				mock.AssertExpectationsForObjects(test, mockData)
+			}
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestDecodeData(test *testing.T) {
+	type args struct {
+		rawData []byte
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    Data
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with the text data type",
+			args: args{
+				rawData: []byte("\x04textdata"),
+			},
+			want:    NewData("data"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the registered data type",
+			args: args{
+				rawData: []byte("\x04test23"),
+			},
+			want:    testTypedData{value: 23},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error with the truncated data type",
+			args: args{
+				rawData: []byte("\x04te"),
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with the unknown data type",
+			args: args{
+				rawData: []byte("\x07unknowndata"),
+			},
+			want: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnknownDataType)
+			},
+		},
+		{
+			name: "error with the decoder",
+			args: args{
+				rawData: []byte("\x04testdata"),
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, gotErr := DecodeData(data.args.rawData)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestDecodeData_withMerkleData(test *testing.T) {
+	data := NewMerkleData([]Data{
+		NewData("one"),
+		testTypedData{value: 23},
+		NewMerkleData([]Data{NewData("two")}),
+	})
+
+	rawData, err := EncodeData(data)
+	require.NoError(test, err)
+
+	got, gotErr := DecodeData(rawData)
+
+	assert.Equal(test, data, got)
+	assert.NoError(test, gotErr)
+}
+
+type testTypedData struct {
+	value int
+}
+
+func (data testTypedData) DataType() string {
+	return testDataType
+}
+
+func (data testTypedData) String() string {
+	return strconv.Itoa(data.value)
+}
+
+func (data testTypedData) Equal(anotherData Data) bool {
+	return data == anotherData
+}
@@ -0,0 +1,306 @@
+package blockchain
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/samber/mo"
+)
+
+const defaultEventBufferSize = 16
+
+// Event ...
+//
+// It's one of the following events: [BlockAddedEvent], [BlocksRevertedEvent],
+// [BlocksAppliedEvent] or [MergeRejectedEvent].
+type Event interface {
+	isEvent()
+}
+
+// BlockAddedEvent ...
+//
+// It's emitted by the [Blockchain.AddBlockEx] method.
+type BlockAddedEvent struct {
+	Block Block
+}
+
+// BlocksRevertedEvent ...
+//
+// It's emitted by the merging for the deleted orphan blocks (the last block
+// goes first). It's followed by the [BlocksAppliedEvent] one.
+type BlocksRevertedEvent struct {
+	Blocks BlockGroup
+}
+
+// BlocksAppliedEvent ...
+//
+// It's emitted by the merging for the stored blocks of the fork (the last
+// block goes first).
+type BlocksAppliedEvent struct {
+	Blocks BlockGroup
+}
+
+// MergeRejectedEvent ...
+//
+// It's emitted by the merging if the found fork isn't merged for any reason.
+// The error is nil if the current blocks are preferred by the fork choice.
+type MergeRejectedEvent struct {
+	LeftDifferences  BlockGroup
+	RightDifferences BlockGroup
+	Err              error
+}
+
+func (BlockAddedEvent) isEvent()     {}
+func (BlocksRevertedEvent) isEvent() {}
+func (BlocksAppliedEvent) isEvent()  {}
+func (MergeRejectedEvent) isEvent()  {}
+
+// EventHandler ...
+type EventHandler func(event Event)
+
+// OverflowPolicy ...
+//
+// It defines the behavior of the publishing when the buffer
+// of the subscription is full.
+type OverflowPolicy int
+
+// ...
+const (
+	// the publishing waits for the subscriber, so the changes
+	// of the blockchain are slowed down to its pace
+	BlockOnOverflow OverflowPolicy = iota
+	// the published event is dropped
+	DropNewestOnOverflow
+	// the oldest buffered event is dropped to make room for the published one
+	DropOldestOnOverflow
+)
+
+// SubscriptionParams ...
+//
+// The buffer size is 16 and the overflow policy is [BlockOnOverflow]
+// by default.
+type SubscriptionParams struct {
+	BufferSize     mo.Option[int]
+	OverflowPolicy mo.Option[OverflowPolicy]
+}
+
+// Subscription ...
+type Subscription struct {
+	bus            *eventBus
+	id             int
+	overflowPolicy OverflowPolicy
+	isHandled      bool
+
+	events            chan Event
+	done              chan struct{}
+	unsubscribeOnce   sync.Once
+	droppedEventCount atomic.Int64
+}
+
+// Events ...
+//
+// The channel is closed on unsubscribing. It's nil for the subscriptions
+// with a handler.
+func (subscription *Subscription) Events() <-chan Event {
+	if subscription.isHandled {
+		return nil
+	}
+
+	return subscription.events
+}
+
+// DroppedEventCount ...
+//
+// It allows the subscriber to detect the lost events and to resynchronize
+// with the blockchain.
+func (subscription *Subscription) DroppedEventCount() int {
+	return int(subscription.droppedEventCount.Load())
+}
+
+// Unsubscribe ...
+//
+// It's safe to call it several times and from the handler. The blocked
+// publishing for the subscription is released.
+func (subscription *Subscription) Unsubscribe() {
+	subscription.unsubscribeOnce.Do(func() {
+		close(subscription.done)
+		subscription.bus.unsubscribe(subscription)
+	})
+}
+
+// the handler isn't called after unsubscribing
+func (subscription *Subscription) handle(handler EventHandler) {
+	for {
+		select {
+		case event, ok := <-subscription.events:
+			if !ok {
+				return
+			}
+
+			// the selection above is random, so the unsubscribing is checked again
+			select {
+			case <-subscription.done:
+				return
+			default:
+			}
+
+			handler(event)
+
+		case <-subscription.done:
+			return
+		}
+	}
+}
+
+func (subscription *Subscription) deliver(event Event) {
+	switch subscription.overflowPolicy {
+	case DropNewestOnOverflow:
+		select {
+		case subscription.events <- event:
+		default:
+			subscription.droppedEventCount.Add(1)
+		}
+
+	case DropOldestOnOverflow:
+		for {
+			select {
+			case subscription.events <- event:
+				return
+			default:
+			}
+
+			select {
+			case <-subscription.events:
+				subscription.droppedEventCount.Add(1)
+			default:
+			}
+		}
+
+	default:
+		select {
+		case subscription.events <- event:
+		case <-subscription.done:
+		}
+	}
+}
+
+// it's usable as a zero value
+type eventBus struct {
+	// the publishing holds the read lock during the delivery, so the unsubscribing
+	// closes the channel of the subscription only after the delivery is finished
+	lock          sync.RWMutex
+	subscriptions map[int]*Subscription
+	lastID        int
+
+	// the events are queued under the lock of the blockchain in the order
+	// of the changes and are published after its releasing, so the subscribers
+	// can load the blocks during the delivery
+	queueLock sync.Mutex
+	queue     []Event
+	// it serializes the flushing, so the queued events keep their order
+	flushLock sync.Mutex
+}
+
+func (bus *eventBus) subscribe(
+	params SubscriptionParams,
+	handler mo.Option[EventHandler],
+) (*Subscription, error) {
+	bufferSize := params.BufferSize.OrElse(defaultEventBufferSize)
+	if bufferSize < 0 {
+		return nil, fmt.Errorf("the buffer size %d is negative", bufferSize)
+	}
+
+	overflowPolicy := params.OverflowPolicy.OrElse(BlockOnOverflow)
+	switch overflowPolicy {
+	case BlockOnOverflow:
+	case DropNewestOnOverflow, DropOldestOnOverflow:
+		if bufferSize == 0 {
+			return nil, fmt.Errorf(
+				"the overflow policy %d requires a positive buffer size",
+				overflowPolicy,
+			)
+		}
+	default:
+		return nil, fmt.Errorf("the overflow policy %d is unknown", overflowPolicy)
+	}
+
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	if bus.subscriptions == nil {
+		bus.subscriptions = make(map[int]*Subscription)
+	}
+
+	bus.lastID++
+	subscription := &Subscription{
+		bus:            bus,
+		id:             bus.lastID,
+		overflowPolicy: overflowPolicy,
+		isHandled:      handler.IsPresent(),
+		events:         make(chan Event, bufferSize),
+		done:           make(chan struct{}),
+	}
+	bus.subscriptions[subscription.id] = subscription
+
+	if handler, isPresent := handler.Get(); isPresent {
+		go subscription.handle(handler)
+	}
+
+	return subscription, nil
+}
+
+func (bus *eventBus) unsubscribe(subscription *Subscription) {
+	bus.lock.Lock()
+	defer bus.lock.Unlock()
+
+	delete(bus.subscriptions, subscription.id)
+	close(subscription.events)
+}
+
+func (bus *eventBus) publish(event Event) {
+	bus.lock.RLock()
+	defer bus.lock.RUnlock()
+
+	for _, subscription := range bus.subscriptions {
+		subscription.deliver(event)
+	}
+}
+
+func (bus *eventBus) enqueue(events ...Event) {
+	bus.queueLock.Lock()
+	defer bus.queueLock.Unlock()
+
+	bus.queue = append(bus.queue, events...)
+}
+
+// it should be called without the lock of the blockchain; it also publishes
+// the events queued by the concurrent changes
+func (bus *eventBus) flush() {
+	bus.flushLock.Lock()
+	defer bus.flushLock.Unlock()
+
+	for {
+		event, ok := bus.dequeue()
+		if !ok {
+			return
+		}
+
+		bus.publish(event)
+	}
+}
+
+func (bus *eventBus) dequeue() (Event, bool) {
+	bus.queueLock.Lock()
+	defer bus.queueLock.Unlock()
+
+	if len(bus.queue) == 0 {
+		return nil, false
+	}
+
+	event := bus.queue[0]
+	bus.queue[0] = nil
+	bus.queue = bus.queue[1:]
+
+	return event, true
+}
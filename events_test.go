@@ -0,0 +1,522 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockchain_Subscribe(test *testing.T) {
+	type args struct {
+		params SubscriptionParams
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    assert.ValueAssertionFunc
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/default params",
+			args: args{
+				params: SubscriptionParams{},
+			},
+			want:    assert.NotNil,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/unbuffered subscription",
+			args: args{
+				params: SubscriptionParams{
+					BufferSize: mo.Some(0),
+				},
+			},
+			want:    assert.NotNil,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/negative buffer size",
+			args: args{
+				params: SubscriptionParams{
+					BufferSize: mo.Some(-1),
+				},
+			},
+			want:    assert.Nil,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/dropping without a buffer",
+			args: args{
+				params: SubscriptionParams{
+					BufferSize:     mo.Some(0),
+					OverflowPolicy: mo.Some(DropOldestOnOverflow),
+				},
+			},
+			want:    assert.Nil,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/unknown overflow policy",
+			args: args{
+				params: SubscriptionParams{
+					OverflowPolicy: mo.Some(OverflowPolicy(23)),
+				},
+			},
+			want:    assert.Nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			blockchain := &Blockchain{}
+			got, err := blockchain.Subscribe(data.args.params)
+
+			data.want(test, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestSubscription_overflowPolicies(test *testing.T) {
+	events := []Event{
+		BlockAddedEvent{Block: Block{Hash: "hash #1"}},
+		BlockAddedEvent{Block: Block{Hash: "hash #2"}},
+		BlockAddedEvent{Block: Block{Hash: "hash #3"}},
+	}
+
+	for _, data := range []struct {
+		name                  string
+		overflowPolicy        OverflowPolicy
+		wantEvents            []Event
+		wantDroppedEventCount int
+	}{
+		{
+			name:                  "dropping the newest events",
+			overflowPolicy:        DropNewestOnOverflow,
+			wantEvents:            events[:1],
+			wantDroppedEventCount: 2,
+		},
+		{
+			name:                  "dropping the oldest events",
+			overflowPolicy:        DropOldestOnOverflow,
+			wantEvents:            events[2:],
+			wantDroppedEventCount: 2,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			blockchain := &Blockchain{}
+			subscription, err := blockchain.Subscribe(SubscriptionParams{
+				BufferSize:     mo.Some(1),
+				OverflowPolicy: mo.Some(data.overflowPolicy),
+			})
+			require.NoError(test, err)
+
+			for _, event := range events {
+				blockchain.events.publish(event)
+			}
+			subscription.Unsubscribe()
+
+			var gotEvents []Event
+			for event := range subscription.Events() {
+				gotEvents = append(gotEvents, event)
+			}
+
+			assert.Equal(test, data.wantEvents, gotEvents)
+			assert.Equal(
+				test,
+				data.wantDroppedEventCount,
+				subscription.DroppedEventCount(),
+			)
+		})
+	}
+}
+
+func TestSubscription_blockingOnOverflow(test *testing.T) {
+	blockchain := &Blockchain{}
+	subscription, err := blockchain.Subscribe(SubscriptionParams{
+		BufferSize: mo.Some(1),
+	})
+	require.NoError(test, err)
+
+	blockchain.events.publish(BlockAddedEvent{Block: Block{Hash: "hash #1"}})
+
+	isPublished := make(chan struct{})
+	go func() {
+		defer close(isPublished)
+
+		blockchain.events.publish(BlockAddedEvent{Block: Block{Hash: "hash #2"}})
+	}()
+
+	select {
+	case <-isPublished:
+		require.Fail(test, "the publishing isn't blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	assert.Equal(
+		test,
+		BlockAddedEvent{Block: Block{Hash: "hash #1"}},
+		<-subscription.Events(),
+	)
+	<-isPublished
+	assert.Equal(
+		test,
+		BlockAddedEvent{Block: Block{Hash: "hash #2"}},
+		<-subscription.Events(),
+	)
+	assert.Zero(test, subscription.DroppedEventCount())
+}
+
+func TestSubscription_Unsubscribe(test *testing.T) {
+	blockchain := &Blockchain{}
+	subscription, err := blockchain.Subscribe(SubscriptionParams{
+		BufferSize: mo.Some(0),
+	})
+	require.NoError(test, err)
+
+	isPublished := make(chan struct{})
+	go func() {
+		defer close(isPublished)
+
+		blockchain.events.publish(BlockAddedEvent{Block: Block{Hash: "hash #1"}})
+	}()
+
+	// it releases the blocked publishing
+	subscription.Unsubscribe()
+	subscription.Unsubscribe()
+	<-isPublished
+
+	blockchain.events.publish(BlockAddedEvent{Block: Block{Hash: "hash #2"}})
+
+	_, isOpen := <-subscription.Events()
+	assert.False(test, isOpen)
+}
+
+func TestBlockchain_SubscribeFunc(test *testing.T) {
+	blockchain := &Blockchain{}
+
+	var lock sync.Mutex
+	var gotEvents []Event
+	isHandled := make(chan struct{})
+	subscription, err := blockchain.SubscribeFunc(
+		func(event Event) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			gotEvents = append(gotEvents, event)
+			if len(gotEvents) == 2 {
+				close(isHandled)
+			}
+		},
+		SubscriptionParams{},
+	)
+	require.NoError(test, err)
+	require.Nil(test, subscription.Events())
+
+	blockchain.events.publish(BlockAddedEvent{Block: Block{Hash: "hash #1"}})
+	blockchain.events.publish(BlockAddedEvent{Block: Block{Hash: "hash #2"}})
+	<-isHandled
+
+	subscription.Unsubscribe()
+	blockchain.events.publish(BlockAddedEvent{Block: Block{Hash: "hash #3"}})
+
+	lock.Lock()
+	defer lock.Unlock()
+
+	wantEvents := []Event{
+		BlockAddedEvent{Block: Block{Hash: "hash #1"}},
+		BlockAddedEvent{Block: Block{Hash: "hash #2"}},
+	}
+	assert.Equal(test, wantEvents, gotEvents)
+}
+
+func TestBlockchain_AddBlockEx_withEvents(test *testing.T) {
+	proofer := new(MockProofer)
+	proofer.
+		On("HashEx", mock.Anything, mock.AnythingOfType("blockchain.Block")).
+		Return("hash #1", nil)
+
+	storage := new(MockGroupStorage)
+	storage.On("StoreBlock", mock.AnythingOfType("blockchain.Block")).Return(nil)
+
+	blockchain := &Blockchain{
+		dependencies: Dependencies{
+			BlockDependencies: BlockDependencies{
+				Clock:   func() time.Time { return clock().Add(time.Hour) },
+				Proofer: proofer,
+			},
+			Storage: storage,
+		},
+		lastBlock: Block{
+			Timestamp: clock(),
+			Data:      NewData("genesis block"),
+			Hash:      "hash #0",
+			PrevHash:  "",
+		},
+	}
+	subscription, err := blockchain.Subscribe(SubscriptionParams{})
+	require.NoError(test, err)
+
+	err = blockchain.AddBlockEx(context.Background(), NewData("block"))
+	require.NoError(test, err)
+
+	subscription.Unsubscribe()
+
+	var gotEvents []Event
+	for event := range subscription.Events() {
+		gotEvents = append(gotEvents, event)
+	}
+
+	mock.AssertExpectationsForObjects(test, proofer, storage)
+	wantEvents := []Event{
+		BlockAddedEvent{
+			Block: Block{
+				Timestamp: clock().Add(time.Hour),
+				Data:      NewData("block"),
+				Hash:      "hash #1",
+				PrevHash:  "hash #0",
+				Height:    1,
+			},
+		},
+	}
+	assert.Equal(test, wantEvents, gotEvents)
+}
+
+func TestBlockchain_Merge_withEvents(test *testing.T) {
+	genesisBlock := Block{
+		Timestamp: clock(),
+		Data:      NewData("genesis block"),
+		Hash:      "hash #0",
+		PrevHash:  "",
+	}
+	localBlock := Block{
+		Timestamp: clock().Add(time.Minute),
+		Data:      NewData("local block"),
+		Hash:      "local hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+	remoteBlock := Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      NewData("remote block"),
+		Hash:      "remote hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+
+	for _, data := range []struct {
+		name         string
+		prepareMocks func(proofer *MockProofer, storage *MockGroupStorage)
+		wantEvents   []Event
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			prepareMocks: func(proofer *MockProofer, storage *MockGroupStorage) {
+				proofer.On("Validate", remoteBlock).Return(nil)
+				proofer.On("Difficulty", "local hash #1").Return(23, nil)
+				proofer.On("Difficulty", "remote hash #1").Return(42, nil)
+
+				storage.On("DeleteBlockGroup", BlockGroup{localBlock}).Return(nil)
+				storage.On("StoreBlockGroup", BlockGroup{remoteBlock}).Return(nil)
+				storage.On("LoadLastBlock").Return(remoteBlock, nil)
+			},
+			wantEvents: []Event{
+				BlocksRevertedEvent{Blocks: BlockGroup{localBlock}},
+				BlocksAppliedEvent{Blocks: BlockGroup{remoteBlock}},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "rejection/local blocks are preferred",
+			prepareMocks: func(proofer *MockProofer, storage *MockGroupStorage) {
+				proofer.On("Validate", remoteBlock).Return(nil)
+				proofer.On("Difficulty", "local hash #1").Return(42, nil)
+				proofer.On("Difficulty", "remote hash #1").Return(23, nil)
+			},
+			wantEvents: []Event{
+				MergeRejectedEvent{
+					LeftDifferences:  BlockGroup{localBlock},
+					RightDifferences: BlockGroup{remoteBlock},
+					Err:              nil,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "rejection/unable to replace the blocks",
+			prepareMocks: func(proofer *MockProofer, storage *MockGroupStorage) {
+				proofer.On("Validate", remoteBlock).Return(nil)
+				proofer.On("Difficulty", "local hash #1").Return(23, nil)
+				proofer.On("Difficulty", "remote hash #1").Return(42, nil)
+
+				storage.
+					On("DeleteBlockGroup", BlockGroup{localBlock}).
+					Return(iotest.ErrTimeout)
+			},
+			wantEvents: []Event{
+				MergeRejectedEvent{
+					LeftDifferences:  BlockGroup{localBlock},
+					RightDifferences: BlockGroup{remoteBlock},
+					Err: fmt.Errorf(
+						"unable to delete the left differences: %w",
+						iotest.ErrTimeout,
+					),
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := new(MockProofer)
+			storage := new(MockGroupStorage)
+			storage.
+				On("LoadBlocks", nil, 10).
+				Return(BlockGroup{localBlock, genesisBlock}, 2, nil)
+			data.prepareMocks(proofer, storage)
+
+			loader := new(MockLoader)
+			loader.
+				On("LoadBlocks", nil, 10).
+				Return(BlockGroup{remoteBlock, genesisBlock}, 2, nil)
+
+			blockchain := &Blockchain{
+				dependencies: Dependencies{
+					BlockDependencies: BlockDependencies{
+						Proofer: proofer,
+					},
+					Storage: storage,
+				},
+				lastBlock: localBlock,
+			}
+			subscription, err := blockchain.Subscribe(SubscriptionParams{})
+			require.NoError(test, err)
+
+			err = blockchain.Merge(loader, 10)
+			subscription.Unsubscribe()
+
+			var gotEvents []Event
+			for event := range subscription.Events() {
+				gotEvents = append(gotEvents, event)
+			}
+
+			mock.AssertExpectationsForObjects(test, proofer, storage, loader)
+			assert.Equal(test, data.wantEvents, gotEvents)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestBlockchain_Merge_withLoadingHandler(test *testing.T) {
+	genesisBlock := Block{
+		Timestamp: clock(),
+		Data:      NewData("genesis block"),
+		Hash:      "hash #0",
+		PrevHash:  "",
+	}
+	localBlock := Block{
+		Timestamp: clock().Add(time.Minute),
+		Data:      NewData("local block"),
+		Hash:      "local hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+	remoteBlock := Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      NewData("remote block"),
+		Hash:      "remote hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+
+	proofer := new(MockProofer)
+	proofer.On("Validate", remoteBlock).Return(nil)
+	proofer.On("Difficulty", "local hash #1").Return(23, nil)
+	proofer.On("Difficulty", "remote hash #1").Return(42, nil)
+
+	storage := new(MockGroupStorage)
+	storage.
+		On("LoadBlocks", nil, 10).
+		Return(BlockGroup{localBlock, genesisBlock}, 2, nil)
+	storage.On("DeleteBlockGroup", BlockGroup{localBlock}).Return(nil)
+	storage.On("StoreBlockGroup", BlockGroup{remoteBlock}).Return(nil)
+	storage.On("LoadLastBlock").Return(remoteBlock, nil)
+	storage.
+		On("LoadBlocks", nil, 1).
+		Return(BlockGroup{remoteBlock}, 1, nil)
+
+	loader := new(MockLoader)
+	loader.
+		On("LoadBlocks", nil, 10).
+		Return(BlockGroup{remoteBlock, genesisBlock}, 2, nil)
+
+	blockchain := &Blockchain{
+		dependencies: Dependencies{
+			BlockDependencies: BlockDependencies{
+				Proofer: proofer,
+			},
+			Storage: storage,
+		},
+		lastBlock: localBlock,
+	}
+
+	type handledEvent struct {
+		event      Event
+		lastBlocks BlockGroup
+	}
+
+	// the unbuffered subscription waits for the handler, so the next event
+	// is published only after the loading of the blocks by the handler
+	handledEvents := make(chan handledEvent, 2)
+	subscription, err := blockchain.SubscribeFunc(
+		func(event Event) {
+			lastBlocks, _, err := blockchain.LoadBlocks(nil, 1)
+			assert.NoError(test, err)
+
+			handledEvents <- handledEvent{event: event, lastBlocks: lastBlocks}
+		},
+		SubscriptionParams{BufferSize: mo.Some(0)},
+	)
+	require.NoError(test, err)
+	defer subscription.Unsubscribe()
+
+	isMerged := make(chan error)
+	go func() {
+		isMerged <- blockchain.Merge(loader, 10)
+	}()
+
+	select {
+	case err := <-isMerged:
+		require.NoError(test, err)
+	case <-time.After(time.Second):
+		require.FailNow(test, "the merging is deadlocked")
+	}
+
+	var gotEvents []handledEvent
+	for range 2 {
+		gotEvents = append(gotEvents, <-handledEvents)
+	}
+
+	mock.AssertExpectationsForObjects(test, proofer, storage, loader)
+	wantEvents := []handledEvent{
+		{
+			event:      BlocksRevertedEvent{Blocks: BlockGroup{localBlock}},
+			lastBlocks: BlockGroup{remoteBlock},
+		},
+		{
+			event:      BlocksAppliedEvent{Blocks: BlockGroup{remoteBlock}},
+			lastBlocks: BlockGroup{remoteBlock},
+		},
+	}
+	assert.Equal(test, wantEvents, gotEvents)
+}
@@ -59,39 +59,63 @@ func ExampleBlockchain() {
 	// [
 	//   {
 	//     "Timestamp": "2006-01-02T21:04:05Z",
-	//     "Data": "block #4",
-	//     "Hash": "248:173:00b6863763acd6ec77ca3521589d8e68c118efe855657d702783e8e6aee169a9",
-	//     "PrevHash": "248:65:00d5800e119abe44d89469c2161be7f9645d7237697c6d14b4a72717893582fa"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #4"
+	//     },
+	//     "Hash": "v1:248:77:00ef6fd1be9b106cc6a5c2fffad40484524d0fc00715b73adb65c63ef26ab6ae",
+	//     "PrevHash": "v1:248:72:00228e27a49db926de3137038c340040cb432dbda55f99aab9ac4c8238018b0c",
+	//     "Height": 5
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T20:04:05Z",
-	//     "Data": "block #3",
-	//     "Hash": "248:65:00d5800e119abe44d89469c2161be7f9645d7237697c6d14b4a72717893582fa",
-	//     "PrevHash": "248:136:003c7def3d467a759fad481c03cadbd62e62b2c5dbc10e4bbb6e1944c158a8be"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #3"
+	//     },
+	//     "Hash": "v1:248:72:00228e27a49db926de3137038c340040cb432dbda55f99aab9ac4c8238018b0c",
+	//     "PrevHash": "v1:248:247:00a45549da3d730738406940adbc9b56cf4da65bd6a2e27b83690dd731a6dfef",
+	//     "Height": 4
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T19:04:05Z",
-	//     "Data": "block #2",
-	//     "Hash": "248:136:003c7def3d467a759fad481c03cadbd62e62b2c5dbc10e4bbb6e1944c158a8be",
-	//     "PrevHash": "248:15:002fc891ad012c4a89f7b267a2ec1767415c627ff69b88b90a93be938b026efa"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #2"
+	//     },
+	//     "Hash": "v1:248:247:00a45549da3d730738406940adbc9b56cf4da65bd6a2e27b83690dd731a6dfef",
+	//     "PrevHash": "v1:248:101:002f0f8f8e82a0a164e6338b8c7ad26bf425793665296f4a41cc84e828aa82a9",
+	//     "Height": 3
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T18:04:05Z",
-	//     "Data": "block #1",
-	//     "Hash": "248:15:002fc891ad012c4a89f7b267a2ec1767415c627ff69b88b90a93be938b026efa",
-	//     "PrevHash": "248:198:0058f5dae6ca3451801a276c94862c7cce085e6f9371e50d80ddbb87c1438faf"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #1"
+	//     },
+	//     "Hash": "v1:248:101:002f0f8f8e82a0a164e6338b8c7ad26bf425793665296f4a41cc84e828aa82a9",
+	//     "PrevHash": "v1:248:87:00d8c38907ac171a1e469111f6128682056ceb9660b56e94b155afa25f3d582f",
+	//     "Height": 2
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T17:04:05Z",
-	//     "Data": "block #0",
-	//     "Hash": "248:198:0058f5dae6ca3451801a276c94862c7cce085e6f9371e50d80ddbb87c1438faf",
-	//     "PrevHash": "248:225:00e26abd9974fcdea4b32eca43c9dc5c67fffa8efd53cebffa9b049fd6c2bb36"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #0"
+	//     },
+	//     "Hash": "v1:248:87:00d8c38907ac171a1e469111f6128682056ceb9660b56e94b155afa25f3d582f",
+	//     "PrevHash": "v1:248:57:0027d126d75c938f7b26a0dcaa56cc84b8c70cbfddbe1fd6bd16e6c6752fd8b4",
+	//     "Height": 1
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T16:04:05Z",
-	//     "Data": "genesis block",
-	//     "Hash": "248:225:00e26abd9974fcdea4b32eca43c9dc5c67fffa8efd53cebffa9b049fd6c2bb36",
-	//     "PrevHash": ""
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "genesis block"
+	//     },
+	//     "Hash": "v1:248:57:0027d126d75c938f7b26a0dcaa56cc84b8c70cbfddbe1fd6bd16e6c6752fd8b4",
+	//     "PrevHash": "",
+	//     "Height": 0
 	//   }
 	// ]
 }
@@ -219,21 +243,33 @@ func ExampleBlockchain_Merge() {
 	// [
 	//   {
 	//     "Timestamp": "2006-01-02T17:04:05Z",
-	//     "Data": "block #1",
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #1"
+	//     },
 	//     "Hash": "240:885:0000afa95e15291e5d6e7b5454292841114904f4d4b81c8187e838b7fe7d7b25",
-	//     "PrevHash": "240:25578:0000d382b7d47324d79ba6178449f9ebbd08a20412c2fa548a32f6ad217f6ce9"
+	//     "PrevHash": "240:25578:0000d382b7d47324d79ba6178449f9ebbd08a20412c2fa548a32f6ad217f6ce9",
+	//     "Height": 0
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T16:04:05Z",
-	//     "Data": "block #0",
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #0"
+	//     },
 	//     "Hash": "240:25578:0000d382b7d47324d79ba6178449f9ebbd08a20412c2fa548a32f6ad217f6ce9",
-	//     "PrevHash": "240:73021:00004a15cf538f5e4d3592c68ee4ac6dd3d3b99d7fa5effcd75fe07c58eb213e"
+	//     "PrevHash": "240:73021:00004a15cf538f5e4d3592c68ee4ac6dd3d3b99d7fa5effcd75fe07c58eb213e",
+	//     "Height": 0
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T15:04:05Z",
-	//     "Data": "genesis block",
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "genesis block"
+	//     },
 	//     "Hash": "240:73021:00004a15cf538f5e4d3592c68ee4ac6dd3d3b99d7fa5effcd75fe07c58eb213e",
-	//     "PrevHash": ""
+	//     "PrevHash": "",
+	//     "Height": 0
 	//   }
 	// ]
 }
@@ -286,39 +322,63 @@ func ExampleBlock() {
 	// [
 	//   {
 	//     "Timestamp": "2006-01-02T16:04:05Z",
-	//     "Data": "genesis block",
-	//     "Hash": "248:225:00e26abd9974fcdea4b32eca43c9dc5c67fffa8efd53cebffa9b049fd6c2bb36",
-	//     "PrevHash": ""
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "genesis block"
+	//     },
+	//     "Hash": "v1:248:57:0027d126d75c938f7b26a0dcaa56cc84b8c70cbfddbe1fd6bd16e6c6752fd8b4",
+	//     "PrevHash": "",
+	//     "Height": 0
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T17:04:05Z",
-	//     "Data": "block #0",
-	//     "Hash": "248:198:0058f5dae6ca3451801a276c94862c7cce085e6f9371e50d80ddbb87c1438faf",
-	//     "PrevHash": "248:225:00e26abd9974fcdea4b32eca43c9dc5c67fffa8efd53cebffa9b049fd6c2bb36"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #0"
+	//     },
+	//     "Hash": "v1:248:87:00d8c38907ac171a1e469111f6128682056ceb9660b56e94b155afa25f3d582f",
+	//     "PrevHash": "v1:248:57:0027d126d75c938f7b26a0dcaa56cc84b8c70cbfddbe1fd6bd16e6c6752fd8b4",
+	//     "Height": 1
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T18:04:05Z",
-	//     "Data": "block #1",
-	//     "Hash": "248:15:002fc891ad012c4a89f7b267a2ec1767415c627ff69b88b90a93be938b026efa",
-	//     "PrevHash": "248:198:0058f5dae6ca3451801a276c94862c7cce085e6f9371e50d80ddbb87c1438faf"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #1"
+	//     },
+	//     "Hash": "v1:248:101:002f0f8f8e82a0a164e6338b8c7ad26bf425793665296f4a41cc84e828aa82a9",
+	//     "PrevHash": "v1:248:87:00d8c38907ac171a1e469111f6128682056ceb9660b56e94b155afa25f3d582f",
+	//     "Height": 2
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T19:04:05Z",
-	//     "Data": "block #2",
-	//     "Hash": "248:136:003c7def3d467a759fad481c03cadbd62e62b2c5dbc10e4bbb6e1944c158a8be",
-	//     "PrevHash": "248:15:002fc891ad012c4a89f7b267a2ec1767415c627ff69b88b90a93be938b026efa"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #2"
+	//     },
+	//     "Hash": "v1:248:247:00a45549da3d730738406940adbc9b56cf4da65bd6a2e27b83690dd731a6dfef",
+	//     "PrevHash": "v1:248:101:002f0f8f8e82a0a164e6338b8c7ad26bf425793665296f4a41cc84e828aa82a9",
+	//     "Height": 3
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T20:04:05Z",
-	//     "Data": "block #3",
-	//     "Hash": "248:65:00d5800e119abe44d89469c2161be7f9645d7237697c6d14b4a72717893582fa",
-	//     "PrevHash": "248:136:003c7def3d467a759fad481c03cadbd62e62b2c5dbc10e4bbb6e1944c158a8be"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #3"
+	//     },
+	//     "Hash": "v1:248:72:00228e27a49db926de3137038c340040cb432dbda55f99aab9ac4c8238018b0c",
+	//     "PrevHash": "v1:248:247:00a45549da3d730738406940adbc9b56cf4da65bd6a2e27b83690dd731a6dfef",
+	//     "Height": 4
 	//   },
 	//   {
 	//     "Timestamp": "2006-01-02T21:04:05Z",
-	//     "Data": "block #4",
-	//     "Hash": "248:173:00b6863763acd6ec77ca3521589d8e68c118efe855657d702783e8e6aee169a9",
-	//     "PrevHash": "248:65:00d5800e119abe44d89469c2161be7f9645d7237697c6d14b4a72717893582fa"
+	//     "Data": {
+	//       "Type": "text",
+	//       "Text": "block #4"
+	//     },
+	//     "Hash": "v1:248:77:00ef6fd1be9b106cc6a5c2fffad40484524d0fc00715b73adb65c63ef26ab6ae",
+	//     "PrevHash": "v1:248:72:00228e27a49db926de3137038c340040cb432dbda55f99aab9ac4c8238018b0c",
+	//     "Height": 5
 	//   }
 	// ]
 }
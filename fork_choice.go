@@ -0,0 +1,148 @@
+package blockchain
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	"github.com/samber/mo"
+)
+
+//go:generate mockery --name=ForkChoice --inpackage --case=underscore --testonly
+
+// ForkChoice ...
+//
+// It compares two forks following the same common ancestor
+// by their differences. The result is positive if the left fork is preferred,
+// negative if the right one is preferred, and zero if neither is.
+type ForkChoice interface {
+	CompareForks(
+		leftDifferences BlockGroup,
+		rightDifferences BlockGroup,
+		proofer Proofer,
+	) (int, error)
+}
+
+// HeaviestWorkForkChoice ...
+//
+// It prefers the fork with the maximal total work (see [BlockGroup.Work]).
+type HeaviestWorkForkChoice struct{}
+
+// CompareForks ...
+func (forkChoice HeaviestWorkForkChoice) CompareForks(
+	leftDifferences BlockGroup,
+	rightDifferences BlockGroup,
+	proofer Proofer,
+) (int, error) {
+	leftWork, err := leftDifferences.Work(proofer)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"unable to calculate the work of the left differences: %w",
+			err,
+		)
+	}
+
+	rightWork, err := rightDifferences.Work(proofer)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"unable to calculate the work of the right differences: %w",
+			err,
+		)
+	}
+
+	return leftWork.Cmp(rightWork), nil
+}
+
+// LongestChainForkChoice ...
+//
+// It prefers the fork with the maximal block count regardless of the work.
+type LongestChainForkChoice struct{}
+
+// CompareForks ...
+func (forkChoice LongestChainForkChoice) CompareForks(
+	leftDifferences BlockGroup,
+	rightDifferences BlockGroup,
+	proofer Proofer,
+) (int, error) {
+	return cmp.Compare(len(leftDifferences), len(rightDifferences)), nil
+}
+
+// EarliestTipForkChoice ...
+//
+// It uses the base fork choice ([HeaviestWorkForkChoice] by default)
+// and breaks its ties by preferring the fork whose last block has
+// the earliest timestamp.
+type EarliestTipForkChoice struct {
+	Base mo.Option[ForkChoice]
+}
+
+// CompareForks ...
+func (forkChoice EarliestTipForkChoice) CompareForks(
+	leftDifferences BlockGroup,
+	rightDifferences BlockGroup,
+	proofer Proofer,
+) (int, error) {
+	return breakForkTie(
+		forkChoice.Base,
+		leftDifferences,
+		rightDifferences,
+		proofer,
+		func(leftTip Block, rightTip Block) int {
+			// the earlier timestamp is preferred, so they are compared in reverse
+			return rightTip.Timestamp.Compare(leftTip.Timestamp)
+		},
+	)
+}
+
+// LowestHashForkChoice ...
+//
+// It uses the base fork choice ([HeaviestWorkForkChoice] by default)
+// and breaks its ties by preferring the fork whose last block has
+// the lexicographically lowest hash.
+type LowestHashForkChoice struct {
+	Base mo.Option[ForkChoice]
+}
+
+// CompareForks ...
+func (forkChoice LowestHashForkChoice) CompareForks(
+	leftDifferences BlockGroup,
+	rightDifferences BlockGroup,
+	proofer Proofer,
+) (int, error) {
+	return breakForkTie(
+		forkChoice.Base,
+		leftDifferences,
+		rightDifferences,
+		proofer,
+		func(leftTip Block, rightTip Block) int {
+			// the lower hash is preferred, so they are compared in reverse
+			return strings.Compare(rightTip.Hash, leftTip.Hash)
+		},
+	)
+}
+
+func breakForkTie(
+	baseForkChoice mo.Option[ForkChoice],
+	leftDifferences BlockGroup,
+	rightDifferences BlockGroup,
+	proofer Proofer,
+	compareTips func(leftTip Block, rightTip Block) int,
+) (int, error) {
+	result, err := baseForkChoice.
+		OrElse(HeaviestWorkForkChoice{}).
+		CompareForks(leftDifferences, rightDifferences, proofer)
+	if err != nil {
+		return 0, fmt.Errorf("unable to compare the forks: %w", err)
+	}
+	if result != 0 {
+		return result, nil
+	}
+
+	// there are no tips to compare
+	if len(leftDifferences) == 0 || len(rightDifferences) == 0 {
+		return 0, nil
+	}
+
+	// the block groups are ordered from the last block to the first one
+	return compareTips(leftDifferences[0], rightDifferences[0]), nil
+}
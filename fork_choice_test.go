@@ -0,0 +1,651 @@
+package blockchain
+
+import (
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHeaviestWorkForkChoice_CompareForks(test *testing.T) {
+	type args struct {
+		leftDifferences  BlockGroup
+		rightDifferences BlockGroup
+		proofer          Proofer
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    int
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/left fork is preferred",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(23, nil)
+
+					return proofer
+				}(),
+			},
+			want:    1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/right fork is preferred",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #3.2",
+						PrevHash:  "hash #2.2",
+					},
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+					proofer.On("Difficulty", "hash #3.2").Return(23, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(23, nil)
+
+					return proofer
+				}(),
+			},
+			want:    -1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/neither fork is preferred",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(42, nil)
+
+					return proofer
+				}(),
+			},
+			want:    0,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error with the left differences",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(0, iotest.ErrTimeout)
+
+					return proofer
+				}(),
+			},
+			want:    0,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error with the right differences",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(0, iotest.ErrTimeout)
+
+					return proofer
+				}(),
+			},
+			want:    0,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			forkChoice := HeaviestWorkForkChoice{}
+			got, err := forkChoice.CompareForks(
+				data.args.leftDifferences,
+				data.args.rightDifferences,
+				data.args.proofer,
+			)
+
+			mock.AssertExpectationsForObjects(test, data.args.proofer)
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestLongestChainForkChoice_CompareForks(test *testing.T) {
+	type args struct {
+		leftDifferences  BlockGroup
+		rightDifferences BlockGroup
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want int
+	}{
+		{
+			name: "left fork is preferred",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: nil,
+			},
+			want: 1,
+		},
+		{
+			name: "right fork is preferred",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #3.2",
+						PrevHash:  "hash #2.2",
+					},
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+			},
+			want: -1,
+		},
+		{
+			name: "neither fork is preferred",
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+			},
+			want: 0,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := new(MockProofer)
+
+			forkChoice := LongestChainForkChoice{}
+			got, err := forkChoice.CompareForks(
+				data.args.leftDifferences,
+				data.args.rightDifferences,
+				proofer,
+			)
+
+			mock.AssertExpectationsForObjects(test, proofer)
+			assert.Equal(test, data.want, got)
+			assert.NoError(test, err)
+		})
+	}
+}
+
+func TestEarliestTipForkChoice_CompareForks(test *testing.T) {
+	type fields struct {
+		base mo.Option[ForkChoice]
+	}
+	type args struct {
+		leftDifferences  BlockGroup
+		rightDifferences BlockGroup
+		proofer          Proofer
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/without a tie",
+			fields: fields{
+				base: mo.None[ForkChoice](),
+			},
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(23, nil)
+
+					return proofer
+				}(),
+			},
+			want:    1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a tie/left tip is earlier",
+			fields: fields{
+				base: mo.None[ForkChoice](),
+			},
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(42, nil)
+
+					return proofer
+				}(),
+			},
+			want:    1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a tie/right tip is earlier",
+			fields: fields{
+				base: mo.Some[ForkChoice](LongestChainForkChoice{}),
+			},
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: new(MockProofer),
+			},
+			want:    -1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a tie/same tip timestamps",
+			fields: fields{
+				base: mo.Some[ForkChoice](LongestChainForkChoice{}),
+			},
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: new(MockProofer),
+			},
+			want:    0,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a tie/without tips",
+			fields: fields{
+				base: mo.Some[ForkChoice](LongestChainForkChoice{}),
+			},
+			args: args{
+				leftDifferences:  nil,
+				rightDifferences: nil,
+				proofer:          new(MockProofer),
+			},
+			want:    0,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			fields: fields{
+				base: func() mo.Option[ForkChoice] {
+					forkChoice := new(MockForkChoice)
+					forkChoice.
+						On("CompareForks", BlockGroup(nil), BlockGroup(nil), new(MockProofer)).
+						Return(0, iotest.ErrTimeout)
+
+					return mo.Some[ForkChoice](forkChoice)
+				}(),
+			},
+			args: args{
+				leftDifferences:  nil,
+				rightDifferences: nil,
+				proofer:          new(MockProofer),
+			},
+			want:    0,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			forkChoice := EarliestTipForkChoice{
+				Base: data.fields.base,
+			}
+			got, err := forkChoice.CompareForks(
+				data.args.leftDifferences,
+				data.args.rightDifferences,
+				data.args.proofer,
+			)
+
+			if base, isPresent := data.fields.base.Get(); isPresent {
+				if mockBase, isMock := base.(*MockForkChoice); isMock {
+					mock.AssertExpectationsForObjects(test, mockBase)
+				}
+			}
+			mock.AssertExpectationsForObjects(test, data.args.proofer)
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestLowestHashForkChoice_CompareForks(test *testing.T) {
+	type fields struct {
+		base mo.Option[ForkChoice]
+	}
+	type args struct {
+		leftDifferences  BlockGroup
+		rightDifferences BlockGroup
+		proofer          Proofer
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/without a tie",
+			fields: fields{
+				base: mo.None[ForkChoice](),
+			},
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(23, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(42, nil)
+
+					return proofer
+				}(),
+			},
+			want:    -1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a tie/left hash is lower",
+			fields: fields{
+				base: mo.None[ForkChoice](),
+			},
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.On("Difficulty", "hash #2.1").Return(42, nil)
+					proofer.On("Difficulty", "hash #2.2").Return(42, nil)
+
+					return proofer
+				}(),
+			},
+			want:    1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a tie/right hash is lower",
+			fields: fields{
+				base: mo.Some[ForkChoice](EarliestTipForkChoice{
+					Base: mo.Some[ForkChoice](LongestChainForkChoice{}),
+				}),
+			},
+			args: args{
+				leftDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.2",
+						PrevHash:  "hash #1",
+					},
+				},
+				rightDifferences: BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      new(MockData),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+				proofer: new(MockProofer),
+			},
+			want:    -1,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a tie/without tips",
+			fields: fields{
+				base: mo.Some[ForkChoice](LongestChainForkChoice{}),
+			},
+			args: args{
+				leftDifferences:  nil,
+				rightDifferences: nil,
+				proofer:          new(MockProofer),
+			},
+			want:    0,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			fields: fields{
+				base: func() mo.Option[ForkChoice] {
+					forkChoice := new(MockForkChoice)
+					forkChoice.
+						On("CompareForks", BlockGroup(nil), BlockGroup(nil), new(MockProofer)).
+						Return(0, iotest.ErrTimeout)
+
+					return mo.Some[ForkChoice](forkChoice)
+				}(),
+			},
+			args: args{
+				leftDifferences:  nil,
+				rightDifferences: nil,
+				proofer:          new(MockProofer),
+			},
+			want:    0,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			forkChoice := LowestHashForkChoice{
+				Base: data.fields.base,
+			}
+			got, err := forkChoice.CompareForks(
+				data.args.leftDifferences,
+				data.args.rightDifferences,
+				data.args.proofer,
+			)
+
+			if base, isPresent := data.fields.base.Get(); isPresent {
+				if mockBase, isMock := base.(*MockForkChoice); isMock {
+					mock.AssertExpectationsForObjects(test, mockBase)
+				}
+			}
+			mock.AssertExpectationsForObjects(test, data.args.proofer)
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
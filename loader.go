@@ -3,14 +3,26 @@ package blockchain
 import (
 	"errors"
 	"fmt"
+	"math"
 )
 
-// ErrNoMatch ...
-var ErrNoMatch = errors.New("no match")
+// ...
+var (
+	ErrNoMatch       = errors.New("no match")
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// errInconsistentHeights is returned by the search by a height
+// if the block heights don't correspond to the block order
+// (e.g., for the blocks created before the heights were introduced).
+var errInconsistentHeights = errors.New("inconsistent block heights")
 
 //go:generate mockery --name=Loader --inpackage --case=underscore --testonly
 
 // Loader ...
+//
+// The LoadBlocks method returns the [ErrInvalidCursor] error
+// if the cursor isn't supported by the loader.
 type Loader interface {
 	LoadBlocks(cursor interface{}, count int) (
 		blocks BlockGroup,
@@ -19,26 +31,275 @@ type Loader interface {
 	)
 }
 
+//go:generate mockery --name=IndexedLoader --inpackage --case=underscore --testonly
+
+// IndexedLoader ...
+//
+// The LoadBlockByHeight method returns the [ErrNotFound] error
+// if there is no block with the specified height, and the
+// [errors.ErrUnsupported] error if the lookup by a height isn't supported.
+type IndexedLoader interface {
+	Loader
+
+	LoadBlockByHeight(height int) (Block, error)
+}
+
 // FindDifferences ...
+//
+// It finds the common ancestor of the block groups provided by the loaders
+// and returns the blocks that follow it.
+//
+// If both loaders implement the [IndexedLoader] interface, the common ancestor
+// is searched by a height: first with an exponentially increasing step back
+// from the last blocks, then with a binary search. Otherwise (or if the search
+// by a height isn't supported or the block heights are inconsistent),
+// the loaders are paged backward with an exponentially increasing chunk size
+// starting from the specified one.
 func FindDifferences(leftLoader Loader, rightLoader Loader, chunkSize int) (
 	leftDifferences BlockGroup,
 	rightDifferences BlockGroup,
 	err error,
 ) {
-	leftBlocks, _, err := leftLoader.LoadBlocks(nil, chunkSize)
+	foundFork, err := findFork(leftLoader, rightLoader, chunkSize)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return foundFork.leftDifferences, foundFork.rightDifferences, nil
+}
+
+type fork struct {
+	leftDifferences  BlockGroup
+	rightDifferences BlockGroup
+	commonBlock      Block
+}
+
+func findFork(leftLoader Loader, rightLoader Loader, chunkSize int) (
+	fork,
+	error,
+) {
+	leftIndexedLoader, isLeftLoaderIndexed := leftLoader.(IndexedLoader)
+	rightIndexedLoader, isRightLoaderIndexed := rightLoader.(IndexedLoader)
+	if isLeftLoaderIndexed && isRightLoaderIndexed {
+		foundFork, err := findForkByHeight(
+			leftIndexedLoader,
+			rightIndexedLoader,
+			chunkSize,
+		)
+		if !errors.Is(err, errors.ErrUnsupported) &&
+			!errors.Is(err, errInconsistentHeights) {
+			return foundFork, err
+		}
+	}
+
+	return findForkByPaging(leftLoader, rightLoader, chunkSize)
+}
+
+func findForkByPaging(
+	leftLoader Loader,
+	rightLoader Loader,
+	chunkSize int,
+) (fork, error) {
+	leftPager := &blockPager{loader: leftLoader}
+	rightPager := &blockPager{loader: rightLoader}
+	for {
+		if err := leftPager.loadNextChunk(chunkSize); err != nil {
+			return fork{}, fmt.Errorf("unable to load the left blocks: %w", err)
+		}
+
+		if err := rightPager.loadNextChunk(chunkSize); err != nil {
+			return fork{}, fmt.Errorf("unable to load the right blocks: %w", err)
+		}
+
+		leftIndex, rightIndex, hasMatch :=
+			leftPager.blocks.FindDifferences(rightPager.blocks)
+		if hasMatch {
+			return fork{
+				leftDifferences:  leftPager.blocks[:leftIndex],
+				rightDifferences: rightPager.blocks[:rightIndex],
+				commonBlock:      leftPager.blocks[leftIndex],
+			}, nil
+		}
+
+		if leftPager.isExhausted && rightPager.isExhausted {
+			return fork{}, ErrNoMatch
+		}
+
+		if chunkSize <= math.MaxInt/2 {
+			chunkSize *= 2
+		}
+	}
+}
+
+func findForkByHeight(
+	leftLoader IndexedLoader,
+	rightLoader IndexedLoader,
+	chunkSize int,
+) (fork, error) {
+	leftLastBlocks, _, err := leftLoader.LoadBlocks(nil, 1)
+	if err != nil {
+		return fork{}, fmt.Errorf("unable to load the left last block: %w", err)
+	}
+
+	rightLastBlocks, _, err := rightLoader.LoadBlocks(nil, 1)
+	if err != nil {
+		return fork{}, fmt.Errorf("unable to load the right last block: %w", err)
+	}
+
+	if len(leftLastBlocks) == 0 || len(rightLastBlocks) == 0 {
+		return fork{}, ErrNoMatch
+	}
+
+	isCommonHeight := func(height int) (bool, error) {
+		leftBlock, err := loadBlockByHeight(leftLoader, height)
+		if err != nil {
+			return false, fmt.Errorf("unable to load the left block: %w", err)
+		}
+
+		rightBlock, err := loadBlockByHeight(rightLoader, height)
+		if err != nil {
+			return false, fmt.Errorf("unable to load the right block: %w", err)
+		}
+
+		isCommon := leftBlock.IsEqual(rightBlock) == nil
+		if !isCommon && height == 0 &&
+			(leftBlock.PrevHash != "" || rightBlock.PrevHash != "") {
+			// the blocks with the zero height have to be genesis ones
+			return false, errInconsistentHeights
+		}
+
+		return isCommon, nil
+	}
+
+	// invariant: the block with the height [differentHeight] differs,
+	// and the block with the height [commonHeight] is common
+	differentHeight :=
+		min(leftLastBlocks[0].Height, rightLastBlocks[0].Height) + 1
+	commonHeight := differentHeight - 1
+	for step := 1; ; step *= 2 {
+		isCommon, err := isCommonHeight(commonHeight)
+		if err != nil {
+			return fork{}, err
+		}
+		if isCommon {
+			break
+		}
+
+		if commonHeight == 0 {
+			return fork{}, ErrNoMatch
+		}
+
+		differentHeight = commonHeight
+		commonHeight = max(commonHeight-step, 0)
+	}
+
+	for differentHeight-commonHeight > 1 {
+		middleHeight := commonHeight + (differentHeight-commonHeight)/2
+
+		isCommon, err := isCommonHeight(middleHeight)
+		if err != nil {
+			return fork{}, err
+		}
+
+		if isCommon {
+			commonHeight = middleHeight
+		} else {
+			differentHeight = middleHeight
+		}
+	}
+
+	leftDifferences, leftCommonBlock, err :=
+		loadBlocksAbove(leftLoader, commonHeight, chunkSize)
+	if err != nil {
+		return fork{}, fmt.Errorf("unable to load the left differences: %w", err)
+	}
+
+	rightDifferences, rightCommonBlock, err :=
+		loadBlocksAbove(rightLoader, commonHeight, chunkSize)
+	if err != nil {
+		return fork{}, fmt.Errorf("unable to load the right differences: %w", err)
+	}
+
+	if err := leftCommonBlock.IsEqual(rightCommonBlock); err != nil {
+		return fork{}, errors.Join(err, errInconsistentHeights)
+	}
+
+	return fork{
+		leftDifferences:  leftDifferences,
+		rightDifferences: rightDifferences,
+		commonBlock:      leftCommonBlock,
+	}, nil
+}
+
+func loadBlockByHeight(loader IndexedLoader, height int) (Block, error) {
+	block, err := loader.LoadBlockByHeight(height)
 	if err != nil {
-		return nil, nil, fmt.Errorf("unable to load the left blocks: %w", err)
+		if errors.Is(err, ErrNotFound) {
+			// the block heights are expected to be continuous
+			err = errors.Join(err, errInconsistentHeights)
+		}
+
+		return Block{}, fmt.Errorf(
+			"unable to load the block with height %d: %w",
+			height,
+			err,
+		)
+	}
+
+	return block, nil
+}
+
+func loadBlocksAbove(loader Loader, height int, chunkSize int) (
+	blocks BlockGroup,
+	blockAtHeight Block,
+	err error,
+) {
+	pager := &blockPager{loader: loader}
+	for !pager.isExhausted {
+		chunkStartIndex := len(pager.blocks)
+		if err := pager.loadNextChunk(chunkSize); err != nil {
+			return nil, Block{}, err
+		}
+
+		for index, block := range pager.blocks[chunkStartIndex:] {
+			if block.Height <= height {
+				index += chunkStartIndex
+				return pager.blocks[:index], block, nil
+			}
+		}
 	}
 
-	rightBlocks, _, err := rightLoader.LoadBlocks(nil, chunkSize)
+	return nil, Block{}, fmt.Errorf(
+		"unable to find the block with height %d: %w",
+		height,
+		errInconsistentHeights,
+	)
+}
+
+type blockPager struct {
+	loader      Loader
+	cursor      interface{}
+	blocks      BlockGroup
+	isExhausted bool
+}
+
+func (pager *blockPager) loadNextChunk(count int) error {
+	if pager.isExhausted {
+		return nil
+	}
+
+	blocks, nextCursor, err := pager.loader.LoadBlocks(pager.cursor, count)
 	if err != nil {
-		return nil, nil, fmt.Errorf("unable to load the right blocks: %w", err)
+		return err
 	}
 
-	leftIndex, rightIndex, hasMatch := leftBlocks.FindDifferences(rightBlocks)
-	if !hasMatch {
-		return nil, nil, ErrNoMatch
+	if len(blocks) == 0 {
+		pager.isExhausted = true
+		return nil
 	}
 
-	return leftBlocks[:leftIndex], rightBlocks[:rightIndex], nil
+	pager.blocks = append(pager.blocks, blocks...)
+	pager.cursor = nextCursor
+
+	return nil
 }
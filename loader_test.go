@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"fmt"
 	"testing"
 	"testing/iotest"
 	"time"
@@ -274,6 +275,7 @@ func TestFindDifferences(test *testing.T) {
 
 					leftLoader := new(MockLoader)
 					leftLoader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+					leftLoader.On("LoadBlocks", 26, 46).Return(nil, 26, nil)
 
 					return leftLoader
 				}(),
@@ -301,6 +303,7 @@ func TestFindDifferences(test *testing.T) {
 
 					rightLoader := new(MockLoader)
 					rightLoader.On("LoadBlocks", nil, 23).Return(blocks, 26, nil)
+					rightLoader.On("LoadBlocks", 26, 46).Return(nil, 26, nil)
 
 					return rightLoader
 				}(),
@@ -335,3 +338,178 @@ func TestFindDifferences(test *testing.T) {
 		})
 	}
 }
+
+func TestFindDifferences_withDeepFork(test *testing.T) {
+	type args struct {
+		makeLoader func(blocks BlockGroup) Loader
+		chunkSize  int
+	}
+
+	commonBlocks := makeTestChain(nil, "common", 20)
+	leftBlocks := makeTestChain(commonBlocks, "left", 3)
+	rightBlocks := makeTestChain(commonBlocks, "right", 5)
+
+	for _, data := range []struct {
+		name string
+		args args
+	}{
+		{
+			name: "with paging",
+			args: args{
+				makeLoader: func(blocks BlockGroup) Loader {
+					return testLoader(blocks)
+				},
+				chunkSize: 2,
+			},
+		},
+		{
+			name: "with the search by a height",
+			args: args{
+				makeLoader: func(blocks BlockGroup) Loader {
+					return testIndexedLoader{testLoader(blocks)}
+				},
+				chunkSize: 2,
+			},
+		},
+		{
+			name: "with the search by a height and the large chunk size",
+			args: args{
+				makeLoader: func(blocks BlockGroup) Loader {
+					return testIndexedLoader{testLoader(blocks)}
+				},
+				chunkSize: 100,
+			},
+		},
+		{
+			name: "with inconsistent heights",
+			args: args{
+				makeLoader: func(blocks BlockGroup) Loader {
+					blocksWithoutHeights := make(BlockGroup, len(blocks))
+					for index, block := range blocks {
+						block.Height = 0
+						blocksWithoutHeights[index] = block
+					}
+
+					return testIndexedLoader{testLoader(blocksWithoutHeights)}
+				},
+				chunkSize: 2,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			leftLoader := data.args.makeLoader(leftBlocks)
+			rightLoader := data.args.makeLoader(rightBlocks)
+			gotLeftDifferences, gotRightDifferences, gotErr :=
+				FindDifferences(leftLoader, rightLoader, data.args.chunkSize)
+
+			wantLeftDifferences, _, _ := leftLoader.LoadBlocks(nil, 3)
+			wantRightDifferences, _, _ := rightLoader.LoadBlocks(nil, 5)
+			assert.Equal(test, wantLeftDifferences, gotLeftDifferences)
+			assert.Equal(test, wantRightDifferences, gotRightDifferences)
+			assert.NoError(test, gotErr)
+		})
+	}
+}
+
+func TestFindDifferences_withoutCommonBlocks(test *testing.T) {
+	leftBlocks := makeTestChain(nil, "left", 10)
+	rightBlocks := makeTestChain(nil, "right", 7)
+
+	for _, data := range []struct {
+		name        string
+		leftLoader  Loader
+		rightLoader Loader
+	}{
+		{
+			name:        "with paging",
+			leftLoader:  testLoader(leftBlocks),
+			rightLoader: testLoader(rightBlocks),
+		},
+		{
+			name:        "with the search by a height",
+			leftLoader:  testIndexedLoader{testLoader(leftBlocks)},
+			rightLoader: testIndexedLoader{testLoader(rightBlocks)},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotLeftDifferences, gotRightDifferences, gotErr :=
+				FindDifferences(data.leftLoader, data.rightLoader, 2)
+
+			assert.Nil(test, gotLeftDifferences)
+			assert.Nil(test, gotRightDifferences)
+			assert.Equal(test, ErrNoMatch, gotErr)
+		})
+	}
+}
+
+func TestFindDifferences_withLoadingByHeightError(test *testing.T) {
+	blocks := makeTestChain(nil, "common", 3)
+
+	leftLoader := new(MockIndexedLoader)
+	leftLoader.On("LoadBlocks", nil, 1).Return(blocks[:1], 1, nil)
+	leftLoader.On("LoadBlockByHeight", 2).Return(Block{}, iotest.ErrTimeout)
+
+	rightLoader := new(MockIndexedLoader)
+	rightLoader.On("LoadBlocks", nil, 1).Return(blocks[:1], 1, nil)
+
+	gotLeftDifferences, gotRightDifferences, gotErr :=
+		FindDifferences(leftLoader, rightLoader, 2)
+
+	mock.AssertExpectationsForObjects(test, leftLoader, rightLoader)
+	assert.Nil(test, gotLeftDifferences)
+	assert.Nil(test, gotRightDifferences)
+	assert.ErrorIs(test, gotErr, iotest.ErrTimeout)
+}
+
+type testLoader BlockGroup
+
+func (loader testLoader) LoadBlocks(cursor interface{}, count int) (
+	blocks BlockGroup,
+	nextCursor interface{},
+	err error,
+) {
+	startIndex := 0
+	if cursor != nil {
+		startIndex = cursor.(int)
+	}
+	if startIndex >= len(loader) {
+		return nil, startIndex, nil
+	}
+
+	endIndex := min(startIndex+count, len(loader))
+	return BlockGroup(loader[startIndex:endIndex]), endIndex, nil
+}
+
+type testIndexedLoader struct {
+	testLoader
+}
+
+func (loader testIndexedLoader) LoadBlockByHeight(height int) (Block, error) {
+	for _, block := range loader.testLoader {
+		if block.Height == height {
+			return block, nil
+		}
+	}
+
+	return Block{}, ErrNotFound
+}
+
+// makeTestChain returns the blocks in the reverse order, like the loaders do.
+func makeTestChain(baseBlocks BlockGroup, name string, count int) BlockGroup {
+	blocks := append(BlockGroup(nil), baseBlocks...)
+	for index := 0; index < count; index++ {
+		block := Block{
+			Timestamp: clock().Add(time.Duration(len(blocks)) * time.Hour),
+			Data:      NewData(fmt.Sprintf("%s block #%d", name, index)),
+			Hash:      fmt.Sprintf("%s hash #%d", name, index),
+		}
+		if len(blocks) != 0 {
+			block.PrevHash = blocks[0].Hash
+			block.Height = blocks[0].Height + 1
+		}
+
+		blocks = append(BlockGroup{block}, blocks...)
+	}
+
+	return blocks
+}
@@ -0,0 +1,56 @@
+package loaders
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// CursorCodec ...
+//
+// It converts the cursors of a loader to opaque strings and back.
+type CursorCodec interface {
+	EncodeCursor(cursor interface{}) (string, error)
+	DecodeCursor(rawCursor string) (interface{}, error)
+}
+
+// JSONCursorCodec ...
+//
+// It represents cursors of the specified type as base64-encoded JSON.
+// For example, use `JSONCursorCodec[int]` for the [MemoryLoader] loader
+// and the memory storage from the storages package.
+type JSONCursorCodec[T any] struct{}
+
+// EncodeCursor ...
+func (codec JSONCursorCodec[T]) EncodeCursor(
+	cursor interface{},
+) (string, error) {
+	typedCursor, ok := cursor.(T)
+	if !ok {
+		return "", fmt.Errorf("unsupported cursor type %T", cursor)
+	}
+
+	cursorBytes, err := json.Marshal(typedCursor)
+	if err != nil {
+		return "", fmt.Errorf("unable to marshal the cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(cursorBytes), nil
+}
+
+// DecodeCursor ...
+func (codec JSONCursorCodec[T]) DecodeCursor(
+	rawCursor string,
+) (interface{}, error) {
+	cursorBytes, err := base64.RawURLEncoding.DecodeString(rawCursor)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode the cursor: %w", err)
+	}
+
+	var cursor T
+	if err := json.Unmarshal(cursorBytes, &cursor); err != nil {
+		return nil, fmt.Errorf("unable to unmarshal the cursor: %w", err)
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,108 @@
+package loaders
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONCursorCodec_EncodeCursor(test *testing.T) {
+	type args struct {
+		cursor interface{}
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				cursor: 23,
+			},
+			want:    "MjM",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				cursor: "23",
+			},
+			want:    "",
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, gotErr := JSONCursorCodec[int]{}.EncodeCursor(data.args.cursor)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestJSONCursorCodec_DecodeCursor(test *testing.T) {
+	type args struct {
+		rawCursor string
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				rawCursor: "MjM",
+			},
+			want:    23,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/unable to decode the cursor",
+			args: args{
+				rawCursor: "invalid!",
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/unable to unmarshal the cursor",
+			args: args{
+				rawCursor: "ImludmFsaWQi", // "invalid" in JSON
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, gotErr := JSONCursorCodec[int]{}.DecodeCursor(data.args.rawCursor)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestJSONCursorCodec_withStructure(test *testing.T) {
+	type structuredCursor struct {
+		TimestampNs int64
+		Hash        string
+	}
+
+	codec := JSONCursorCodec[structuredCursor]{}
+	cursor := structuredCursor{TimestampNs: 23, Hash: "hash"}
+
+	rawCursor, err := codec.EncodeCursor(cursor)
+	require.NoError(test, err)
+
+	got, gotErr := codec.DecodeCursor(rawCursor)
+
+	assert.Equal(test, cursor, got)
+	assert.NoError(test, gotErr)
+}
@@ -0,0 +1,101 @@
+package loaders_test
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http/httptest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+	"github.com/thewizardplusplus/go-blockchain/loading/loaders"
+	"github.com/thewizardplusplus/go-blockchain/proofers"
+	"github.com/thewizardplusplus/go-blockchain/storing"
+	"github.com/thewizardplusplus/go-blockchain/storing/storages"
+)
+
+func ExampleHTTPLoader() {
+	timestamp := time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+	blockDependencies := blockchain.BlockDependencies{
+		// use the custom clock function to get the same blocks
+		Clock: func() time.Time {
+			timestamp = timestamp.Add(time.Hour)
+			return timestamp
+		},
+		Proofer: proofers.ProofOfWork{
+			TargetBit: 248,
+		},
+	}
+
+	remoteBlockchain, err := blockchain.NewBlockchainEx(
+		context.Background(),
+		blockchain.NewBlockchainExParams{
+			Dependencies: blockchain.Dependencies{
+				BlockDependencies: blockDependencies,
+				Storage:           storing.NewGroupStorage(&storages.MemoryStorage{}),
+			},
+			GenesisBlockData: mo.Some(blockchain.NewData("genesis block")),
+		},
+	)
+	if err != nil {
+		log.Fatalf("unable to create the remote blockchain: %v", err)
+	}
+
+	// the local blockchain shares only the genesis block with the remote one
+	genesisBlocks, _, err := remoteBlockchain.LoadBlocks(nil, 1)
+	if err != nil {
+		log.Fatalf("unable to load the genesis block: %v", err)
+	}
+
+	localBlockchain, err := blockchain.NewBlockchainEx(
+		context.Background(),
+		blockchain.NewBlockchainExParams{
+			Dependencies: blockchain.Dependencies{
+				BlockDependencies: blockDependencies,
+				Storage: storing.NewGroupStorage(
+					storages.NewMemoryStorage(genesisBlocks),
+				),
+			},
+			GenesisBlockData: mo.None[blockchain.Data](),
+		},
+	)
+	if err != nil {
+		log.Fatalf("unable to create the local blockchain: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := remoteBlockchain.AddBlockEx(
+			context.Background(),
+			blockchain.NewData(fmt.Sprintf("block #%d", i)),
+		); err != nil {
+			log.Fatalf("unable to add a new block: %v", err)
+		}
+	}
+
+	server := httptest.NewServer(loaders.NewHTTPHandler(loaders.HTTPHandlerParams{
+		Loader:        remoteBlockchain,
+		CursorCodec:   loaders.JSONCursorCodec[int]{},
+		MaxBlockCount: mo.Some(100),
+	}))
+	defer server.Close()
+
+	remoteLoader := loaders.NewHTTPLoader(loaders.HTTPLoaderParams{
+		URL:    server.URL,
+		Client: mo.Some(server.Client()),
+	})
+	if err := localBlockchain.Merge(remoteLoader, 10); err != nil {
+		log.Fatalf("unable to merge the blockchains: %v", err)
+	}
+
+	mergedBlocks, _, _ := localBlockchain.LoadBlocks(nil, 10)
+	for _, block := range mergedBlocks {
+		fmt.Printf("%d: %s\n", block.Height, block.Data)
+	}
+
+	// Output:
+	// 3: block #2
+	// 2: block #1
+	// 1: block #0
+	// 0: genesis block
+}
@@ -0,0 +1,195 @@
+package loaders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+const (
+	cursorQueryParameter = "cursor"
+	countQueryParameter  = "count"
+	heightQueryParameter = "height"
+	defaultMaxBlockCount = 1000
+)
+
+type httpResponse struct {
+	Blocks     blockchain.BlockGroup
+	NextCursor *string
+}
+
+// HTTPHandlerParams ...
+type HTTPHandlerParams struct {
+	Loader        blockchain.Loader
+	CursorCodec   CursorCodec
+	MaxBlockCount mo.Option[int]
+}
+
+// HTTPHandler ...
+//
+// It exposes the loader via HTTP for the [HTTPLoader] loader. The request
+// is a GET one with the "cursor" (optional) and "count" query parameters.
+// The response is a JSON object with the loaded blocks and the next cursor.
+// The cursors are converted to opaque strings via the cursor codec.
+// The block count is limited by the MaxBlockCount parameter,
+// which is 1000 by default. The cursor rejected by the loader
+// (see [blockchain.ErrInvalidCursor]) is answered as a bad request.
+//
+// The request with the "height" query parameter loads the single block
+// with the specified height, if the loader implements
+// the [blockchain.IndexedLoader] interface; the response is the same
+// JSON object without the next cursor.
+type HTTPHandler struct {
+	params HTTPHandlerParams
+}
+
+// NewHTTPHandler ...
+func NewHTTPHandler(params HTTPHandlerParams) HTTPHandler {
+	return HTTPHandler{params: params}
+}
+
+// ServeHTTP ...
+func (handler HTTPHandler) ServeHTTP(
+	writer http.ResponseWriter,
+	request *http.Request,
+) {
+	if request.Method != http.MethodGet {
+		writer.Header().Set("Allow", http.MethodGet)
+		http.Error(
+			writer,
+			http.StatusText(http.StatusMethodNotAllowed),
+			http.StatusMethodNotAllowed,
+		)
+
+		return
+	}
+
+	query := request.URL.Query()
+	if query.Has(heightQueryParameter) {
+		handler.serveBlockByHeight(writer, query.Get(heightQueryParameter))
+		return
+	}
+
+	count, err := strconv.Atoi(query.Get(countQueryParameter))
+	if err != nil || count <= 0 {
+		http.Error(writer, "invalid block count", http.StatusBadRequest)
+		return
+	}
+	if maxBlockCount := handler.maxBlockCount(); count > maxBlockCount {
+		count = maxBlockCount
+	}
+
+	var cursor interface{}
+	if query.Has(cursorQueryParameter) {
+		cursor, err = handler.params.CursorCodec.DecodeCursor(
+			query.Get(cursorQueryParameter),
+		)
+		if err != nil {
+			http.Error(writer, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+	}
+
+	responseBytes, err := handler.loadBlocks(cursor, count)
+	if err != nil {
+		if errors.Is(err, blockchain.ErrInvalidCursor) {
+			http.Error(writer, "invalid cursor", http.StatusBadRequest)
+			return
+		}
+
+		http.Error(
+			writer,
+			http.StatusText(http.StatusInternalServerError),
+			http.StatusInternalServerError,
+		)
+
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(responseBytes) // nolint: errcheck, gosec
+}
+
+func (handler HTTPHandler) serveBlockByHeight(
+	writer http.ResponseWriter,
+	rawHeight string,
+) {
+	height, err := strconv.Atoi(rawHeight)
+	if err != nil || height < 0 {
+		http.Error(writer, "invalid block height", http.StatusBadRequest)
+		return
+	}
+
+	responseBytes, err := handler.loadBlockByHeight(height)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, blockchain.ErrNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, errors.ErrUnsupported):
+			statusCode = http.StatusNotImplemented
+		}
+
+		http.Error(writer, http.StatusText(statusCode), statusCode)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(responseBytes) // nolint: errcheck, gosec
+}
+
+func (handler HTTPHandler) maxBlockCount() int {
+	return handler.params.MaxBlockCount.OrElse(defaultMaxBlockCount)
+}
+
+func (handler HTTPHandler) loadBlocks(
+	cursor interface{},
+	count int,
+) ([]byte, error) {
+	blocks, nextCursor, err := handler.params.Loader.LoadBlocks(cursor, count)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the blocks: %w", err)
+	}
+
+	response := httpResponse{Blocks: blocks}
+	if nextCursor != nil {
+		rawNextCursor, err := handler.params.CursorCodec.EncodeCursor(nextCursor)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode the next cursor: %w", err)
+		}
+
+		response.NextCursor = &rawNextCursor
+	}
+
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the response: %w", err)
+	}
+
+	return responseBytes, nil
+}
+
+func (handler HTTPHandler) loadBlockByHeight(height int) ([]byte, error) {
+	indexedLoader, ok := handler.params.Loader.(blockchain.IndexedLoader)
+	if !ok {
+		return nil, errors.ErrUnsupported
+	}
+
+	block, err := indexedLoader.LoadBlockByHeight(height)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the block: %w", err)
+	}
+
+	responseBytes, err :=
+		json.Marshal(httpResponse{Blocks: blockchain.BlockGroup{block}})
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal the response: %w", err)
+	}
+
+	return responseBytes, nil
+}
@@ -0,0 +1,460 @@
+package loaders
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestHTTPHandler_ServeHTTP(test *testing.T) {
+	type fields struct {
+		loader        blockchain.Loader
+		cursorCodec   CursorCodec
+		maxBlockCount mo.Option[int]
+	}
+	type args struct {
+		method string
+		target string
+	}
+
+	for _, data := range []struct {
+		name           string
+		fields         fields
+		args           args
+		wantStatusCode int
+		wantBody       string
+	}{
+		{
+			name: "success/without a cursor",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.
+						On("LoadBlocks", nil, 2).
+						Return(
+							blockchain.BlockGroup{
+								{
+									Timestamp: clock().Add(time.Hour),
+									Data:      blockchain.NewData("block #2"),
+									Hash:      "hash #2",
+									PrevHash:  "hash #1",
+									Height:    1,
+								},
+								{
+									Timestamp: clock(),
+									Data:      blockchain.NewData("block #1"),
+									Hash:      "hash #1",
+									PrevHash:  "",
+									Height:    0,
+								},
+							},
+							2,
+							nil,
+						)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=2",
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+				"Blocks": [
+					{
+						"Timestamp": "2006-01-02T16:04:05Z",
+						"Data": {"Type": "text", "Text": "block #2"},
+						"Hash": "hash #2",
+						"PrevHash": "hash #1",
+						"Height": 1
+					},
+					{
+						"Timestamp": "2006-01-02T15:04:05Z",
+						"Data": {"Type": "text", "Text": "block #1"},
+						"Hash": "hash #1",
+						"PrevHash": "",
+						"Height": 0
+					}
+				],
+				"NextCursor": "Mg"
+			}`,
+		},
+		{
+			name: "success/with a cursor",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.
+						On("LoadBlocks", 2, 2).
+						Return(nil, 2, nil)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?cursor=Mg&count=2",
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"Blocks": null, "NextCursor": "Mg"}`,
+		},
+		{
+			name: "success/without a next cursor",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.
+						On("LoadBlocks", nil, 2).
+						Return(nil, nil, nil)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=2",
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"Blocks": null, "NextCursor": null}`,
+		},
+		{
+			name: "success/with the limited block count",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.
+						On("LoadBlocks", nil, 5).
+						Return(nil, 5, nil)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.Some(5),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=100",
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"Blocks": null, "NextCursor": "NQ"}`,
+		},
+		{
+			name: "success/with the huge block count",
+			fields: fields{
+				loader: MemoryLoader(blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+				}),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=9223372036854775807&cursor=MQ",
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"Blocks": null, "NextCursor": "MQ"}`,
+		},
+		{
+			name: "success/with the default limit of the block count",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.
+						On("LoadBlocks", nil, defaultMaxBlockCount).
+						Return(nil, nil, nil)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=100000",
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       `{"Blocks": null, "NextCursor": null}`,
+		},
+		{
+			name: "success/with a height",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockIndexedLoader)
+					loader.
+						On("LoadBlockByHeight", 1).
+						Return(
+							blockchain.Block{
+								Timestamp: clock().Add(time.Hour),
+								Data:      blockchain.NewData("block #2"),
+								Hash:      "hash #2",
+								PrevHash:  "hash #1",
+								Height:    1,
+							},
+							nil,
+						)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?height=1",
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody: `{
+				"Blocks": [
+					{
+						"Timestamp": "2006-01-02T16:04:05Z",
+						"Data": {"Type": "text", "Text": "block #2"},
+						"Hash": "hash #2",
+						"PrevHash": "hash #1",
+						"Height": 1
+					}
+				],
+				"NextCursor": null
+			}`,
+		},
+		{
+			name: "error/unsupported method",
+			fields: fields{
+				loader:        new(MockLoader),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodPost,
+				target: "/blocks?count=2",
+			},
+			wantStatusCode: http.StatusMethodNotAllowed,
+			wantBody:       "",
+		},
+		{
+			name: "error/invalid block count",
+			fields: fields{
+				loader:        new(MockLoader),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=-2",
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "",
+		},
+		{
+			name: "error/invalid cursor",
+			fields: fields{
+				loader:        new(MockLoader),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?cursor=invalid!&count=2",
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "",
+		},
+		{
+			name: "error/negative cursor",
+			fields: fields{
+				loader: MemoryLoader(blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+				}),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=1&cursor=LTU",
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "",
+		},
+		{
+			name: "error/cursor of an unsupported type",
+			fields: fields{
+				loader: MemoryLoader(blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+				}),
+				cursorCodec:   JSONCursorCodec[string]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=1&cursor=IjIi",
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "",
+		},
+		{
+			name: "error/unable to load the blocks",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.
+						On("LoadBlocks", nil, 2).
+						Return(nil, nil, iotest.ErrTimeout)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=2",
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "",
+		},
+		{
+			name: "error/invalid block height",
+			fields: fields{
+				loader:        new(MockIndexedLoader),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?height=-1",
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantBody:       "",
+		},
+		{
+			name: "error/unsupported loading by a height",
+			fields: fields{
+				loader:        new(MockLoader),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?height=1",
+			},
+			wantStatusCode: http.StatusNotImplemented,
+			wantBody:       "",
+		},
+		{
+			name: "error/block with the height not found",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockIndexedLoader)
+					loader.
+						On("LoadBlockByHeight", 1).
+						Return(blockchain.Block{}, blockchain.ErrNotFound)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?height=1",
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantBody:       "",
+		},
+		{
+			name: "error/unable to load the block by the height",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockIndexedLoader)
+					loader.
+						On("LoadBlockByHeight", 1).
+						Return(blockchain.Block{}, iotest.ErrTimeout)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?height=1",
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "",
+		},
+		{
+			name: "error/unable to encode the next cursor",
+			fields: fields{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.
+						On("LoadBlocks", nil, 2).
+						Return(nil, "2", nil)
+
+					return loader
+				}(),
+				cursorCodec:   JSONCursorCodec[int]{},
+				maxBlockCount: mo.None[int](),
+			},
+			args: args{
+				method: http.MethodGet,
+				target: "/blocks?count=2",
+			},
+			wantStatusCode: http.StatusInternalServerError,
+			wantBody:       "",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			handler := NewHTTPHandler(HTTPHandlerParams{
+				Loader:        data.fields.loader,
+				CursorCodec:   data.fields.cursorCodec,
+				MaxBlockCount: data.fields.maxBlockCount,
+			})
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(data.args.method, data.args.target, nil)
+			handler.ServeHTTP(recorder, request)
+
+			if _, ok := data.fields.loader.(MemoryLoader); !ok {
+				mock.AssertExpectationsForObjects(test, data.fields.loader)
+			}
+			assert.Equal(test, data.wantStatusCode, recorder.Code)
+			if data.wantBody != "" {
+				assert.Equal(
+					test,
+					"application/json",
+					recorder.Header().Get("Content-Type"),
+				)
+				assert.JSONEq(test, data.wantBody, recorder.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package loaders
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+const (
+	maxErrorMessageSize    = 1024
+	defaultClientTimeout   = 30 * time.Second
+	defaultMaxResponseSize = 32 << 20 // 32 MiB
+)
+
+var (
+	errUnexpectedResponseStatus = errors.New("unexpected response status")
+	errTooLargeResponse         = errors.New("too large response")
+
+	defaultClient = &http.Client{Timeout: defaultClientTimeout}
+)
+
+// HTTPLoaderParams ...
+//
+// The client with the 30-second timeout is used, and the maximal response size
+// is 32 MiB by default.
+type HTTPLoaderParams struct {
+	URL             string
+	Client          mo.Option[*http.Client]
+	MaxResponseSize mo.Option[int64]
+}
+
+// HTTPLoader ...
+//
+// It loads blocks from the remote [HTTPHandler] handler. Its cursors are
+// opaque strings; the nil cursor corresponds to the start of the blocks.
+//
+// It implements the [blockchain.IndexedLoader] interface; the lookup
+// by a height returns the [errors.ErrUnsupported] error if the remote handler
+// doesn't support it.
+type HTTPLoader struct {
+	params HTTPLoaderParams
+}
+
+// NewHTTPLoader ...
+func NewHTTPLoader(params HTTPLoaderParams) HTTPLoader {
+	return HTTPLoader{params: params}
+}
+
+// LoadBlocks ...
+func (loader HTTPLoader) LoadBlocks(cursor interface{}, count int) (
+	blocks blockchain.BlockGroup,
+	nextCursor interface{},
+	err error,
+) {
+	requestURL, err := url.Parse(loader.params.URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to parse the URL: %w", err)
+	}
+
+	query := requestURL.Query()
+	query.Set(countQueryParameter, strconv.Itoa(count))
+	if cursor != nil {
+		typedCursor, ok := cursor.(string)
+		if !ok {
+			return nil, nil, fmt.Errorf("unsupported cursor type %T", cursor)
+		}
+
+		query.Set(cursorQueryParameter, typedCursor)
+	}
+	requestURL.RawQuery = query.Encode()
+
+	unmarshalledResponse, err := loader.sendRequest(requestURL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if unmarshalledResponse.NextCursor != nil {
+		nextCursor = *unmarshalledResponse.NextCursor
+	}
+
+	return unmarshalledResponse.Blocks, nextCursor, nil
+}
+
+// LoadBlockByHeight ...
+func (loader HTTPLoader) LoadBlockByHeight(
+	height int,
+) (blockchain.Block, error) {
+	requestURL, err := url.Parse(loader.params.URL)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf("unable to parse the URL: %w", err)
+	}
+
+	query := requestURL.Query()
+	query.Set(heightQueryParameter, strconv.Itoa(height))
+	requestURL.RawQuery = query.Encode()
+
+	unmarshalledResponse, err := loader.sendRequest(requestURL)
+	if err != nil {
+		return blockchain.Block{}, err
+	}
+
+	if len(unmarshalledResponse.Blocks) != 1 {
+		return blockchain.Block{}, fmt.Errorf(
+			"unexpected block count %d",
+			len(unmarshalledResponse.Blocks),
+		)
+	}
+
+	return unmarshalledResponse.Blocks[0], nil
+}
+
+func (loader HTTPLoader) sendRequest(
+	requestURL *url.URL,
+) (httpResponse, error) {
+	response, err := loader.client().Get(requestURL.String())
+	if err != nil {
+		return httpResponse{}, fmt.Errorf("unable to send the request: %w", err)
+	}
+	defer response.Body.Close() // nolint: errcheck
+
+	if response.StatusCode != http.StatusOK {
+		var statusErr error
+		switch response.StatusCode {
+		case http.StatusNotFound:
+			statusErr = blockchain.ErrNotFound
+		case http.StatusNotImplemented:
+			statusErr = errors.ErrUnsupported
+		default:
+			statusErr = errUnexpectedResponseStatus
+		}
+
+		message, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorMessageSize))
+		return httpResponse{}, fmt.Errorf(
+			"%w (status %d): %s",
+			statusErr,
+			response.StatusCode,
+			bytes.TrimSpace(message),
+		)
+	}
+
+	// the extra byte allows detecting the exceeding of the maximal size
+	maxResponseSize := loader.maxResponseSize()
+	limitedBody := &io.LimitedReader{R: response.Body, N: maxResponseSize + 1}
+
+	var unmarshalledResponse httpResponse
+	decodingErr := json.NewDecoder(limitedBody).Decode(&unmarshalledResponse)
+	if limitedBody.N == 0 {
+		return httpResponse{}, fmt.Errorf(
+			"the response size exceeds the maximal one %d: %w",
+			maxResponseSize,
+			errTooLargeResponse,
+		)
+	}
+	if decodingErr != nil {
+		return httpResponse{}, fmt.Errorf(
+			"unable to decode the response: %w",
+			decodingErr,
+		)
+	}
+
+	return unmarshalledResponse, nil
+}
+
+func (loader HTTPLoader) client() *http.Client {
+	return loader.params.Client.OrElse(defaultClient)
+}
+
+func (loader HTTPLoader) maxResponseSize() int64 {
+	return loader.params.MaxResponseSize.OrElse(defaultMaxResponseSize)
+}
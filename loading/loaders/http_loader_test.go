@@ -0,0 +1,348 @@
+package loaders
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestHTTPLoader_LoadBlocks(test *testing.T) {
+	blocks := blockchain.BlockGroup{
+		{
+			Timestamp: clock().Add(2 * time.Hour),
+			Data:      blockchain.NewData("block #3"),
+			Hash:      "hash #3",
+			PrevHash:  "hash #2",
+			Height:    2,
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data: blockchain.NewMerkleData([]blockchain.Data{
+				blockchain.NewData("block #2"),
+			}),
+			Hash:     "hash #2",
+			PrevHash: "hash #1",
+			Height:   1,
+		},
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+			Height:    0,
+		},
+	}
+	server := httptest.NewServer(NewHTTPHandler(HTTPHandlerParams{
+		Loader:        MemoryLoader(blocks),
+		CursorCodec:   JSONCursorCodec[int]{},
+		MaxBlockCount: mo.None[int](),
+	}))
+	defer server.Close()
+
+	loader := NewHTTPLoader(HTTPLoaderParams{
+		URL:    server.URL + "/blocks",
+		Client: mo.Some(server.Client()),
+	})
+
+	var gotBlocks blockchain.BlockGroup
+	var cursor interface{}
+	for {
+		blocksChunk, nextCursor, err := loader.LoadBlocks(cursor, 2)
+		require.NoError(test, err)
+		require.IsType(test, "", nextCursor)
+
+		if len(blocksChunk) == 0 {
+			break
+		}
+
+		gotBlocks = append(gotBlocks, blocksChunk...)
+		cursor = nextCursor
+	}
+
+	assert.Equal(test, blocks, gotBlocks)
+}
+
+func TestHTTPLoader_LoadBlocks_withError(test *testing.T) {
+	type args struct {
+		cursor interface{}
+	}
+
+	for _, data := range []struct {
+		name    string
+		handler http.HandlerFunc
+		args    args
+	}{
+		{
+			name: "with an unsupported cursor type",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				test.Error("unexpected request")
+			},
+			args: args{
+				cursor: 23,
+			},
+		},
+		{
+			name: "with an unexpected response status",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				http.Error(writer, "invalid cursor", http.StatusBadRequest)
+			},
+			args: args{
+				cursor: "invalid",
+			},
+		},
+		{
+			name: "with an invalid response",
+			handler: func(writer http.ResponseWriter, request *http.Request) {
+				writer.Write([]byte("invalid")) // nolint: errcheck, gosec
+			},
+			args: args{
+				cursor: nil,
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			server := httptest.NewServer(data.handler)
+			defer server.Close()
+
+			loader := NewHTTPLoader(HTTPLoaderParams{
+				URL:    server.URL,
+				Client: mo.None[*http.Client](),
+			})
+			gotBlocks, gotNextCursor, gotErr := loader.LoadBlocks(data.args.cursor, 2)
+
+			assert.Nil(test, gotBlocks)
+			assert.Nil(test, gotNextCursor)
+			assert.Error(test, gotErr)
+		})
+	}
+}
+
+func TestHTTPLoader_LoadBlocks_withMaxResponseSize(test *testing.T) {
+	const response = `{"Blocks":[],"NextCursor":null}`
+
+	type args struct {
+		maxResponseSize mo.Option[int64]
+	}
+
+	for _, data := range []struct {
+		name       string
+		args       args
+		wantBlocks blockchain.BlockGroup
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with the default size",
+			args: args{
+				maxResponseSize: mo.None[int64](),
+			},
+			wantBlocks: blockchain.BlockGroup{},
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "success with the response of the maximal size",
+			args: args{
+				maxResponseSize: mo.Some(int64(len(response))),
+			},
+			wantBlocks: blockchain.BlockGroup{},
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error with the too large response",
+			args: args{
+				maxResponseSize: mo.Some(int64(len(response) - 1)),
+			},
+			wantBlocks: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, errTooLargeResponse)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(
+				func(writer http.ResponseWriter, request *http.Request) {
+					writer.Write([]byte(response)) // nolint: errcheck, gosec
+				},
+			))
+			defer server.Close()
+
+			loader := NewHTTPLoader(HTTPLoaderParams{
+				URL:             server.URL,
+				Client:          mo.Some(server.Client()),
+				MaxResponseSize: data.args.maxResponseSize,
+			})
+			gotBlocks, _, gotErr := loader.LoadBlocks(nil, 2)
+
+			assert.Equal(test, data.wantBlocks, gotBlocks)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestHTTPLoader_LoadBlocks_withDefaultTimeout(test *testing.T) {
+	prevTimeout := defaultClient.Timeout
+	defaultClient.Timeout = 10 * time.Millisecond
+	defer func() { defaultClient.Timeout = prevTimeout }()
+
+	isRequestDone := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(
+		func(writer http.ResponseWriter, request *http.Request) {
+			<-isRequestDone
+		},
+	))
+	defer server.Close()
+	defer close(isRequestDone)
+
+	loader := NewHTTPLoader(HTTPLoaderParams{
+		URL:    server.URL,
+		Client: mo.None[*http.Client](),
+	})
+	gotBlocks, gotNextCursor, gotErr := loader.LoadBlocks(nil, 2)
+
+	assert.Nil(test, gotBlocks)
+	assert.Nil(test, gotNextCursor)
+
+	var netErr net.Error
+	if assert.ErrorAs(test, gotErr, &netErr) {
+		assert.True(test, netErr.Timeout())
+	}
+}
+
+func TestHTTPLoader_client(test *testing.T) {
+	loader := NewHTTPLoader(HTTPLoaderParams{
+		URL:    "http://example.com/blocks",
+		Client: mo.None[*http.Client](),
+	})
+
+	gotClient := loader.client()
+
+	assert.NotSame(test, http.DefaultClient, gotClient)
+	assert.Equal(test, defaultClientTimeout, gotClient.Timeout)
+}
+
+func TestHTTPLoader_LoadBlocks_withUnavailablePeer(test *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	loader := NewHTTPLoader(HTTPLoaderParams{
+		URL:    server.URL,
+		Client: mo.None[*http.Client](),
+	})
+	gotBlocks, gotNextCursor, gotErr := loader.LoadBlocks(nil, 2)
+
+	assert.Nil(test, gotBlocks)
+	assert.Nil(test, gotNextCursor)
+	assert.Error(test, gotErr)
+}
+
+func TestHTTPLoader_LoadBlockByHeight(test *testing.T) {
+	block := blockchain.Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      blockchain.NewData("block #2"),
+		Hash:      "hash #2",
+		PrevHash:  "hash #1",
+		Height:    1,
+	}
+
+	for _, data := range []struct {
+		name      string
+		loader    blockchain.Loader
+		wantBlock blockchain.Block
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.On("LoadBlockByHeight", 1).Return(block, nil)
+
+				return loader
+			}(),
+			wantBlock: block,
+			wantErr:   assert.NoError,
+		},
+		{
+			name: "error/not found",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.
+					On("LoadBlockByHeight", 1).
+					Return(blockchain.Block{}, blockchain.ErrNotFound)
+
+				return loader
+			}(),
+			wantBlock: blockchain.Block{},
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrNotFound, msgAndArgs...)
+			},
+		},
+		{
+			name:      "error/unsupported",
+			loader:    new(MockLoader),
+			wantBlock: blockchain.Block{},
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.ErrorIs(test, err, errors.ErrUnsupported, msgAndArgs...)
+			},
+		},
+		{
+			name: "error/other",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.
+					On("LoadBlockByHeight", 1).
+					Return(blockchain.Block{}, iotest.ErrTimeout)
+
+				return loader
+			}(),
+			wantBlock: blockchain.Block{},
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.ErrorIs(
+					test,
+					err,
+					errUnexpectedResponseStatus,
+					msgAndArgs...,
+				)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			server := httptest.NewServer(NewHTTPHandler(HTTPHandlerParams{
+				Loader:        data.loader,
+				CursorCodec:   JSONCursorCodec[int]{},
+				MaxBlockCount: mo.None[int](),
+			}))
+			defer server.Close()
+
+			loader := NewHTTPLoader(HTTPLoaderParams{
+				URL:    server.URL,
+				Client: mo.Some(server.Client()),
+			})
+			gotBlock, gotErr := loader.LoadBlockByHeight(1)
+
+			mock.AssertExpectationsForObjects(test, data.loader)
+			assert.Equal(test, data.wantBlock, gotBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
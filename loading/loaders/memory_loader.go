@@ -1,6 +1,9 @@
 package loaders
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/thewizardplusplus/go-blockchain"
 )
 
@@ -17,12 +20,27 @@ func (loader MemoryLoader) LoadBlocks(cursor interface{}, count int) (
 
 	var startIndex int
 	if cursor != nil {
-		startIndex = cursor.(int)
+		typedCursor, ok := cursor.(int)
+		if !ok {
+			return nil, nil, errors.Join(
+				fmt.Errorf("unsupported cursor type %T", cursor),
+				blockchain.ErrInvalidCursor,
+			)
+		}
+		if typedCursor < 0 {
+			return nil, nil, errors.Join(
+				fmt.Errorf("the cursor %d is negative", typedCursor),
+				blockchain.ErrInvalidCursor,
+			)
+		}
+
+		startIndex = typedCursor
 	}
 
-	endIndex := startIndex + count
-	if maximalEndIndex := len(blocks); endIndex > maximalEndIndex {
-		endIndex = maximalEndIndex
+	// compare with the remaining length to avoid the overflow of the sum
+	endIndex := len(blocks)
+	if count < endIndex-startIndex {
+		endIndex = startIndex + count
 	}
 
 	if maximalStartIndex := len(blocks) - 1; startIndex > maximalStartIndex {
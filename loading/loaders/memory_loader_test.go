@@ -1,6 +1,7 @@
 package loaders
 
 import (
+	"math"
 	"testing"
 	"time"
 
@@ -211,6 +212,131 @@ func TestMemoryLoader_LoadBlocks(test *testing.T) {
 			wantNextCursor: 4,
 			wantErr:        assert.NoError,
 		},
+		{
+			name: "with the loading of the chunk by the huge count",
+			loader: MemoryLoader(blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(3 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #4",
+					PrevHash:  "hash #3",
+				},
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			}),
+			args: args{
+				cursor: 2,
+				count:  math.MaxInt,
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantNextCursor: 4,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "error with the negative cursor",
+			loader: MemoryLoader(blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(3 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #4",
+					PrevHash:  "hash #3",
+				},
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			}),
+			args: args{
+				cursor: -5,
+				count:  2,
+			},
+			wantBlocks:     nil,
+			wantNextCursor: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrInvalidCursor)
+			},
+		},
+		{
+			name: "error with the cursor of an unsupported type",
+			loader: MemoryLoader(blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(3 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #4",
+					PrevHash:  "hash #3",
+				},
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			}),
+			args: args{
+				cursor: "2",
+				count:  2,
+			},
+			wantBlocks:     nil,
+			wantNextCursor: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrInvalidCursor)
+			},
+		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
 			gotBlocks, gotNextCursor, gotErr :=
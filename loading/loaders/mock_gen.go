@@ -13,3 +13,23 @@ import (
 type Data interface {
 	blockchain.Data
 }
+
+//go:generate mockery --name=Loader --inpackage --case=underscore --testonly
+
+// Loader ...
+//
+// It's used only for mock generating.
+//
+type Loader interface {
+	blockchain.Loader
+}
+
+//go:generate mockery --name=IndexedLoader --inpackage --case=underscore --testonly
+
+// IndexedLoader ...
+//
+// It's used only for mock generating.
+//
+type IndexedLoader interface {
+	blockchain.IndexedLoader
+}
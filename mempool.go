@@ -0,0 +1,236 @@
+package blockchain
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/samber/mo"
+)
+
+// ...
+var (
+	ErrDuplicateData = errors.New("duplicate data")
+	ErrMempoolFull   = errors.New("mempool is full")
+)
+
+// DataPriority ...
+//
+// The data with a greater priority is included in blocks earlier.
+type DataPriority func(data Data) int
+
+// MempoolParams ...
+//
+// The mempool is unlimited by default, and all the data has the same
+// priority, so it's ordered by the time of adding.
+type MempoolParams struct {
+	MaxSize  mo.Option[int]
+	Priority mo.Option[DataPriority]
+}
+
+// Mempool ...
+//
+// It holds the pending data awaiting inclusion in blocks. The data is
+// deduplicated via the [DataComparer] interface and ordered by its priority,
+// and then by the time of adding.
+//
+// It's safe for concurrent use.
+type Mempool struct {
+	params MempoolParams
+
+	lock sync.Mutex
+	// it's sorted in the inclusion order
+	items []mempoolItem
+	// it's incremented on every adding of an item
+	lastSequence int
+}
+
+type mempoolItem struct {
+	data     Data
+	priority int
+	sequence int
+}
+
+func compareMempoolItems(item mempoolItem, anotherItem mempoolItem) int {
+	if item.priority != anotherItem.priority {
+		// the greater priority goes first, so they are compared in reverse
+		return cmp.Compare(anotherItem.priority, item.priority)
+	}
+
+	return cmp.Compare(item.sequence, anotherItem.sequence)
+}
+
+// NewMempool ...
+func NewMempool(params MempoolParams) (*Mempool, error) {
+	if maxSize, isPresent := params.MaxSize.Get(); isPresent && maxSize <= 0 {
+		return nil, fmt.Errorf("the maximal size %d should be positive", maxSize)
+	}
+
+	return &Mempool{params: params}, nil
+}
+
+// Len ...
+func (mempool *Mempool) Len() int {
+	mempool.lock.Lock()
+	defer mempool.lock.Unlock()
+
+	return len(mempool.items)
+}
+
+// Add ...
+//
+// If equal data is already pending, it returns the [ErrDuplicateData] error.
+// If the maximal size is reached, it returns the [ErrMempoolFull] error.
+//
+// The [Data] interface provides no key to index the data, so the duplicates
+// are searched by comparing the data with all the pending one, i.e.,
+// the adding takes linear time.
+func (mempool *Mempool) Add(data Data) error {
+	mempool.lock.Lock()
+	defer mempool.lock.Unlock()
+
+	if mempool.contains(data) {
+		return ErrDuplicateData
+	}
+	if maxSize, isPresent := mempool.params.MaxSize.Get(); isPresent &&
+		len(mempool.items) >= maxSize {
+		return ErrMempoolFull
+	}
+
+	mempool.insert(data)
+	return nil
+}
+
+// Pending ...
+//
+// It returns up to the specified count of the pending data in the inclusion
+// order without removing it. A non-positive count means all the data.
+func (mempool *Mempool) Pending(count int) []Data {
+	mempool.lock.Lock()
+	defer mempool.lock.Unlock()
+
+	if count <= 0 || count > len(mempool.items) {
+		count = len(mempool.items)
+	}
+
+	pendingData := make([]Data, 0, count)
+	for _, item := range mempool.items[:count] {
+		pendingData = append(pendingData, item.data)
+	}
+
+	return pendingData
+}
+
+// Remove ...
+//
+// It removes the pending data equal to the specified one.
+func (mempool *Mempool) Remove(data []Data) {
+	mempool.lock.Lock()
+	defer mempool.lock.Unlock()
+
+	mempool.items = slices.DeleteFunc(mempool.items, func(item mempoolItem) bool {
+		return containsData(data, item.data)
+	})
+}
+
+// it removes up to the specified count of the items in the inclusion order;
+// a non-positive count means all the items
+func (mempool *Mempool) take(count int) []mempoolItem {
+	mempool.lock.Lock()
+	defer mempool.lock.Unlock()
+
+	if count <= 0 || count > len(mempool.items) {
+		count = len(mempool.items)
+	}
+
+	takenItems := slices.Clone(mempool.items[:count])
+	mempool.items = slices.Delete(mempool.items, 0, count)
+
+	return takenItems
+}
+
+// it returns the taken items keeping their order and skips the duplicates;
+// the new data can be added while the items are taken, so the items
+// with the lowest priority are evicted if the maximal size is exceeded
+func (mempool *Mempool) putBack(items []mempoolItem) {
+	mempool.lock.Lock()
+	defer mempool.lock.Unlock()
+
+	for _, item := range items {
+		if !mempool.contains(item.data) {
+			mempool.insertItem(item)
+		}
+	}
+
+	mempool.evictExcessItems()
+}
+
+// it skips the duplicates; the restored data was already accepted once,
+// so instead of rejecting it, the items with the lowest priority are evicted
+// if the maximal size is exceeded
+func (mempool *Mempool) restore(data []Data) {
+	mempool.lock.Lock()
+	defer mempool.lock.Unlock()
+
+	for _, dataItem := range data {
+		if !mempool.contains(dataItem) {
+			mempool.insert(dataItem)
+		}
+	}
+
+	mempool.evictExcessItems()
+}
+
+// it should be called under the lock
+func (mempool *Mempool) contains(data Data) bool {
+	return slices.ContainsFunc(mempool.items, func(item mempoolItem) bool {
+		return item.data.Equal(data)
+	})
+}
+
+// it should be called under the lock
+func (mempool *Mempool) insert(data Data) {
+	mempool.lastSequence++
+
+	mempool.insertItem(mempoolItem{
+		data:     data,
+		priority: mempool.priority(data),
+		sequence: mempool.lastSequence,
+	})
+}
+
+// it should be called under the lock
+func (mempool *Mempool) insertItem(item mempoolItem) {
+	index, _ :=
+		slices.BinarySearchFunc(mempool.items, item, compareMempoolItems)
+	mempool.items = slices.Insert(mempool.items, index, item)
+}
+
+// it should be called under the lock;
+// the items are sorted in the inclusion order, so the last ones
+// have the lowest priority
+func (mempool *Mempool) evictExcessItems() {
+	maxSize, isPresent := mempool.params.MaxSize.Get()
+	if !isPresent || len(mempool.items) <= maxSize {
+		return
+	}
+
+	mempool.items = slices.Delete(mempool.items, maxSize, len(mempool.items))
+}
+
+func (mempool *Mempool) priority(data Data) int {
+	priority, isPresent := mempool.params.Priority.Get()
+	if !isPresent {
+		return 0
+	}
+
+	return priority(data)
+}
+
+func containsData(data []Data, dataItem Data) bool {
+	return slices.ContainsFunc(data, func(anotherDataItem Data) bool {
+		return anotherDataItem.Equal(dataItem)
+	})
+}
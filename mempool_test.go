@@ -0,0 +1,314 @@
+package blockchain
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMempool(test *testing.T) {
+	type args struct {
+		params MempoolParams
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success without the maximal size",
+			args: args{
+				params: MempoolParams{},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the maximal size",
+			args: args{
+				params: MempoolParams{
+					MaxSize: mo.Some(23),
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				params: MempoolParams{
+					MaxSize: mo.Some(0),
+				},
+			},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			_, err := NewMempool(data.args.params)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestMempool_Add(test *testing.T) {
+	type fields struct {
+		params MempoolParams
+		data   []Data
+	}
+	type args struct {
+		data Data
+	}
+
+	for _, data := range []struct {
+		name        string
+		fields      fields
+		args        args
+		wantPending []Data
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/without a priority",
+			fields: fields{
+				params: MempoolParams{},
+				data:   []Data{NewData("one"), NewData("two")},
+			},
+			args: args{
+				data: NewData("three"),
+			},
+			wantPending: []Data{NewData("one"), NewData("two"), NewData("three")},
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "success/with a priority",
+			fields: fields{
+				params: MempoolParams{
+					Priority: mo.Some[DataPriority](func(data Data) int {
+						return len(data.String())
+					}),
+				},
+				data: []Data{NewData("one"), NewData("three"), NewData("four")},
+			},
+			args: args{
+				data: NewData("two"),
+			},
+			wantPending: []Data{
+				NewData("three"),
+				NewData("four"),
+				NewData("one"),
+				NewData("two"),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/duplicate data",
+			fields: fields{
+				params: MempoolParams{},
+				data:   []Data{NewData("one"), NewData("two")},
+			},
+			args: args{
+				data: NewData("two"),
+			},
+			wantPending: []Data{NewData("one"), NewData("two")},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrDuplicateData)
+			},
+		},
+		{
+			name: "error/full mempool",
+			fields: fields{
+				params: MempoolParams{
+					MaxSize: mo.Some(2),
+				},
+				data: []Data{NewData("one"), NewData("two")},
+			},
+			args: args{
+				data: NewData("three"),
+			},
+			wantPending: []Data{NewData("one"), NewData("two")},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrMempoolFull)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			mempool, err := NewMempool(data.fields.params)
+			require.NoError(test, err)
+
+			for _, dataItem := range data.fields.data {
+				err := mempool.Add(dataItem)
+				require.NoError(test, err)
+			}
+
+			gotErr := mempool.Add(data.args.data)
+
+			assert.Equal(test, data.wantPending, mempool.Pending(0))
+			assert.Equal(test, len(data.wantPending), mempool.Len())
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestMempool_Pending(test *testing.T) {
+	type args struct {
+		count int
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want []Data
+	}{
+		{
+			name: "with a count less than the length",
+			args: args{
+				count: 2,
+			},
+			want: []Data{NewData("one"), NewData("two")},
+		},
+		{
+			name: "with a count greater than the length",
+			args: args{
+				count: 23,
+			},
+			want: []Data{NewData("one"), NewData("two"), NewData("three")},
+		},
+		{
+			name: "with a non-positive count",
+			args: args{
+				count: 0,
+			},
+			want: []Data{NewData("one"), NewData("two"), NewData("three")},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			mempool, err := NewMempool(MempoolParams{})
+			require.NoError(test, err)
+
+			for _, dataItem := range []string{"one", "two", "three"} {
+				err := mempool.Add(NewData(dataItem))
+				require.NoError(test, err)
+			}
+
+			got := mempool.Pending(data.args.count)
+
+			assert.Equal(test, data.want, got)
+			assert.Equal(test, 3, mempool.Len())
+		})
+	}
+}
+
+func TestMempool_Remove(test *testing.T) {
+	mempool, err := NewMempool(MempoolParams{})
+	require.NoError(test, err)
+
+	for _, dataItem := range []string{"one", "two", "three"} {
+		err := mempool.Add(NewData(dataItem))
+		require.NoError(test, err)
+	}
+
+	mempool.Remove([]Data{NewData("two"), NewData("four")})
+
+	wantPending := []Data{NewData("one"), NewData("three")}
+	assert.Equal(test, wantPending, mempool.Pending(0))
+}
+
+func TestMempool_takingAndPuttingBack(test *testing.T) {
+	mempool, err := NewMempool(MempoolParams{
+		Priority: mo.Some[DataPriority](func(data Data) int {
+			return strings.Count(data.String(), "!")
+		}),
+	})
+	require.NoError(test, err)
+
+	for _, dataItem := range []string{"one", "two!", "three"} {
+		err := mempool.Add(NewData(dataItem))
+		require.NoError(test, err)
+	}
+
+	takenItems := mempool.take(2)
+	require.Len(test, takenItems, 2)
+	assert.Equal(test, NewData("two!"), takenItems[0].data)
+	assert.Equal(test, NewData("one"), takenItems[1].data)
+	assert.Equal(test, []Data{NewData("three")}, mempool.Pending(0))
+
+	err = mempool.Add(NewData("four"))
+	require.NoError(test, err)
+
+	mempool.putBack(takenItems)
+
+	wantPending := []Data{
+		NewData("two!"),
+		NewData("one"),
+		NewData("three"),
+		NewData("four"),
+	}
+	assert.Equal(test, wantPending, mempool.Pending(0))
+}
+
+func TestMempool_putBack_withMaxSize(test *testing.T) {
+	mempool, err := NewMempool(MempoolParams{
+		MaxSize: mo.Some(2),
+	})
+	require.NoError(test, err)
+
+	for _, dataItem := range []string{"one", "two"} {
+		err := mempool.Add(NewData(dataItem))
+		require.NoError(test, err)
+	}
+
+	takenItems := mempool.take(1)
+	require.Len(test, takenItems, 1)
+
+	err = mempool.Add(NewData("three"))
+	require.NoError(test, err)
+
+	mempool.putBack(takenItems)
+
+	wantPending := []Data{NewData("one"), NewData("two")}
+	assert.Equal(test, wantPending, mempool.Pending(0))
+}
+
+func TestMempool_restore(test *testing.T) {
+	mempool, err := NewMempool(MempoolParams{
+		MaxSize: mo.Some(2),
+		Priority: mo.Some[DataPriority](func(data Data) int {
+			return strings.Count(data.String(), "!")
+		}),
+	})
+	require.NoError(test, err)
+
+	for _, dataItem := range []string{"one", "two"} {
+		err := mempool.Add(NewData(dataItem))
+		require.NoError(test, err)
+	}
+
+	mempool.restore([]Data{NewData("two"), NewData("three!")})
+
+	wantPending := []Data{NewData("three!"), NewData("one")}
+	assert.Equal(test, wantPending, mempool.Pending(0))
+}
+
+func TestMempool_concurrently(test *testing.T) {
+	mempool, err := NewMempool(MempoolParams{})
+	require.NoError(test, err)
+
+	var waitGroup sync.WaitGroup
+	for index := 0; index < 10; index++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			// all the goroutines add the same data
+			for dataIndex := 0; dataIndex < 10; dataIndex++ {
+				mempool.Add(NewData(dataIndex)) // nolint: errcheck
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	assert.Equal(test, 10, mempool.Len())
+}
@@ -0,0 +1,325 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+)
+
+// MerkleDataType ...
+const MerkleDataType = "merkle"
+
+const (
+	merkleLeafPrefix byte = 0x00
+	merkleNodePrefix byte = 0x01
+)
+
+// ErrInvalidMerkleProof ...
+var ErrInvalidMerkleProof = errors.New("invalid Merkle proof")
+
+// MerkleData ...
+//
+// It holds a list of items and represents them as the root of their Merkle
+// tree, so the root is what gets into the merged data of a block. The tree is
+// built as described in RFC 6962: leaves and nodes are hashed with different
+// prefixes, and an odd node is not duplicated.
+type MerkleData struct {
+	items []Data
+	root  []byte
+}
+
+// NewMerkleData ...
+func NewMerkleData(items []Data) MerkleData {
+	items = slices.Clone(items)
+
+	leafHashes := make([][]byte, 0, len(items))
+	for _, item := range items {
+		leafHashes = append(leafHashes, hashMerkleLeaf(item))
+	}
+
+	return MerkleData{
+		items: items,
+		root:  calculateMerkleRoot(leafHashes),
+	}
+}
+
+func init() {
+	RegisterDataDecoder(MerkleDataType, func(rawData []byte) (Data, error) {
+		var data MerkleData
+		if err := data.UnmarshalBinary(rawData); err != nil {
+			return nil, err
+		}
+
+		return data, nil
+	})
+}
+
+// Items ...
+func (data MerkleData) Items() []Data {
+	return slices.Clone(data.items)
+}
+
+// Root ...
+func (data MerkleData) Root() string {
+	return hex.EncodeToString(data.root)
+}
+
+// String ...
+func (data MerkleData) String() string {
+	return data.Root()
+}
+
+// DataType ...
+func (data MerkleData) DataType() string {
+	return MerkleDataType
+}
+
+// MarshalBinary ...
+//
+// It encodes the items via the [EncodeData] function.
+func (data MerkleData) MarshalBinary() ([]byte, error) {
+	buffer := binary.AppendUvarint(nil, uint64(len(data.items)))
+	for index, item := range data.items {
+		rawItem, err := EncodeData(item)
+		if err != nil {
+			return nil, fmt.Errorf("unable to encode item #%d: %w", index, err)
+		}
+
+		buffer = appendLengthPrefixed(buffer, string(rawItem))
+	}
+
+	return buffer, nil
+}
+
+// UnmarshalBinary ...
+//
+// It decodes the items via the [DecodeData] function
+// and recalculates the Merkle root.
+func (data *MerkleData) UnmarshalBinary(rawData []byte) error {
+	itemCount, itemCountSize := binary.Uvarint(rawData)
+	if itemCountSize <= 0 {
+		return errors.New("unable to read the item count")
+	}
+
+	rawData = rawData[itemCountSize:]
+	if itemCount > uint64(len(rawData)) {
+		return errors.New("the item count exceeds the data size")
+	}
+
+	var items []Data
+	for index := 0; index < int(itemCount); index++ {
+		var rawItem []byte
+		var err error
+		rawItem, rawData, err = readLengthPrefixed(rawData)
+		if err != nil {
+			return fmt.Errorf("unable to read item #%d: %w", index, err)
+		}
+
+		item, err := DecodeData(rawItem)
+		if err != nil {
+			return fmt.Errorf("unable to decode item #%d: %w", index, err)
+		}
+
+		items = append(items, item)
+	}
+	if len(rawData) != 0 {
+		return errors.New("the data contains extra bytes")
+	}
+
+	*data = NewMerkleData(items)
+	return nil
+}
+
+// Equal ...
+func (data MerkleData) Equal(anotherData Data) bool {
+	anotherMerkleData, ok := anotherData.(MerkleData)
+	if !ok || len(data.items) != len(anotherMerkleData.items) {
+		return false
+	}
+
+	for index, item := range data.items {
+		if !item.Equal(anotherMerkleData.items[index]) {
+			return false
+		}
+	}
+
+	return bytes.Equal(data.root, anotherMerkleData.root)
+}
+
+// Proof ...
+func (data MerkleData) Proof(itemIndex int) (MerkleProof, error) {
+	if itemIndex < 0 || itemIndex >= len(data.items) {
+		return MerkleProof{}, fmt.Errorf(
+			"item index %d is out of range [0, %d)",
+			itemIndex,
+			len(data.items),
+		)
+	}
+
+	leafHashes := make([][]byte, 0, len(data.items))
+	for _, item := range data.items {
+		leafHashes = append(leafHashes, hashMerkleLeaf(item))
+	}
+
+	var hashes []string
+	for _, hash := range calculateMerklePath(itemIndex, leafHashes) {
+		hashes = append(hashes, hex.EncodeToString(hash))
+	}
+
+	proof := MerkleProof{
+		ItemIndex: itemIndex,
+		ItemCount: len(data.items),
+		Hashes:    hashes,
+	}
+	return proof, nil
+}
+
+// MerkleProof ...
+//
+// It contains the hashes of the sibling subtrees on the path from the item
+// to the root, starting from the leaf level.
+type MerkleProof struct {
+	ItemIndex int
+	ItemCount int
+	Hashes    []string
+}
+
+// Root ...
+func (proof MerkleProof) Root(item Data) (string, error) {
+	if proof.ItemIndex < 0 || proof.ItemIndex >= proof.ItemCount {
+		return "", errors.Join(
+			fmt.Errorf(
+				"item index %d is out of range [0, %d)",
+				proof.ItemIndex,
+				proof.ItemCount,
+			),
+			ErrInvalidMerkleProof,
+		)
+	}
+
+	// see the verification algorithm in section 2.1.3.2 of RFC 9162
+	index, lastIndex := proof.ItemIndex, proof.ItemCount-1
+	hash := hashMerkleLeaf(item)
+	for _, rawSiblingHash := range proof.Hashes {
+		if lastIndex == 0 {
+			return "", errors.Join(
+				errors.New("the proof contains extra hashes"),
+				ErrInvalidMerkleProof,
+			)
+		}
+
+		siblingHash, err := hex.DecodeString(rawSiblingHash)
+		if err != nil {
+			return "", fmt.Errorf(
+				"unable to decode the hash: %w",
+				errors.Join(err, ErrInvalidMerkleProof),
+			)
+		}
+
+		if index%2 == 1 || index == lastIndex {
+			hash = hashMerkleNode(siblingHash, hash)
+
+			// skip the levels where the node has no sibling
+			for index%2 == 0 && index != 0 {
+				index, lastIndex = index/2, lastIndex/2
+			}
+		} else {
+			hash = hashMerkleNode(hash, siblingHash)
+		}
+
+		index, lastIndex = index/2, lastIndex/2
+	}
+	if lastIndex != 0 {
+		return "", errors.Join(
+			errors.New("the proof lacks hashes"),
+			ErrInvalidMerkleProof,
+		)
+	}
+
+	return hex.EncodeToString(hash), nil
+}
+
+// IsValidMerkleProof ...
+//
+// It checks that the item is included in the block data by the proof.
+// The block data itself isn't required: the block is validated via the proofer
+// with the Merkle root calculated from the proof in place of its data.
+func (block Block) IsValidMerkleProof(
+	item Data,
+	proof MerkleProof,
+	proofer Proofer,
+) error {
+	root, err := proof.Root(item)
+	if err != nil {
+		return fmt.Errorf("unable to calculate the Merkle root: %w", err)
+	}
+
+	block.Data = NewData(root)
+	if err := proofer.Validate(block); err != nil {
+		return fmt.Errorf(
+			"the validation via the proofer was failed: %w",
+			errors.Join(err, ErrInvalidMerkleProof),
+		)
+	}
+
+	return nil
+}
+
+func calculateMerkleRoot(hashes [][]byte) []byte {
+	switch len(hashes) {
+	case 0:
+		emptyRoot := sha256.Sum256(nil)
+		return emptyRoot[:]
+	case 1:
+		return hashes[0]
+	}
+
+	splitIndex := merkleSplitIndex(len(hashes))
+	return hashMerkleNode(
+		calculateMerkleRoot(hashes[:splitIndex]),
+		calculateMerkleRoot(hashes[splitIndex:]),
+	)
+}
+
+func calculateMerklePath(index int, hashes [][]byte) [][]byte {
+	if len(hashes) <= 1 {
+		return nil
+	}
+
+	splitIndex := merkleSplitIndex(len(hashes))
+	if index < splitIndex {
+		return append(
+			calculateMerklePath(index, hashes[:splitIndex]),
+			calculateMerkleRoot(hashes[splitIndex:]),
+		)
+	}
+
+	return append(
+		calculateMerklePath(index-splitIndex, hashes[splitIndex:]),
+		calculateMerkleRoot(hashes[:splitIndex]),
+	)
+}
+
+// it returns the largest power of two less than the count
+func merkleSplitIndex(count int) int {
+	return 1 << (bits.Len(uint(count-1)) - 1)
+}
+
+func hashMerkleLeaf(item Data) []byte {
+	hash := sha256.Sum256(append([]byte{merkleLeafPrefix}, item.String()...))
+	return hash[:]
+}
+
+func hashMerkleNode(leftHash []byte, rightHash []byte) []byte {
+	hasher := sha256.New()
+	hasher.Write([]byte{merkleNodePrefix}) // nolint: errcheck, gosec
+	hasher.Write(leftHash)                 // nolint: errcheck, gosec
+	hasher.Write(rightHash)                // nolint: errcheck, gosec
+
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,353 @@
+package blockchain
+
+import (
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMerkleData(test *testing.T) {
+	type args struct {
+		items []Data
+	}
+
+	for _, data := range []struct {
+		name     string
+		args     args
+		wantRoot string
+	}{
+		{
+			name: "without items",
+			args: args{
+				items: nil,
+			},
+			wantRoot: "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+		},
+		{
+			name: "with one item",
+			args: args{
+				items: []Data{NewData("one")},
+			},
+			wantRoot: "d0d7360ab79f58ab1e1e3fe64ad77e2ea0bc07e36b5f46ed2223edd9298df9e9",
+		},
+		{
+			name: "with an even quantity of items",
+			args: args{
+				items: []Data{NewData("one"), NewData("two")},
+			},
+			wantRoot: "4f55f619d9215235778b2b9f17d6f4915b16171214d152381293669764de722e",
+		},
+		{
+			name: "with an odd quantity of items",
+			args: args{
+				items: []Data{NewData("one"), NewData("two"), NewData("three")},
+			},
+			wantRoot: "5aac771c899ac292e74bf1afe2e6e302f8b55883b2d1bf197b46a6bea6dabca9",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := NewMerkleData(data.args.items)
+
+			assert.Equal(test, data.args.items, got.Items())
+			assert.Equal(test, data.wantRoot, got.Root())
+			assert.Equal(test, data.wantRoot, got.String())
+		})
+	}
+}
+
+func TestMerkleData_Equal(test *testing.T) {
+	type args struct {
+		anotherData Data
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want assert.BoolAssertionFunc
+	}{
+		{
+			name: "equal",
+			args: args{
+				anotherData: NewMerkleData([]Data{NewData("one"), NewData("two")}),
+			},
+			want: assert.True,
+		},
+		{
+			name: "not equal due to the type",
+			args: args{
+				anotherData: NewData("one"),
+			},
+			want: assert.False,
+		},
+		{
+			name: "not equal due to the item quantity",
+			args: args{
+				anotherData: NewMerkleData([]Data{NewData("one")}),
+			},
+			want: assert.False,
+		},
+		{
+			name: "not equal due to the items",
+			args: args{
+				anotherData: NewMerkleData([]Data{NewData("one"), NewData("three")}),
+			},
+			want: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			merkleData := NewMerkleData([]Data{NewData("one"), NewData("two")})
+			got := merkleData.Equal(data.args.anotherData)
+
+			data.want(test, got)
+		})
+	}
+}
+
+func TestMerkleData_Proof(test *testing.T) {
+	type args struct {
+		itemIndex int
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    MerkleProof
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with the last item",
+			args: args{
+				itemIndex: 2,
+			},
+			want: MerkleProof{
+				ItemIndex: 2,
+				ItemCount: 3,
+				Hashes: []string{
+					"4f55f619d9215235778b2b9f17d6f4915b16171214d152381293669764de722e",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with the middle item",
+			args: args{
+				itemIndex: 1,
+			},
+			want: MerkleProof{
+				ItemIndex: 1,
+				ItemCount: 3,
+				Hashes: []string{
+					"d0d7360ab79f58ab1e1e3fe64ad77e2ea0bc07e36b5f46ed2223edd9298df9e9",
+					"671f146c5e471e8a1a83a3c214ce4ba907b8f3a5888d14cc8cd3ce75bb12ef94",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				itemIndex: 3,
+			},
+			want:    MerkleProof{},
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			merkleData := NewMerkleData([]Data{
+				NewData("one"),
+				NewData("two"),
+				NewData("three"),
+			})
+			got, gotErr := merkleData.Proof(data.args.itemIndex)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestMerkleProof_Root(test *testing.T) {
+	for itemCount := 1; itemCount <= 9; itemCount++ {
+		var items []Data
+		for itemIndex := 0; itemIndex < itemCount; itemIndex++ {
+			items = append(items, NewData(itemIndex))
+		}
+
+		merkleData := NewMerkleData(items)
+		for itemIndex, item := range items {
+			proof, err := merkleData.Proof(itemIndex)
+			require.NoError(test, err)
+
+			gotRoot, gotErr := proof.Root(item)
+
+			assert.Equal(test, merkleData.Root(), gotRoot)
+			assert.NoError(test, gotErr)
+		}
+	}
+}
+
+func TestMerkleProof_Root_withError(test *testing.T) {
+	for _, data := range []struct {
+		name  string
+		proof MerkleProof
+	}{
+		{
+			name: "with the item index out of range",
+			proof: MerkleProof{
+				ItemIndex: 3,
+				ItemCount: 3,
+				Hashes: []string{
+					"4f55f619d9215235778b2b9f17d6f4915b16171214d152381293669764de722e",
+				},
+			},
+		},
+		{
+			name: "with extra hashes",
+			proof: MerkleProof{
+				ItemIndex: 2,
+				ItemCount: 3,
+				Hashes: []string{
+					"4f55f619d9215235778b2b9f17d6f4915b16171214d152381293669764de722e",
+					"4f55f619d9215235778b2b9f17d6f4915b16171214d152381293669764de722e",
+				},
+			},
+		},
+		{
+			name: "with lacking hashes",
+			proof: MerkleProof{
+				ItemIndex: 2,
+				ItemCount: 3,
+				Hashes:    nil,
+			},
+		},
+		{
+			name: "with an invalid hash",
+			proof: MerkleProof{
+				ItemIndex: 2,
+				ItemCount: 3,
+				Hashes:    []string{"invalid"},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotRoot, gotErr := data.proof.Root(NewData("three"))
+
+			assert.Empty(test, gotRoot)
+			assert.ErrorIs(test, gotErr, ErrInvalidMerkleProof)
+		})
+	}
+}
+
+func TestBlock_IsValidMerkleProof(test *testing.T) {
+	type args struct {
+		item    Data
+		proof   MerkleProof
+		proofer Proofer
+	}
+
+	for _, data := range []struct {
+		name string
+		args args
+		want assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				item: NewData("three"),
+				proof: MerkleProof{
+					ItemIndex: 2,
+					ItemCount: 3,
+					Hashes: []string{
+						"4f55f619d9215235778b2b9f17d6f4915b16171214d152381293669764de722e",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.
+						On("Validate", Block{
+							Timestamp: clock(),
+							Data: NewData(
+								"5aac771c899ac292e74bf1afe2e6e302f8b55883b2d1bf197b46a6bea6dabca9",
+							),
+							Hash:     "hash",
+							PrevHash: "previous hash",
+							Height:   1,
+						}).
+						Return(nil)
+
+					return proofer
+				}(),
+			},
+			want: assert.NoError,
+		},
+		{
+			name: "error with the proof",
+			args: args{
+				item: NewData("three"),
+				proof: MerkleProof{
+					ItemIndex: 2,
+					ItemCount: 3,
+					Hashes:    nil,
+				},
+				proofer: new(MockProofer),
+			},
+			want: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidMerkleProof)
+			},
+		},
+		{
+			name: "error with the proofer",
+			args: args{
+				item: NewData("four"),
+				proof: MerkleProof{
+					ItemIndex: 2,
+					ItemCount: 3,
+					Hashes: []string{
+						"4f55f619d9215235778b2b9f17d6f4915b16171214d152381293669764de722e",
+					},
+				},
+				proofer: func() Proofer {
+					proofer := new(MockProofer)
+					proofer.
+						On("Validate", Block{
+							Timestamp: clock(),
+							Data: NewData(
+								"9c855e2cb3d6da22cf14c6e761f610312649fe0726a126032744ec139e2cf4bf",
+							),
+							Hash:     "hash",
+							PrevHash: "previous hash",
+							Height:   1,
+						}).
+						Return(iotest.ErrTimeout)
+
+					return proofer
+				}(),
+			},
+			want: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout) &&
+					assert.ErrorIs(test, err, ErrInvalidMerkleProof)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			block := Block{
+				Timestamp: clock(),
+				Data:      nil,
+				Hash:      "hash",
+				PrevHash:  "previous hash",
+				Height:    1,
+			}
+			got := block.IsValidMerkleProof(
+				data.args.item,
+				data.args.proof,
+				data.args.proofer,
+			)
+
+			mock.AssertExpectationsForObjects(test, data.args.proofer)
+			data.want(test, got)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package proofers
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/sha3"
+)
+
+// HashAlgorithm ...
+type HashAlgorithm int
+
+// ...
+const (
+	SHA256 HashAlgorithm = iota
+	SHA512
+	SHA3_256
+	BLAKE2b256
+	BLAKE2b512
+)
+
+var hashAlgorithmNames = map[HashAlgorithm]string{
+	SHA256:     "sha256",
+	SHA512:     "sha512",
+	SHA3_256:   "sha3-256",
+	BLAKE2b256: "blake2b-256",
+	BLAKE2b512: "blake2b-512",
+}
+
+// ParseHashAlgorithm ...
+//
+// It returns the hash algorithm by its identifier.
+func ParseHashAlgorithm(name string) (HashAlgorithm, error) {
+	for algorithm, algorithmName := range hashAlgorithmNames {
+		if algorithmName == name {
+			return algorithm, nil
+		}
+	}
+
+	return 0, errors.Join(
+		fmt.Errorf("unknown hash algorithm %q", name),
+		ErrInvalidParameters,
+	)
+}
+
+// String ...
+//
+// It returns the identifier of the hash algorithm
+// that is embedded in the hash string.
+func (algorithm HashAlgorithm) String() string {
+	if name, isPresent := hashAlgorithmNames[algorithm]; isPresent {
+		return name
+	}
+
+	return fmt.Sprintf("HashAlgorithm(%d)", int(algorithm))
+}
+
+// Size ...
+//
+// It returns the digest size in bytes.
+func (algorithm HashAlgorithm) Size() (int, error) {
+	hash, err := algorithm.New()
+	if err != nil {
+		return 0, fmt.Errorf("unable to construct the hash: %w", err)
+	}
+
+	return hash.Size(), nil
+}
+
+// New ...
+func (algorithm HashAlgorithm) New() (hash.Hash, error) {
+	switch algorithm {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case SHA3_256:
+		return sha3.New256(), nil
+	case BLAKE2b256:
+		return blake2b.New256(nil)
+	case BLAKE2b512:
+		return blake2b.New512(nil)
+	default:
+		return nil, errors.Join(
+			fmt.Errorf("unknown hash algorithm %d", int(algorithm)),
+			ErrInvalidParameters,
+		)
+	}
+}
+
+func (algorithm HashAlgorithm) maximalTargetBit() (int, error) {
+	size, err := algorithm.Size()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the digest size: %w", err)
+	}
+
+	return size*8 - 1, nil
+}
@@ -0,0 +1,134 @@
+package proofers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseHashAlgorithm(test *testing.T) {
+	type args struct {
+		name string
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    HashAlgorithm
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "success/SHA-256",
+			args:    args{name: "sha256"},
+			want:    SHA256,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "success/SHA3-256",
+			args:    args{name: "sha3-256"},
+			want:    SHA3_256,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "success/BLAKE2b-512",
+			args:    args{name: "blake2b-512"},
+			want:    BLAKE2b512,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{name: "md5"},
+			want: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := ParseHashAlgorithm(data.args.name)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestHashAlgorithm_String(test *testing.T) {
+	for _, data := range []struct {
+		name      string
+		algorithm HashAlgorithm
+		want      string
+	}{
+		{
+			name:      "known algorithm",
+			algorithm: BLAKE2b256,
+			want:      "blake2b-256",
+		},
+		{
+			name:      "unknown algorithm",
+			algorithm: HashAlgorithm(23),
+			want:      "HashAlgorithm(23)",
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.algorithm.String()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestHashAlgorithm_Size(test *testing.T) {
+	for _, data := range []struct {
+		name      string
+		algorithm HashAlgorithm
+		want      int
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "success/SHA-256",
+			algorithm: SHA256,
+			want:      32,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "success/SHA-512",
+			algorithm: SHA512,
+			want:      64,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "success/SHA3-256",
+			algorithm: SHA3_256,
+			want:      32,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "success/BLAKE2b-256",
+			algorithm: BLAKE2b256,
+			want:      32,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "success/BLAKE2b-512",
+			algorithm: BLAKE2b512,
+			want:      64,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "error",
+			algorithm: HashAlgorithm(23),
+			want:      0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := data.algorithm.Size()
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
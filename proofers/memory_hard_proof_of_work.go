@@ -0,0 +1,309 @@
+package proofers
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+	powErrors "github.com/thewizardplusplus/go-pow/errors"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	memoryHardHashPrefix    = "argon2id"
+	memoryHardHashPartCount = 7
+	memoryHardBlockEncoding = blockchain.BinaryBlockEncodingV1
+	memoryHardKeyLength     = 32
+	memoryHardMaxTargetBit  = memoryHardKeyLength*8 - 1
+)
+
+// Argon2Params ...
+//
+// The memory is specified in KiB.
+type Argon2Params struct {
+	Time    uint32
+	Memory  uint32
+	Threads uint8
+}
+
+func (params Argon2Params) isWithin(
+	minParams Argon2Params,
+	maxParams Argon2Params,
+) bool {
+	return params.Time >= minParams.Time && params.Time <= maxParams.Time &&
+		params.Memory >= minParams.Memory && params.Memory <= maxParams.Memory &&
+		params.Threads >= minParams.Threads && params.Threads <= maxParams.Threads
+}
+
+// MemoryHardProofOfWork ...
+//
+// It's the proof of work based on the memory-hard Argon2id function
+// instead of a regular hash function. The key derived from the encoded block
+// (as a password) and the nonce (as a salt) should be less than two
+// to the power of the target bit.
+//
+// It stores the cost parameters, the target bit, the nonce and the key
+// in the hash: "argon2id:<time>:<memory>:<threads>:<target bit>:<nonce>:<key>".
+//
+// The validation recomputes the key with the parameters from the hash,
+// but only if they are within the bounds: the parameters used for the hashing
+// are the minimal ones, and the maximal ones equal to them by default.
+// It prevents both the cheap work and the exhaustion of the validator memory.
+// The target bit from the hash should also be not easier than the configured
+// one, otherwise the [ErrInsufficientDifficulty] error is returned.
+type MemoryHardProofOfWork struct {
+	TargetBit       int
+	Params          Argon2Params
+	MaxParams       mo.Option[Argon2Params]
+	MaxAttemptCount mo.Option[int]
+}
+
+// Hash ...
+//
+// Deprecated: Use [MemoryHardProofOfWork.HashEx] instead.
+func (proofer MemoryHardProofOfWork) Hash(block blockchain.Block) string {
+	hash, _ := proofer.HashEx(context.Background(), block)
+	return hash
+}
+
+// HashEx ...
+func (proofer MemoryHardProofOfWork) HashEx(
+	ctx context.Context,
+	block blockchain.Block,
+) (string, error) {
+	if proofer.TargetBit < 0 || proofer.TargetBit > memoryHardMaxTargetBit {
+		return "", errors.Join(
+			fmt.Errorf("the target bit %d is out of range", proofer.TargetBit),
+			ErrInvalidParameters,
+		)
+	}
+	if proofer.Params.Time == 0 || proofer.Params.Threads == 0 {
+		return "", errors.Join(
+			errors.New("the time and the threads should be positive"),
+			ErrInvalidParameters,
+		)
+	}
+
+	encodedBlock, err := block.EncodedData(memoryHardBlockEncoding)
+	if err != nil {
+		return "", fmt.Errorf(
+			"unable to encode the block: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	for nonce := uint64(0); ; nonce++ {
+		if maxAttemptCount, isPresent := proofer.MaxAttemptCount.Get(); isPresent &&
+			nonce >= uint64(maxAttemptCount) {
+			return "", errors.Join(
+				errors.New("the maximal attempt count is exceeded"),
+				powErrors.ErrTaskInterruption,
+			)
+		}
+		if err := ctx.Err(); err != nil {
+			return "", errors.Join(err, powErrors.ErrTaskInterruption)
+		}
+
+		key := deriveMemoryHardKey(encodedBlock, nonce, proofer.Params)
+		if !isMemoryHardKeyValid(key, proofer.TargetBit) {
+			continue
+		}
+
+		hashParts := []string{
+			memoryHardHashPrefix,
+			strconv.FormatUint(uint64(proofer.Params.Time), 10),
+			strconv.FormatUint(uint64(proofer.Params.Memory), 10),
+			strconv.FormatUint(uint64(proofer.Params.Threads), 10),
+			strconv.Itoa(proofer.TargetBit),
+			strconv.FormatUint(nonce, 10),
+			hex.EncodeToString(key),
+		}
+		return strings.Join(hashParts, hashPartSeparator), nil
+	}
+}
+
+// Validate ...
+func (proofer MemoryHardProofOfWork) Validate(block blockchain.Block) error {
+	hashParts, err := parseMemoryHardHash(block.Hash)
+	if err != nil {
+		return fmt.Errorf("unable to parse the hash: %w", err)
+	}
+
+	if hashParts.targetBit > proofer.TargetBit {
+		return errors.Join(
+			fmt.Errorf(
+				"the target bit %d is easier than the configured one %d",
+				hashParts.targetBit,
+				proofer.TargetBit,
+			),
+			ErrInsufficientDifficulty,
+		)
+	}
+
+	maxParams := proofer.MaxParams.OrElse(proofer.Params)
+	if !hashParts.params.isWithin(proofer.Params, maxParams) {
+		return errors.Join(
+			fmt.Errorf(
+				"the parameters %+v are out of the bounds %+v and %+v",
+				hashParts.params,
+				proofer.Params,
+				maxParams,
+			),
+			ErrInvalidParameters,
+		)
+	}
+
+	encodedBlock, err := block.EncodedData(memoryHardBlockEncoding)
+	if err != nil {
+		return fmt.Errorf(
+			"unable to encode the block: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	key := deriveMemoryHardKey(encodedBlock, hashParts.nonce, hashParts.params)
+	if !bytes.Equal(key, hashParts.key) {
+		return errors.Join(
+			errors.New("the key doesn't match the block"),
+			powErrors.ErrValidationFailure,
+		)
+	}
+	if !isMemoryHardKeyValid(key, hashParts.targetBit) {
+		return errors.Join(
+			errors.New("the key doesn't satisfy the target bit"),
+			powErrors.ErrValidationFailure,
+		)
+	}
+
+	return nil
+}
+
+// BlockEncoding ...
+//
+// It implements the [blockchain.EncodingProofer] interface, because the hash
+// doesn't mark the block encoding, which is always the same.
+func (proofer MemoryHardProofOfWork) BlockEncoding(
+	hash string,
+) blockchain.BlockEncodingVersion {
+	return memoryHardBlockEncoding
+}
+
+// Difficulty ...
+func (proofer MemoryHardProofOfWork) Difficulty(hash string) (int, error) {
+	hashParts, err := parseMemoryHardHash(hash)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse the hash: %w", err)
+	}
+
+	return memoryHardMaxTargetBit - hashParts.targetBit, nil
+}
+
+// Work ...
+//
+// It returns the expected attempt count to find the hash.
+func (proofer MemoryHardProofOfWork) Work(hash string) (*big.Int, error) {
+	difficulty, err := proofer.Difficulty(hash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to calculate the difficulty: %w", err)
+	}
+
+	work, err := expectedWork(difficulty)
+	if err != nil {
+		return nil, fmt.Errorf("unable to calculate the work: %w", err)
+	}
+
+	return work, nil
+}
+
+func deriveMemoryHardKey(
+	encodedBlock []byte,
+	nonce uint64,
+	params Argon2Params,
+) []byte {
+	salt := binary.BigEndian.AppendUint64(nil, nonce)
+	return argon2.IDKey(
+		encodedBlock,
+		salt,
+		params.Time,
+		params.Memory,
+		params.Threads,
+		memoryHardKeyLength,
+	)
+}
+
+func isMemoryHardKeyValid(key []byte, targetBit int) bool {
+	target := new(big.Int).Lsh(big.NewInt(1), uint(targetBit))
+	return new(big.Int).SetBytes(key).Cmp(target) < 0
+}
+
+type memoryHardHashParts struct {
+	params    Argon2Params
+	targetBit int
+	nonce     uint64
+	key       []byte
+}
+
+func parseMemoryHardHash(hash string) (memoryHardHashParts, error) {
+	rawHashParts := strings.Split(hash, hashPartSeparator)
+	if len(rawHashParts) != memoryHardHashPartCount ||
+		rawHashParts[0] != memoryHardHashPrefix {
+		return memoryHardHashParts{}, errors.Join(
+			errors.New("the hash has the invalid format"),
+			ErrInvalidParameters,
+		)
+	}
+
+	var rawNumbers [5]uint64
+	for index, bitSize := range []int{32, 32, 8, 16, 64} {
+		number, err := strconv.ParseUint(rawHashParts[index+1], 10, bitSize)
+		if err != nil {
+			return memoryHardHashParts{}, fmt.Errorf(
+				"unable to parse the hash part #%d: %w",
+				index+1,
+				errors.Join(err, ErrInvalidParameters),
+			)
+		}
+
+		rawNumbers[index] = number
+	}
+	if rawNumbers[0] == 0 || rawNumbers[2] == 0 {
+		return memoryHardHashParts{}, errors.Join(
+			errors.New("the time and the threads should be positive"),
+			ErrInvalidParameters,
+		)
+	}
+	if rawNumbers[3] > memoryHardMaxTargetBit {
+		return memoryHardHashParts{}, errors.Join(
+			fmt.Errorf("the target bit %d is out of range", rawNumbers[3]),
+			ErrInvalidParameters,
+		)
+	}
+
+	key, err := hex.DecodeString(rawHashParts[6])
+	if err != nil {
+		return memoryHardHashParts{}, fmt.Errorf(
+			"unable to decode the key: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	hashParts := memoryHardHashParts{
+		params: Argon2Params{
+			Time:    uint32(rawNumbers[0]),
+			Memory:  uint32(rawNumbers[1]),
+			Threads: uint8(rawNumbers[2]),
+		},
+		targetBit: int(rawNumbers[3]),
+		nonce:     rawNumbers[4],
+		key:       key,
+	}
+	return hashParts, nil
+}
@@ -0,0 +1,459 @@
+package proofers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/thewizardplusplus/go-blockchain"
+	powErrors "github.com/thewizardplusplus/go-pow/errors"
+)
+
+func TestMemoryHardProofOfWork_Hash(test *testing.T) {
+	proofer := MemoryHardProofOfWork{
+		TargetBit: 251,
+		Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+	}
+	hash := proofer.Hash(blockchain.Block{
+		Timestamp: clock(),
+		Data:      blockchain.NewData("hash"),
+		PrevHash:  "previous hash",
+	})
+
+	wantedHash := "argon2id:1:8:1:251:10:" +
+		"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037"
+	assert.Equal(test, wantedHash, hash)
+}
+
+func TestMemoryHardProofOfWork_HashEx(test *testing.T) {
+	type fields struct {
+		TargetBit       int
+		Params          Argon2Params
+		MaxAttemptCount mo.Option[int]
+	}
+	type args struct {
+		ctx   context.Context
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 2, Memory: 16, Threads: 1},
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "argon2id:" +
+				"2:" +
+				"16:" +
+				"1:" +
+				"251:" +
+				"5:" +
+				"03792dfd26087b63aca43bb3d43966b1eed6f34b907d97373e74bd059c71fd57",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid target bit",
+			fields: fields{
+				TargetBit: 1000,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/invalid parameters",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 0, Memory: 8, Threads: 1},
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/context is done",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				ctx: func() context.Context {
+					ctx, ctxCancel := context.WithCancel(context.Background())
+					ctxCancel()
+
+					return ctx
+				}(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, context.Canceled) &&
+					assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+			},
+		},
+		{
+			name: "error/maximal attempt count is exceeded",
+			fields: fields{
+				TargetBit:       251,
+				Params:          Argon2Params{Time: 1, Memory: 8, Threads: 1},
+				MaxAttemptCount: mo.Some(10),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := MemoryHardProofOfWork{
+				TargetBit:       data.fields.TargetBit,
+				Params:          data.fields.Params,
+				MaxAttemptCount: data.fields.MaxAttemptCount,
+			}
+			got, err := proofer.HashEx(data.args.ctx, data.args.block)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestMemoryHardProofOfWork_Validate(test *testing.T) {
+	type fields struct {
+		TargetBit int
+		Params    Argon2Params
+		MaxParams mo.Option[Argon2Params]
+	}
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/same parameters",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: "argon2id:1:8:1:251:10:" +
+						"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/parameters within the bounds",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+				MaxParams: mo.Some(Argon2Params{Time: 4, Memory: 64, Threads: 2}),
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: "argon2id:2:16:1:251:5:" +
+						"03792dfd26087b63aca43bb3d43966b1eed6f34b907d97373e74bd059c71fd57",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/unable to parse the hash",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: "v1:248:86:" +
+						"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/target bit is easier than the configured one",
+			fields: fields{
+				TargetBit: 250,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: "argon2id:1:8:1:251:10:" +
+						"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInsufficientDifficulty)
+			},
+		},
+		{
+			name: "error/forged easy target bit",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					// the key satisfies the forged target bit too
+					Hash: "argon2id:1:8:1:255:10:" +
+						"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInsufficientDifficulty)
+			},
+		},
+		{
+			name: "error/parameters are too cheap",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 2, Memory: 16, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: "argon2id:1:8:1:251:10:" +
+						"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/parameters are too expensive",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: "argon2id:2:16:1:251:5:" +
+						"03792dfd26087b63aca43bb3d43966b1eed6f34b907d97373e74bd059c71fd57",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/key doesn't match the block",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("another hash"),
+					Hash: "argon2id:1:8:1:251:10:" +
+						"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrValidationFailure)
+			},
+		},
+		{
+			name: "error/key doesn't satisfy the target bit",
+			fields: fields{
+				TargetBit: 251,
+				Params:    Argon2Params{Time: 1, Memory: 8, Threads: 1},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: "argon2id:1:8:1:248:10:" +
+						"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrValidationFailure)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := MemoryHardProofOfWork{
+				TargetBit: data.fields.TargetBit,
+				Params:    data.fields.Params,
+				MaxParams: data.fields.MaxParams,
+			}
+			err := proofer.Validate(data.args.block)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestMemoryHardProofOfWork_Difficulty(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantDifficulty int
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				hash: "argon2id:1:8:1:251:10:" +
+					"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+			},
+			wantDifficulty: 4,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "error/invalid quantity of the parts",
+			args: args{
+				hash: "argon2id:1:8:1:251:" +
+					"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/invalid prefix",
+			args: args{
+				hash: "scrypt:1:8:1:251:10:" +
+					"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to parse the parameters",
+			args: args{
+				hash: "argon2id:1:8:1000:251:10:" +
+					"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/invalid parameters",
+			args: args{
+				hash: "argon2id:0:8:1:251:10:" +
+					"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/invalid target bit",
+			args: args{
+				hash: "argon2id:1:8:1:256:10:" +
+					"04f9fd875603109c56e857ba0b4911dac28e19edd234e45a71eaf04741259037",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name:           "error/unable to decode the key",
+			args:           args{hash: "argon2id:1:8:1:251:10:invalid"},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := MemoryHardProofOfWork{}
+			gotDifficulty, gotErr := proofer.Difficulty(data.args.hash)
+
+			assert.Equal(test, data.wantDifficulty, gotDifficulty)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package proofers
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+const (
+	authorityHashPrefix    = "poa"
+	authorityHashPartCount = 4
+	authorityBlockEncoding = blockchain.BinaryBlockEncodingV1
+	inTurnDifficulty       = 2
+	outOfTurnDifficulty    = 1
+)
+
+// ...
+var (
+	ErrUnauthorizedSigner = errors.New("unauthorized signer")
+	ErrInvalidSignature   = errors.New("invalid signature")
+)
+
+// ProofOfAuthority ...
+//
+// It signs blocks with the private key of one of the validators
+// and stores the difficulty, the public key of the signer and the signature
+// in the hash: "poa:<difficulty>:<public key>:<signature>".
+//
+// The validators take turns by the block height, so the signer is in turn
+// if the block height modulo the validator count equals its index.
+// The difficulty of a block signed in turn is greater than of one signed
+// out of turn, so the fork choice prefers the chains signed in turn.
+// The block height is tied to the previous block by [blockchain.Block.IsValid],
+// so the turn can't be claimed by a forged height.
+//
+// The private key is required only for the hashing.
+type ProofOfAuthority struct {
+	Validators []ed25519.PublicKey
+	PrivateKey mo.Option[ed25519.PrivateKey]
+}
+
+// Hash ...
+//
+// Deprecated: Use [ProofOfAuthority.HashEx] instead.
+func (proofer ProofOfAuthority) Hash(block blockchain.Block) string {
+	hash, _ := proofer.HashEx(context.Background(), block)
+	return hash
+}
+
+// HashEx ...
+func (proofer ProofOfAuthority) HashEx(
+	ctx context.Context,
+	block blockchain.Block,
+) (string, error) {
+	privateKey, isPresent := proofer.PrivateKey.Get()
+	if !isPresent || len(privateKey) != ed25519.PrivateKeySize {
+		return "", errors.Join(
+			errors.New("the private key is absent or invalid"),
+			ErrInvalidParameters,
+		)
+	}
+
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	difficulty, err := proofer.signerDifficulty(block, publicKey)
+	if err != nil {
+		return "", fmt.Errorf(
+			"unable to get the signer difficulty: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	encodedBlock, err := block.EncodedData(authorityBlockEncoding)
+	if err != nil {
+		return "", fmt.Errorf(
+			"unable to encode the block: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	hashParts := []string{
+		authorityHashPrefix,
+		strconv.Itoa(difficulty),
+		hex.EncodeToString(publicKey),
+		hex.EncodeToString(ed25519.Sign(privateKey, encodedBlock)),
+	}
+	return strings.Join(hashParts, hashPartSeparator), nil
+}
+
+// Validate ...
+func (proofer ProofOfAuthority) Validate(block blockchain.Block) error {
+	hashParts, err := parseAuthorityHash(block.Hash)
+	if err != nil {
+		return fmt.Errorf("unable to parse the hash: %w", err)
+	}
+
+	difficulty, err := proofer.signerDifficulty(block, hashParts.publicKey)
+	if err != nil {
+		return fmt.Errorf("unable to get the signer difficulty: %w", err)
+	}
+	if difficulty != hashParts.difficulty {
+		return errors.Join(
+			fmt.Errorf(
+				"the difficulty %d differs from the expected one %d",
+				hashParts.difficulty,
+				difficulty,
+			),
+			ErrInvalidSignature,
+		)
+	}
+
+	encodedBlock, err := block.EncodedData(authorityBlockEncoding)
+	if err != nil {
+		return fmt.Errorf(
+			"unable to encode the block: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	if !ed25519.Verify(hashParts.publicKey, encodedBlock, hashParts.signature) {
+		return errors.Join(
+			errors.New("the signature doesn't match the block"),
+			ErrInvalidSignature,
+		)
+	}
+
+	return nil
+}
+
+// BlockEncoding ...
+//
+// It implements the [blockchain.EncodingProofer] interface, because the hash
+// doesn't mark the block encoding, which is always the same.
+func (proofer ProofOfAuthority) BlockEncoding(
+	hash string,
+) blockchain.BlockEncodingVersion {
+	return authorityBlockEncoding
+}
+
+// Difficulty ...
+func (proofer ProofOfAuthority) Difficulty(hash string) (int, error) {
+	hashParts, err := parseAuthorityHash(hash)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse the hash: %w", err)
+	}
+
+	return hashParts.difficulty, nil
+}
+
+func (proofer ProofOfAuthority) signerDifficulty(
+	block blockchain.Block,
+	publicKey ed25519.PublicKey,
+) (int, error) {
+	signerIndex := slices.IndexFunc(
+		proofer.Validators,
+		func(validator ed25519.PublicKey) bool {
+			return bytes.Equal(validator, publicKey)
+		},
+	)
+	if signerIndex == -1 {
+		return 0, errors.Join(
+			errors.New("the signer isn't in the validator set"),
+			ErrUnauthorizedSigner,
+		)
+	}
+
+	if block.Height%len(proofer.Validators) != signerIndex {
+		return outOfTurnDifficulty, nil
+	}
+
+	return inTurnDifficulty, nil
+}
+
+type authorityHashParts struct {
+	difficulty int
+	publicKey  ed25519.PublicKey
+	signature  []byte
+}
+
+func parseAuthorityHash(hash string) (authorityHashParts, error) {
+	rawHashParts := strings.Split(hash, hashPartSeparator)
+	if len(rawHashParts) != authorityHashPartCount ||
+		rawHashParts[0] != authorityHashPrefix {
+		return authorityHashParts{}, errors.Join(
+			errors.New("the hash has the invalid format"),
+			ErrInvalidParameters,
+		)
+	}
+
+	difficulty, err := strconv.Atoi(rawHashParts[1])
+	if err != nil {
+		return authorityHashParts{}, fmt.Errorf(
+			"unable to parse the difficulty: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+	if difficulty != inTurnDifficulty && difficulty != outOfTurnDifficulty {
+		return authorityHashParts{}, errors.Join(
+			fmt.Errorf("the difficulty %d is invalid", difficulty),
+			ErrInvalidParameters,
+		)
+	}
+
+	publicKey, err := hex.DecodeString(rawHashParts[2])
+	if err != nil {
+		return authorityHashParts{}, fmt.Errorf(
+			"unable to decode the public key: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+	if len(publicKey) != ed25519.PublicKeySize {
+		return authorityHashParts{}, errors.Join(
+			errors.New("the public key has the invalid size"),
+			ErrInvalidParameters,
+		)
+	}
+
+	signature, err := hex.DecodeString(rawHashParts[3])
+	if err != nil {
+		return authorityHashParts{}, fmt.Errorf(
+			"unable to decode the signature: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	hashParts := authorityHashParts{
+		difficulty: difficulty,
+		publicKey:  publicKey,
+		signature:  signature,
+	}
+	return hashParts, nil
+}
@@ -0,0 +1,475 @@
+package proofers
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestProofOfAuthority_Hash(test *testing.T) {
+	block := blockchain.Block{
+		Timestamp: clock(),
+		Data:      blockchain.NewData("hash"),
+		PrevHash:  "previous hash",
+	}
+
+	proofer := ProofOfAuthority{
+		Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+		PrivateKey: mo.Some(testPrivateKey(1)),
+	}
+	hash := proofer.Hash(block)
+
+	wantedHash := makeAuthorityHash(2, testPrivateKey(1), block)
+	assert.Equal(test, wantedHash, hash)
+}
+
+func TestProofOfAuthority_HashEx(test *testing.T) {
+	type fields struct {
+		Validators []ed25519.PublicKey
+		PrivateKey mo.Option[ed25519.PrivateKey]
+	}
+	type args struct {
+		ctx   context.Context
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		want    string
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/in turn",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+				PrivateKey: mo.Some(testPrivateKey(2)),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+					Height:    3,
+				},
+			},
+			want: makeAuthorityHash(2, testPrivateKey(2), blockchain.Block{
+				Timestamp: clock(),
+				Data:      blockchain.NewData("hash"),
+				PrevHash:  "previous hash",
+				Height:    3,
+			}),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/out of turn",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+				PrivateKey: mo.Some(testPrivateKey(2)),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+					Height:    4,
+				},
+			},
+			want: makeAuthorityHash(1, testPrivateKey(2), blockchain.Block{
+				Timestamp: clock(),
+				Data:      blockchain.NewData("hash"),
+				PrevHash:  "previous hash",
+				Height:    4,
+			}),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/private key is absent",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+				PrivateKey: mo.None[ed25519.PrivateKey](),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/signer is not a validator",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+				PrivateKey: mo.Some(testPrivateKey(3)),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnauthorizedSigner) &&
+					assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := ProofOfAuthority{
+				Validators: data.fields.Validators,
+				PrivateKey: data.fields.PrivateKey,
+			}
+			got, err := proofer.HashEx(data.args.ctx, data.args.block)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestProofOfAuthority_Validate(test *testing.T) {
+	type fields struct {
+		Validators []ed25519.PublicKey
+	}
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: makeAuthorityHash(2, testPrivateKey(2), blockchain.Block{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("hash"),
+						PrevHash:  "previous hash",
+						Height:    1,
+					}),
+					PrevHash: "previous hash",
+					Height:   1,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/unable to parse the hash",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash:      "v1:248:86:00",
+					PrevHash:  "previous hash",
+					Height:    1,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/signer is not a validator",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: makeAuthorityHash(1, testPrivateKey(3), blockchain.Block{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("hash"),
+						PrevHash:  "previous hash",
+						Height:    1,
+					}),
+					PrevHash: "previous hash",
+					Height:   1,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnauthorizedSigner)
+			},
+		},
+		{
+			name: "error/unexpected difficulty",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					Hash: makeAuthorityHash(2, testPrivateKey(1), blockchain.Block{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("hash"),
+						PrevHash:  "previous hash",
+						Height:    1,
+					}),
+					PrevHash: "previous hash",
+					Height:   1,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "error/signature doesn't match the block",
+			fields: fields{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("another hash"),
+					Hash: makeAuthorityHash(2, testPrivateKey(2), blockchain.Block{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("hash"),
+						PrevHash:  "previous hash",
+						Height:    1,
+					}),
+					PrevHash: "previous hash",
+					Height:   1,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := ProofOfAuthority{Validators: data.fields.Validators}
+			err := proofer.Validate(data.args.block)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestProofOfAuthority_Difficulty(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantDifficulty int
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/in turn",
+			args: args{
+				hash: "poa:2:" + strings.Repeat("00", ed25519.PublicKeySize) + ":00",
+			},
+			wantDifficulty: 2,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success/out of turn",
+			args: args{
+				hash: "poa:1:" + strings.Repeat("00", ed25519.PublicKeySize) + ":00",
+			},
+			wantDifficulty: 1,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "error/invalid quantity of the parts",
+			args: args{
+				hash: "poa:2:" + strings.Repeat("00", ed25519.PublicKeySize),
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/invalid prefix",
+			args: args{
+				hash: "pow:2:" + strings.Repeat("00", ed25519.PublicKeySize) + ":00",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to parse the difficulty",
+			args: args{
+				hash: "poa:invalid:" +
+					strings.Repeat("00", ed25519.PublicKeySize) +
+					":00",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/invalid difficulty",
+			args: args{
+				hash: "poa:23:" + strings.Repeat("00", ed25519.PublicKeySize) + ":00",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to decode the public key",
+			args: args{
+				hash: "poa:2:invalid:00",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/invalid size of the public key",
+			args: args{
+				hash: "poa:2:00:00",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to decode the signature",
+			args: args{
+				hash: "poa:2:" +
+					strings.Repeat("00", ed25519.PublicKeySize) +
+					":invalid",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := ProofOfAuthority{}
+			gotDifficulty, gotErr := proofer.Difficulty(data.args.hash)
+
+			assert.Equal(test, data.wantDifficulty, gotDifficulty)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestProofOfAuthority_withBlockValidation(test *testing.T) {
+	prevBlock := blockchain.Block{
+		Timestamp: clock(),
+		Data:      blockchain.NewData("previous data"),
+		Hash:      "previous hash",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+
+	type args struct {
+		height int
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantDifficulty int
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name:           "success/out of turn",
+			args:           args{height: 2},
+			wantDifficulty: outOfTurnDifficulty,
+			wantErr:        assert.NoError,
+		},
+		{
+			name:           "error/in turn by a forged height",
+			args:           args{height: 3},
+			wantDifficulty: inTurnDifficulty,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrInvalidHeight)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := ProofOfAuthority{
+				Validators: []ed25519.PublicKey{testPublicKey(1), testPublicKey(2)},
+				PrivateKey: mo.Some(testPrivateKey(2)),
+			}
+
+			block := blockchain.Block{
+				Timestamp: clock().Add(time.Hour),
+				Data:      blockchain.NewData("data"),
+				PrevHash:  "previous hash",
+				Height:    data.args.height,
+			}
+
+			var err error
+			block.Hash, err = proofer.HashEx(context.Background(), block)
+			require.NoError(test, err)
+
+			gotDifficulty, err := proofer.Difficulty(block.Hash)
+			require.NoError(test, err)
+
+			gotErr := block.IsValid(&prevBlock, proofer)
+
+			assert.Equal(test, data.wantDifficulty, gotDifficulty)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func testPrivateKey(seed byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+}
+
+func testPublicKey(seed byte) ed25519.PublicKey {
+	return testPrivateKey(seed).Public().(ed25519.PublicKey)
+}
+
+func makeAuthorityHash(
+	difficulty int,
+	privateKey ed25519.PrivateKey,
+	block blockchain.Block,
+) string {
+	return "poa:" +
+		strconv.Itoa(difficulty) + ":" +
+		hex.EncodeToString(privateKey.Public().(ed25519.PublicKey)) + ":" +
+		hex.EncodeToString(ed25519.Sign(privateKey, block.CanonicalData()))
+}
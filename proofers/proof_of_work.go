@@ -2,12 +2,13 @@ package proofers
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/samber/mo"
 	"github.com/thewizardplusplus/go-blockchain"
@@ -17,20 +18,63 @@ import (
 )
 
 const (
-	hashPartSeparator = ":"
-	hashPartCount     = 3
-	maximalTargetBit  = sha256.Size*8 - 1
+	hashPartSeparator         = ":"
+	hashPartCount             = 3
+	hashEncodingVersionPrefix = "v"
+	nonceBatchSize            = 1 << 12
 )
 
-var ErrInvalidParameters = errors.New("invalid parameters")
+// ...
+var (
+	ErrInvalidParameters      = errors.New("invalid parameters")
+	ErrInsufficientDifficulty = errors.New("insufficient difficulty")
+)
 
 // ProofOfWork ...
+//
+// By default, it hashes blocks in the latest block encoding and adds
+// its version to the hash. The hashes without a version correspond
+// to the legacy block encoding and are still accepted by the validation.
+// If the minimal block encoding is specified, the validation and
+// the difficulty calculation reject the hashes in the older encodings
+// (including the legacy one) with the
+// [blockchain.ErrUnsupportedBlockEncoding] error.
+//
+// If the worker count is greater than one, the nonce space is split
+// into batches that are searched by the specified quantity of goroutines.
+// In this case, the maximal attempt count is a budget shared by all
+// the workers, and the found nonce may differ from the sequential search.
+//
+// If the target bit schedule is specified, it overrides the target bit
+// during the hashing, and the validation rejects the blocks with the target bit
+// easier than the scheduled one with the [ErrInsufficientDifficulty] error.
+//
+// By default, it uses the SHA-256 hash algorithm. Other algorithms
+// are identified in the hash, so the validation doesn't depend
+// on the configured one. The bounds of the target bit are derived
+// from the digest size of the algorithm.
+//
+// The validation also rejects the blocks with the difficulty less than
+// the minimal one or not accepted by the difficulty policy
+// with the same error. By default, any difficulty is accepted.
 type ProofOfWork struct {
 	TargetBit                int
 	MaxAttemptCount          mo.Option[int]
 	RandomInitialNonceParams mo.Option[powValueTypes.RandomNonceParams]
+	BlockEncoding            mo.Option[blockchain.BlockEncodingVersion]
+	MinBlockEncoding         mo.Option[blockchain.BlockEncodingVersion]
+	WorkerCount              mo.Option[int]
+	TargetBitSchedule        mo.Option[TargetBitSchedule]
+	MinDifficulty            mo.Option[int]
+	DifficultyPolicy         mo.Option[DifficultyPolicy]
+	HashAlgorithm            mo.Option[HashAlgorithm]
 }
 
+// DifficultyPolicy ...
+//
+// It returns an error if the difficulty of the block is insufficient.
+type DifficultyPolicy func(block blockchain.Block, difficulty int) error
+
 // Hash ...
 //
 // Deprecated: Use [ProofOfWork.HashEx] instead.
@@ -44,7 +88,32 @@ func (proofer ProofOfWork) HashEx(
 	ctx context.Context,
 	block blockchain.Block,
 ) (string, error) {
-	targetBitIndex, err := powValueTypes.NewTargetBitIndex(proofer.TargetBit)
+	targetBit := proofer.TargetBit
+	if schedule, isPresent := proofer.TargetBitSchedule.Get(); isPresent {
+		var err error
+		targetBit, err = schedule.TargetBitEx(block, nil)
+		if err != nil {
+			return "", fmt.Errorf("unable to schedule the target bit: %w", err)
+		}
+	}
+
+	hashAlgorithm := proofer.HashAlgorithm.OrElse(SHA256)
+	maximalTargetBit, err := hashAlgorithm.maximalTargetBit()
+	if err != nil {
+		return "", fmt.Errorf("unable to get the maximal target bit: %w", err)
+	}
+	if targetBit > maximalTargetBit {
+		return "", errors.Join(
+			fmt.Errorf(
+				"the target bit %d exceeds the maximal one %d",
+				targetBit,
+				maximalTargetBit,
+			),
+			ErrInvalidParameters,
+		)
+	}
+
+	targetBitIndex, err := powValueTypes.NewTargetBitIndex(targetBit)
 	if err != nil {
 		return "", fmt.Errorf(
 			"unable to construct the target bit index: %w",
@@ -52,15 +121,42 @@ func (proofer ProofOfWork) HashEx(
 		)
 	}
 
-	challenge, err := buildChallenge(targetBitIndex, block)
-	if err != nil {
-		return "", fmt.Errorf("unable to build the challenge: %w", err)
+	workerCount := proofer.WorkerCount.OrElse(1)
+	if workerCount <= 0 {
+		return "", errors.Join(
+			errors.New("the worker count should be positive"),
+			ErrInvalidParameters,
+		)
 	}
 
-	solution, err := challenge.Solve(ctx, pow.SolveParams{
-		MaxAttemptCount:          proofer.MaxAttemptCount,
-		RandomInitialNonceParams: proofer.RandomInitialNonceParams,
-	})
+	blockEncoding := proofer.BlockEncoding.OrElse(blockchain.LatestBlockEncoding)
+	var solution pow.Solution
+	if workerCount == 1 {
+		var challenge pow.Challenge
+		challenge, err =
+			buildChallenge(hashAlgorithm, targetBitIndex, blockEncoding, block)
+		if err != nil {
+			return "", fmt.Errorf("unable to build the challenge: %w", err)
+		}
+
+		solution, err = challenge.Solve(ctx, pow.SolveParams{
+			MaxAttemptCount:          proofer.MaxAttemptCount,
+			RandomInitialNonceParams: proofer.RandomInitialNonceParams,
+		})
+	} else {
+		solution, err = proofer.solveConcurrently(
+			ctx,
+			workerCount,
+			func() (pow.Challenge, error) {
+				return buildChallenge(
+					hashAlgorithm,
+					targetBitIndex,
+					blockEncoding,
+					block,
+				)
+			},
+		)
+	}
 	if err != nil {
 		if !errors.Is(err, powErrors.ErrIO) &&
 			!errors.Is(err, powErrors.ErrTaskInterruption) {
@@ -76,21 +172,90 @@ func (proofer ProofOfWork) HashEx(
 	}
 
 	hashParts := []string{
-		strconv.Itoa(proofer.TargetBit),
+		strconv.Itoa(targetBit),
 		solution.Nonce().ToString(),
 		hex.EncodeToString(hashSum.ToBytes()),
 	}
+	if hashAlgorithm != SHA256 {
+		hashParts = append([]string{hashAlgorithm.String()}, hashParts...)
+	}
+	if blockEncoding != blockchain.LegacyBlockEncoding {
+		hashParts = append(
+			[]string{hashEncodingVersionPrefix + strconv.Itoa(int(blockEncoding))},
+			hashParts...,
+		)
+	}
 	return strings.Join(hashParts, hashPartSeparator), nil
 }
 
 // Validate ...
 func (proofer ProofOfWork) Validate(block blockchain.Block) error {
-	hashParts, err := parseHash(block.Hash)
+	return proofer.ValidateWithAncestry(block, nil)
+}
+
+// ValidateWithAncestry ...
+//
+// It's similar to the [ProofOfWork.Validate] method, but the target bit
+// schedule takes the previous blocks from the ancestry first.
+func (proofer ProofOfWork) ValidateWithAncestry(
+	block blockchain.Block,
+	ancestry blockchain.BlockGroup,
+) error {
+	hashParts, err := proofer.parseHash(block.Hash)
 	if err != nil {
 		return fmt.Errorf("unable to parse the hash: %w", err)
 	}
 
-	challenge, err := buildChallenge(hashParts.targetBitIndex, block)
+	difficulty, err := hashParts.difficulty()
+	if err != nil {
+		return fmt.Errorf("unable to get the difficulty: %w", err)
+	}
+	if minDifficulty, isPresent := proofer.MinDifficulty.Get(); isPresent &&
+		difficulty < minDifficulty {
+		return errors.Join(
+			fmt.Errorf(
+				"the difficulty %d is less than the minimal one %d",
+				difficulty,
+				minDifficulty,
+			),
+			ErrInsufficientDifficulty,
+		)
+	}
+
+	if policy, isPresent := proofer.DifficultyPolicy.Get(); isPresent {
+		if err := policy(block, difficulty); err != nil {
+			return fmt.Errorf(
+				"the difficulty isn't accepted by the policy: %w",
+				errors.Join(err, ErrInsufficientDifficulty),
+			)
+		}
+	}
+
+	if schedule, isPresent := proofer.TargetBitSchedule.Get(); isPresent {
+		scheduledTargetBit, err := schedule.TargetBitEx(block, ancestry)
+		if err != nil {
+			return fmt.Errorf("unable to schedule the target bit: %w", err)
+		}
+
+		if targetBit := hashParts.targetBitIndex.ToInt(); targetBit >
+			scheduledTargetBit {
+			return errors.Join(
+				fmt.Errorf(
+					"the target bit %d is easier than the scheduled one %d",
+					targetBit,
+					scheduledTargetBit,
+				),
+				ErrInsufficientDifficulty,
+			)
+		}
+	}
+
+	challenge, err := buildChallenge(
+		hashParts.hashAlgorithm,
+		hashParts.targetBitIndex,
+		hashParts.blockEncoding,
+		block,
+	)
 	if err != nil {
 		return fmt.Errorf("unable to build the challenge: %w", err)
 	}
@@ -116,23 +281,210 @@ func (proofer ProofOfWork) Validate(block blockchain.Block) error {
 
 // Difficulty ...
 func (proofer ProofOfWork) Difficulty(hash string) (int, error) {
-	hashParts, err := parseHash(hash)
+	hashParts, err := proofer.parseHash(hash)
 	if err != nil {
 		return 0, fmt.Errorf("unable to parse the hash: %w", err)
 	}
 
-	difficulty := maximalTargetBit - hashParts.targetBitIndex.ToInt()
+	difficulty, err := hashParts.difficulty()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the difficulty: %w", err)
+	}
+
 	return difficulty, nil
 }
 
+// Work ...
+//
+// It returns the expected attempt count to find the hash.
+func (proofer ProofOfWork) Work(hash string) (*big.Int, error) {
+	difficulty, err := proofer.Difficulty(hash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to calculate the difficulty: %w", err)
+	}
+
+	work, err := expectedWork(difficulty)
+	if err != nil {
+		return nil, fmt.Errorf("unable to calculate the work: %w", err)
+	}
+
+	return work, nil
+}
+
+func (proofer ProofOfWork) solveConcurrently(
+	ctx context.Context,
+	workerCount int,
+	challengeBuilder func() (pow.Challenge, error),
+) (pow.Solution, error) {
+	initialNonce := big.NewInt(0)
+	if params, isPresent := proofer.RandomInitialNonceParams.Get(); isPresent {
+		nonce, err := powValueTypes.NewRandomNonce(params)
+		if err != nil {
+			if params.MaxRawValue.Cmp(params.MinRawValue) > 0 {
+				err = errors.Join(err, powErrors.ErrIO)
+			}
+
+			return pow.Solution{}, fmt.Errorf(
+				"unable to generate the initial nonce: %w",
+				err,
+			)
+		}
+
+		initialNonce = nonce.ToBigInt()
+	}
+
+	ctx, ctxCancel := context.WithCancel(ctx)
+	defer ctxCancel()
+
+	batcher := &nonceBatcher{
+		nextNonce:             initialNonce,
+		remainingAttemptCount: proofer.MaxAttemptCount,
+	}
+
+	var (
+		resultOnce    sync.Once
+		result        pow.Solution
+		resultErr     error
+		isResultFound bool
+	)
+	setResult := func(solution pow.Solution, err error) {
+		resultOnce.Do(func() {
+			result, resultErr, isResultFound = solution, err, true
+		})
+
+		ctxCancel()
+	}
+
+	var waitGroup sync.WaitGroup
+	for range workerCount {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			// the challenge holds the hash, which can't be shared between goroutines
+			challenge, err := challengeBuilder()
+			if err != nil {
+				setResult(pow.Solution{}, fmt.Errorf(
+					"unable to build the challenge: %w",
+					err,
+				))
+
+				return
+			}
+
+			for {
+				firstNonce, attemptCount, isPresent := batcher.nextBatch()
+				if !isPresent {
+					return
+				}
+
+				solution, err := challenge.Solve(ctx, pow.SolveParams{
+					MaxAttemptCount:          mo.Some(attemptCount),
+					RandomInitialNonceParams: mo.Some(exactNonceParams(firstNonce)),
+				})
+				if err == nil || !errors.Is(err, powErrors.ErrTaskInterruption) ||
+					ctx.Err() != nil {
+					setResult(solution, err)
+					return
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	if !isResultFound {
+		return pow.Solution{}, errors.Join(
+			errors.New("the maximal attempt count is exceeded"),
+			powErrors.ErrTaskInterruption,
+		)
+	}
+
+	return result, resultErr
+}
+
+type nonceBatcher struct {
+	lock                  sync.Mutex
+	nextNonce             *big.Int
+	remainingAttemptCount mo.Option[int]
+}
+
+func (batcher *nonceBatcher) nextBatch() (*big.Int, int, bool) {
+	batcher.lock.Lock()
+	defer batcher.lock.Unlock()
+
+	attemptCount := nonceBatchSize
+	if remainingAttemptCount, isPresent :=
+		batcher.remainingAttemptCount.Get(); isPresent {
+		if remainingAttemptCount <= 0 {
+			return nil, 0, false
+		}
+
+		attemptCount = min(attemptCount, remainingAttemptCount)
+		batcher.remainingAttemptCount =
+			mo.Some(remainingAttemptCount - attemptCount)
+	}
+
+	firstNonce := new(big.Int).Set(batcher.nextNonce)
+	batcher.nextNonce.Add(batcher.nextNonce, big.NewInt(int64(attemptCount)))
+
+	return firstNonce, attemptCount, true
+}
+
+// the range of a single value makes the random initial nonce exact
+func exactNonceParams(nonce *big.Int) powValueTypes.RandomNonceParams {
+	return powValueTypes.RandomNonceParams{
+		RandomReader: zeroReader{},
+		MinRawValue:  nonce,
+		MaxRawValue:  new(big.Int).Add(nonce, big.NewInt(1)),
+	}
+}
+
+// the hash is less than 2^targetBit with the probability 2^-(difficulty+1)
+func expectedWork(difficulty int) (*big.Int, error) {
+	// the negative difficulty would wrap the shift around
+	if difficulty < 0 {
+		return nil, errors.Join(
+			fmt.Errorf("the difficulty %d is negative", difficulty),
+			ErrInvalidParameters,
+		)
+	}
+
+	return new(big.Int).Lsh(big.NewInt(1), uint(difficulty+1)), nil
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(buffer []byte) (int, error) {
+	clear(buffer)
+	return len(buffer), nil
+}
+
 func buildChallenge(
+	hashAlgorithm HashAlgorithm,
 	targetBitIndex powValueTypes.TargetBitIndex,
+	blockEncoding blockchain.BlockEncodingVersion,
 	block blockchain.Block,
 ) (pow.Challenge, error) {
+	encodedBlock, err := block.EncodedData(blockEncoding)
+	if err != nil {
+		return pow.Challenge{}, fmt.Errorf(
+			"unable to encode the block: %w",
+			errors.Join(err, ErrInvalidParameters),
+		)
+	}
+
+	hash, err := hashAlgorithm.New()
+	if err != nil {
+		return pow.Challenge{}, fmt.Errorf("unable to construct the hash: %w", err)
+	}
+
 	challenge, err := pow.NewChallengeBuilder().
 		SetTargetBitIndex(targetBitIndex).
-		SetSerializedPayload(powValueTypes.NewSerializedPayload(block.MergedData())).
-		SetHash(powValueTypes.NewHash(sha256.New())).
+		SetSerializedPayload(powValueTypes.NewSerializedPayload(
+			string(encodedBlock),
+		)).
+		SetHash(powValueTypes.NewHash(hash)).
 		SetHashDataLayout(powValueTypes.MustParseHashDataLayout(
 			"{{ .Challenge.SerializedPayload.ToString }}" +
 				"{{ .Nonce.ToString }}" +
@@ -149,13 +501,86 @@ func buildChallenge(
 	return challenge, nil
 }
 
+func (proofer ProofOfWork) parseHash(hash string) (hashParts, error) {
+	parts, err := parseHash(hash)
+	if err != nil {
+		return hashParts{}, err
+	}
+
+	if minBlockEncoding, isPresent := proofer.MinBlockEncoding.Get(); isPresent &&
+		parts.blockEncoding < minBlockEncoding {
+		return hashParts{}, errors.Join(
+			fmt.Errorf(
+				"the block encoding version %d is older than the minimal one %d",
+				parts.blockEncoding,
+				minBlockEncoding,
+			),
+			blockchain.ErrUnsupportedBlockEncoding,
+		)
+	}
+
+	return parts, nil
+}
+
 type hashParts struct {
+	blockEncoding  blockchain.BlockEncodingVersion
+	hashAlgorithm  HashAlgorithm
 	targetBitIndex powValueTypes.TargetBitIndex
 	nonce          powValueTypes.Nonce
 	hashSum        powValueTypes.HashSum
 }
 
+func (hashParts hashParts) difficulty() (int, error) {
+	maximalTargetBit, err := hashParts.hashAlgorithm.maximalTargetBit()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the maximal target bit: %w", err)
+	}
+
+	difficulty := maximalTargetBit - hashParts.targetBitIndex.ToInt()
+	if difficulty < 0 {
+		return 0, errors.Join(
+			fmt.Errorf("the difficulty %d is negative", difficulty),
+			ErrInvalidParameters,
+		)
+	}
+
+	return difficulty, nil
+}
+
 func parseHash(hash string) (hashParts, error) {
+	blockEncoding := blockchain.LegacyBlockEncoding
+	if strings.HasPrefix(hash, hashEncodingVersionPrefix) {
+		rawBlockEncoding, remainingHash, _ :=
+			strings.Cut(hash[len(hashEncodingVersionPrefix):], hashPartSeparator)
+
+		parsedBlockEncoding, err := strconv.Atoi(rawBlockEncoding)
+		if err != nil {
+			return hashParts{}, fmt.Errorf(
+				"unable to parse the block encoding version: %w",
+				errors.Join(err, ErrInvalidParameters),
+			)
+		}
+
+		blockEncoding = blockchain.BlockEncodingVersion(parsedBlockEncoding)
+		hash = remainingHash
+	}
+
+	// the hash algorithm is omitted for SHA-256, so the target bit goes first
+	hashAlgorithm := SHA256
+	rawHashAlgorithm, remainingHash, _ := strings.Cut(hash, hashPartSeparator)
+	if _, err := strconv.Atoi(rawHashAlgorithm); err != nil {
+		parsedHashAlgorithm, err := ParseHashAlgorithm(rawHashAlgorithm)
+		if err != nil {
+			return hashParts{}, fmt.Errorf(
+				"unable to parse the hash algorithm: %w",
+				err,
+			)
+		}
+
+		hashAlgorithm = parsedHashAlgorithm
+		hash = remainingHash
+	}
+
 	rawHashParts := strings.SplitN(hash, hashPartSeparator, hashPartCount)
 	if len(rawHashParts) != hashPartCount {
 		return hashParts{}, errors.Join(
@@ -180,6 +605,25 @@ func parseHash(hash string) (hashParts, error) {
 		)
 	}
 
+	// any hash sum is less than the target out of the digest size
+	maximalTargetBit, err := hashAlgorithm.maximalTargetBit()
+	if err != nil {
+		return hashParts{}, fmt.Errorf(
+			"unable to get the maximal target bit: %w",
+			err,
+		)
+	}
+	if rawTargetBitIndex > maximalTargetBit {
+		return hashParts{}, errors.Join(
+			fmt.Errorf(
+				"the target bit %d exceeds the maximal one %d",
+				rawTargetBitIndex,
+				maximalTargetBit,
+			),
+			ErrInvalidParameters,
+		)
+	}
+
 	nonce, err := powValueTypes.ParseNonce(rawHashParts[1])
 	if err != nil {
 		return hashParts{}, fmt.Errorf(
@@ -197,6 +641,8 @@ func parseHash(hash string) (hashParts, error) {
 	}
 
 	hashParts := hashParts{
+		blockEncoding:  blockEncoding,
+		hashAlgorithm:  hashAlgorithm,
 		targetBitIndex: targetBitIndex,
 		nonce:          nonce,
 		hashSum:        powValueTypes.NewHashSum(rawHashSum),
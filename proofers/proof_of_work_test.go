@@ -3,6 +3,8 @@ package proofers
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"math/big"
 	"testing"
 	"testing/iotest"
@@ -11,6 +13,7 @@ import (
 	"github.com/samber/mo"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/thewizardplusplus/go-blockchain"
 	powErrors "github.com/thewizardplusplus/go-pow/errors"
 	powValueTypes "github.com/thewizardplusplus/go-pow/value-types"
@@ -27,8 +30,8 @@ func TestProofOfWork_Hash(test *testing.T) {
 		PrevHash:  "previous hash",
 	})
 
-	wantedHash :=
-		"248:26:00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191"
+	wantedHash := "v1:248:86:" +
+		"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87"
 	mock.AssertExpectationsForObjects(test, data)
 	assert.Equal(test, wantedHash, hash)
 }
@@ -38,6 +41,8 @@ func TestProofOfWork_HashEx(test *testing.T) {
 		TargetBit                int
 		MaxAttemptCount          mo.Option[int]
 		RandomInitialNonceParams mo.Option[powValueTypes.RandomNonceParams]
+		BlockEncoding            mo.Option[blockchain.BlockEncodingVersion]
+		HashAlgorithm            mo.Option[HashAlgorithm]
 	}
 	type args struct {
 		ctx   context.Context
@@ -69,6 +74,31 @@ func TestProofOfWork_HashEx(test *testing.T) {
 					PrevHash: "previous hash",
 				},
 			},
+			want: "v1:" +
+				"248:" +
+				"86:" +
+				"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/legacy block encoding",
+			fields: fields{
+				TargetBit:     248,
+				BlockEncoding: mo.Some(blockchain.LegacyBlockEncoding),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
 			want: "248:" +
 				"26:" +
 				"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
@@ -97,9 +127,89 @@ func TestProofOfWork_HashEx(test *testing.T) {
 					PrevHash: "previous hash",
 				},
 			},
-			want: "248:" +
-				"617:" +
-				"00b68f5e223b82d5e3a4c1e48aca4db2b08f791866147ef33e3c5208937ae8f1",
+			want: "v1:" +
+				"248:" +
+				"629:" +
+				"0014ff22c3dcee1a7005b13f92d3a68371dcbb9cd30cd1659d48080a316aa4a8",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/SHA-512 hash algorithm",
+			fields: fields{
+				TargetBit:     504,
+				HashAlgorithm: mo.Some(SHA512),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
+			want: "v1:" +
+				"sha512:" +
+				"504:" +
+				"65:" +
+				"0021340c2c75f7bc26adf9dece529446181f8e25222d80b8586ebd6750d32b02" +
+				"3721d1bf1b79e49d2b493acd3aa10306543a53d623fa72361656e4fe001c800b",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/SHA3-256 hash algorithm",
+			fields: fields{
+				TargetBit:     248,
+				HashAlgorithm: mo.Some(SHA3_256),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
+			want: "v1:" +
+				"sha3-256:" +
+				"248:" +
+				"532:" +
+				"0000e567f61f9a24902185624264247de2314ed473fd9b3f9770e78094b6c0e7",
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/BLAKE2b-256 hash algorithm",
+			fields: fields{
+				TargetBit:     248,
+				HashAlgorithm: mo.Some(BLAKE2b256),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
+			want: "v1:" +
+				"blake2b-256:" +
+				"248:" +
+				"118:" +
+				"00b8774c08192e28987039b74197983e59abf2a1e0f8aab8533ea72e5d3a74c4",
 			wantErr: assert.NoError,
 		},
 		{
@@ -121,68 +231,625 @@ func TestProofOfWork_HashEx(test *testing.T) {
 			},
 		},
 		{
-			name: "error/unable to build the challenge",
+			name: "error/unable to encode the block",
+			fields: fields{
+				TargetBit:     248,
+				BlockEncoding: mo.Some(blockchain.BlockEncodingVersion(23)),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrUnsupportedBlockEncoding) &&
+					assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unknown hash algorithm",
+			fields: fields{
+				TargetBit:     248,
+				HashAlgorithm: mo.Some(HashAlgorithm(23)),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/target bit exceeds the maximal one",
+			fields: fields{
+				TargetBit: 1000,
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					PrevHash:  "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to solve the challenge/regular error",
+			fields: fields{
+				TargetBit: 248,
+				RandomInitialNonceParams: mo.Some(powValueTypes.RandomNonceParams{
+					RandomReader: bytes.NewReader([]byte("dummy")),
+					MinRawValue:  big.NewInt(142),
+					MaxRawValue:  big.NewInt(123),
+				}),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to solve the challenge/I/O error",
+			fields: fields{
+				TargetBit: 248,
+				RandomInitialNonceParams: mo.Some(powValueTypes.RandomNonceParams{
+					RandomReader: iotest.ErrReader(iotest.ErrTimeout),
+					MinRawValue:  big.NewInt(123),
+					MaxRawValue:  big.NewInt(142),
+				}),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrIO)
+			},
+		},
+		{
+			name: "error/unable to solve the challenge/context is done",
+			fields: fields{
+				TargetBit: 248,
+			},
+			args: args{
+				ctx: func() context.Context {
+					ctx, ctxCancel := context.WithCancel(context.Background())
+					ctxCancel()
+
+					return ctx
+				}(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+			},
+		},
+		{
+			name: "error/" +
+				"unable to solve the challenge/" +
+				"maximal attempt count is exceeded",
+			fields: fields{
+				TargetBit:       248,
+				MaxAttemptCount: mo.Some(23),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					PrevHash: "previous hash",
+				},
+			},
+			want: "",
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := ProofOfWork{
+				TargetBit:                data.fields.TargetBit,
+				MaxAttemptCount:          data.fields.MaxAttemptCount,
+				RandomInitialNonceParams: data.fields.RandomInitialNonceParams,
+				BlockEncoding:            data.fields.BlockEncoding,
+				HashAlgorithm:            data.fields.HashAlgorithm,
+			}
+			got, err := proofer.HashEx(data.args.ctx, data.args.block)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+
+			mock.AssertExpectationsForObjects(test, data.args.block.Data)
+		})
+	}
+}
+
+func TestProofOfWork_HashEx_concurrently(test *testing.T) {
+	type fields struct {
+		TargetBit                int
+		MaxAttemptCount          mo.Option[int]
+		RandomInitialNonceParams mo.Option[powValueTypes.RandomNonceParams]
+		BlockEncoding            mo.Option[blockchain.BlockEncodingVersion]
+		WorkerCount              mo.Option[int]
+	}
+	type args struct {
+		ctx   context.Context
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name      string
+		fields    fields
+		args      args
+		wantValid bool
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/without a maximal attempt count",
+			fields: fields{
+				TargetBit:   240,
+				WorkerCount: mo.Some(4),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: true,
+			wantErr:   assert.NoError,
+		},
+		{
+			name: "success/with a maximal attempt count",
+			fields: fields{
+				TargetBit:       248,
+				MaxAttemptCount: mo.Some(87),
+				WorkerCount:     mo.Some(4),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: true,
+			wantErr:   assert.NoError,
+		},
+		{
+			name: "success/random initial nonce",
+			fields: fields{
+				TargetBit: 248,
+				RandomInitialNonceParams: mo.Some(powValueTypes.RandomNonceParams{
+					RandomReader: bytes.NewReader([]byte("dummy")),
+					MinRawValue:  big.NewInt(123),
+					MaxRawValue:  big.NewInt(142),
+				}),
+				WorkerCount: mo.Some(4),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: true,
+			wantErr:   assert.NoError,
+		},
+		{
+			name: "error/invalid worker count",
+			fields: fields{
+				TargetBit:   248,
+				WorkerCount: mo.Some(0),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: false,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to generate the initial nonce/regular error",
+			fields: fields{
+				TargetBit: 248,
+				RandomInitialNonceParams: mo.Some(powValueTypes.RandomNonceParams{
+					RandomReader: bytes.NewReader([]byte("dummy")),
+					MinRawValue:  big.NewInt(142),
+					MaxRawValue:  big.NewInt(123),
+				}),
+				WorkerCount: mo.Some(4),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: false,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to generate the initial nonce/I/O error",
+			fields: fields{
+				TargetBit: 248,
+				RandomInitialNonceParams: mo.Some(powValueTypes.RandomNonceParams{
+					RandomReader: iotest.ErrReader(iotest.ErrTimeout),
+					MinRawValue:  big.NewInt(123),
+					MaxRawValue:  big.NewInt(142),
+				}),
+				WorkerCount: mo.Some(4),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: false,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrIO)
+			},
+		},
+		{
+			name: "error/unable to encode the block",
+			fields: fields{
+				TargetBit:     248,
+				BlockEncoding: mo.Some(blockchain.BlockEncodingVersion(23)),
+				WorkerCount:   mo.Some(4),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: false,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrUnsupportedBlockEncoding) &&
+					assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/context is done",
+			fields: fields{
+				TargetBit:   248,
+				WorkerCount: mo.Some(4),
+			},
+			args: args{
+				ctx: func() context.Context {
+					ctx, ctxCancel := context.WithCancel(context.Background())
+					ctxCancel()
+
+					return ctx
+				}(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: false,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+			},
+		},
+		{
+			name: "error/maximal attempt count is exceeded",
+			fields: fields{
+				TargetBit:       248,
+				MaxAttemptCount: mo.Some(86),
+				WorkerCount:     mo.Some(4),
+			},
+			args: args{
+				ctx: context.Background(),
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("hash"),
+					PrevHash:  "previous hash",
+				},
+			},
+			wantValid: false,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := ProofOfWork{
+				TargetBit:                data.fields.TargetBit,
+				MaxAttemptCount:          data.fields.MaxAttemptCount,
+				RandomInitialNonceParams: data.fields.RandomInitialNonceParams,
+				BlockEncoding:            data.fields.BlockEncoding,
+				WorkerCount:              data.fields.WorkerCount,
+			}
+			got, err := proofer.HashEx(data.args.ctx, data.args.block)
+
+			if data.wantValid {
+				block := data.args.block
+				block.Hash = got
+
+				assert.NoError(test, proofer.Validate(block))
+			} else {
+				assert.Empty(test, got)
+			}
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func BenchmarkProofOfWork_HashEx(benchmark *testing.B) {
+	for _, workerCount := range []int{1, 2, 4, 8} {
+		benchmark.Run(fmt.Sprintf("workers=%d", workerCount), func(
+			benchmark *testing.B,
+		) {
+			proofer := ProofOfWork{
+				TargetBit:   240,
+				WorkerCount: mo.Some(workerCount),
+			}
+			for index := 0; index < benchmark.N; index++ {
+				_, err := proofer.HashEx(context.Background(), blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData(fmt.Sprintf("hash #%d", index)),
+					PrevHash:  "previous hash",
+				})
+				if err != nil {
+					benchmark.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestProofOfWork_Validate(test *testing.T) {
+	type fields struct {
+		MinBlockEncoding mo.Option[blockchain.BlockEncodingVersion]
+		MinDifficulty    mo.Option[int]
+		DifficultyPolicy mo.Option[DifficultyPolicy]
+	}
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					Hash: "248:" +
+						"26:" +
+						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/binary block encoding",
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					Hash: "v1:" +
+						"248:" +
+						"86:" +
+						"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/sufficient minimal block encoding",
+			fields: fields{
+				MinBlockEncoding: mo.Some(blockchain.BinaryBlockEncodingV1),
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					Hash: "v1:" +
+						"248:" +
+						"86:" +
+						"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/sufficient minimal difficulty",
+			fields: fields{
+				MinDifficulty: mo.Some(7),
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					Hash: "248:" +
+						"26:" +
+						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/difficulty is accepted by the policy",
+			fields: fields{
+				DifficultyPolicy: mo.Some[DifficultyPolicy](func(
+					block blockchain.Block,
+					difficulty int,
+				) error {
+					if difficulty != 7 {
+						return errors.New("unexpected difficulty")
+					}
+
+					return nil
+				}),
+			},
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data: func() blockchain.Data {
+						data := new(MockData)
+						data.On("String").Return("hash")
+
+						return data
+					}(),
+					Hash: "248:" +
+						"26:" +
+						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/insufficient minimal difficulty",
 			fields: fields{
-				TargetBit: 1000,
+				MinDifficulty: mo.Some(8),
 			},
 			args: args{
-				ctx: context.Background(),
 				block: blockchain.Block{
 					Timestamp: clock(),
-					Data: func() blockchain.Data {
-						data := new(MockData)
-						data.On("String").Return("hash")
-
-						return data
-					}(),
+					Data:      new(MockData),
+					Hash: "248:" +
+						"26:" +
+						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
 					PrevHash: "previous hash",
 				},
 			},
-			want: "",
 			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
-				return assert.ErrorIs(test, err, ErrInvalidParameters)
+				return assert.ErrorIs(test, err, ErrInsufficientDifficulty)
 			},
 		},
 		{
-			name: "error/unable to solve the challenge/regular error",
+			name: "error/difficulty is rejected by the policy",
 			fields: fields{
-				TargetBit: 248,
-				RandomInitialNonceParams: mo.Some(powValueTypes.RandomNonceParams{
-					RandomReader: bytes.NewReader([]byte("dummy")),
-					MinRawValue:  big.NewInt(142),
-					MaxRawValue:  big.NewInt(123),
+				DifficultyPolicy: mo.Some[DifficultyPolicy](func(
+					block blockchain.Block,
+					difficulty int,
+				) error {
+					return iotest.ErrTimeout
 				}),
 			},
 			args: args{
-				ctx: context.Background(),
 				block: blockchain.Block{
 					Timestamp: clock(),
-					Data: func() blockchain.Data {
-						data := new(MockData)
-						data.On("String").Return("hash")
-
-						return data
-					}(),
+					Data:      new(MockData),
+					Hash: "248:" +
+						"26:" +
+						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
 					PrevHash: "previous hash",
 				},
 			},
-			want: "",
 			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
-				return assert.ErrorIs(test, err, ErrInvalidParameters)
+				return assert.ErrorIs(test, err, iotest.ErrTimeout) &&
+					assert.ErrorIs(test, err, ErrInsufficientDifficulty)
 			},
 		},
 		{
-			name: "error/unable to solve the challenge/I/O error",
-			fields: fields{
-				TargetBit: 248,
-				RandomInitialNonceParams: mo.Some(powValueTypes.RandomNonceParams{
-					RandomReader: iotest.ErrReader(iotest.ErrTimeout),
-					MinRawValue:  big.NewInt(123),
-					MaxRawValue:  big.NewInt(142),
-				}),
-			},
+			name: "success/SHA-512 hash algorithm",
 			args: args{
-				ctx: context.Background(),
 				block: blockchain.Block{
 					Timestamp: clock(),
 					Data: func() blockchain.Data {
@@ -191,113 +858,87 @@ func TestProofOfWork_HashEx(test *testing.T) {
 
 						return data
 					}(),
+					Hash: "v1:" +
+						"sha512:" +
+						"504:" +
+						"65:" +
+						"0021340c2c75f7bc26adf9dece529446181f8e25222d80b8586ebd6750d32b02" +
+						"3721d1bf1b79e49d2b493acd3aa10306543a53d623fa72361656e4fe001c800b",
+					PrevHash: "previous hash",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/unknown hash algorithm",
+			args: args{
+				block: blockchain.Block{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash: "v1:" +
+						"md5:" +
+						"120:" +
+						"86:" +
+						"00199f967ae02dd65f3d18a27cf783d4",
 					PrevHash: "previous hash",
 				},
 			},
-			want: "",
 			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
-				return assert.ErrorIs(test, err, powErrors.ErrIO)
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
 			},
 		},
 		{
-			name: "error/unable to solve the challenge/context is done",
-			fields: fields{
-				TargetBit: 248,
-			},
+			name: "error/unable to parse the block encoding version",
 			args: args{
-				ctx: func() context.Context {
-					ctx, ctxCancel := context.WithCancel(context.Background())
-					ctxCancel()
-
-					return ctx
-				}(),
 				block: blockchain.Block{
 					Timestamp: clock(),
-					Data: func() blockchain.Data {
-						data := new(MockData)
-						data.On("String").Return("hash")
-
-						return data
-					}(),
+					Data:      new(MockData),
+					Hash: "vinvalid:" +
+						"248:" +
+						"86:" +
+						"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
 					PrevHash: "previous hash",
 				},
 			},
-			want: "",
 			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
-				return assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
 			},
 		},
 		{
-			name: "error/" +
-				"unable to solve the challenge/" +
-				"maximal attempt count is exceeded",
+			name: "error/legacy block encoding is older than the minimal one",
 			fields: fields{
-				TargetBit:       248,
-				MaxAttemptCount: mo.Some(23),
+				MinBlockEncoding: mo.Some(blockchain.BinaryBlockEncodingV1),
 			},
 			args: args{
-				ctx: context.Background(),
 				block: blockchain.Block{
 					Timestamp: clock(),
-					Data: func() blockchain.Data {
-						data := new(MockData)
-						data.On("String").Return("hash")
-
-						return data
-					}(),
+					Data:      new(MockData),
+					Hash: "248:" +
+						"26:" +
+						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
 					PrevHash: "previous hash",
 				},
 			},
-			want: "",
 			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
-				return assert.ErrorIs(test, err, powErrors.ErrTaskInterruption)
+				return assert.ErrorIs(test, err, blockchain.ErrUnsupportedBlockEncoding)
 			},
 		},
-	} {
-		test.Run(data.name, func(test *testing.T) {
-			proofer := ProofOfWork{
-				TargetBit:                data.fields.TargetBit,
-				MaxAttemptCount:          data.fields.MaxAttemptCount,
-				RandomInitialNonceParams: data.fields.RandomInitialNonceParams,
-			}
-			got, err := proofer.HashEx(data.args.ctx, data.args.block)
-
-			assert.Equal(test, data.want, got)
-			data.wantErr(test, err)
-
-			mock.AssertExpectationsForObjects(test, data.args.block.Data)
-		})
-	}
-}
-
-func TestProofOfWork_Validate(test *testing.T) {
-	type args struct {
-		block blockchain.Block
-	}
-
-	for _, data := range []struct {
-		name    string
-		args    args
-		wantErr assert.ErrorAssertionFunc
-	}{
 		{
-			name: "success",
+			name: "error/unsupported block encoding version",
 			args: args{
 				block: blockchain.Block{
 					Timestamp: clock(),
-					Data: func() blockchain.Data {
-						data := new(MockData)
-						data.On("String").Return("hash")
-
-						return data
-					}(),
-					Hash: "248:" +
-						"26:" +
-						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+					Data:      new(MockData),
+					Hash: "v23:" +
+						"248:" +
+						"86:" +
+						"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
 					PrevHash: "previous hash",
 				},
 			},
-			wantErr: assert.NoError,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrUnsupportedBlockEncoding)
+			},
 		},
 		{
 			name: "error/the hash contains the invalid quantity of the parts",
@@ -376,17 +1017,12 @@ func TestProofOfWork_Validate(test *testing.T) {
 			},
 		},
 		{
-			name: "error/unable to build the challenge",
+			name: "error/target bit exceeds the maximal one",
 			args: args{
 				block: blockchain.Block{
 					Timestamp: clock(),
-					Data: func() blockchain.Data {
-						data := new(MockData)
-						data.On("String").Return("hash")
-
-						return data
-					}(),
-					Hash: "1000:" +
+					Data:      new(MockData),
+					Hash: "300:" +
 						"26:" +
 						"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
 					PrevHash: "previous hash",
@@ -438,7 +1074,12 @@ func TestProofOfWork_Validate(test *testing.T) {
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			err := (ProofOfWork{}).Validate(data.args.block)
+			proofer := ProofOfWork{
+				MinBlockEncoding: data.fields.MinBlockEncoding,
+				MinDifficulty:    data.fields.MinDifficulty,
+				DifficultyPolicy: data.fields.DifficultyPolicy,
+			}
+			err := proofer.Validate(data.args.block)
 
 			mock.AssertExpectationsForObjects(test, data.args.block.Data)
 			data.wantErr(test, err)
@@ -448,7 +1089,8 @@ func TestProofOfWork_Validate(test *testing.T) {
 
 func TestProofOfWork_Difficulty(test *testing.T) {
 	type fields struct {
-		TargetBit int
+		TargetBit        int
+		MinBlockEncoding mo.Option[blockchain.BlockEncodingVersion]
 	}
 	type args struct {
 		hash string
@@ -483,6 +1125,91 @@ func TestProofOfWork_Difficulty(test *testing.T) {
 			wantDifficulty: 3,
 			wantErr:        assert.NoError,
 		},
+		{
+			name:   "success with the block encoding version",
+			fields: fields{TargetBit: 23},
+			args: args{
+				hash: "v1:" +
+					"248:" +
+					"86:" +
+					"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
+			},
+			wantDifficulty: 7,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "success with the sufficient minimal block encoding",
+			fields: fields{
+				TargetBit:        23,
+				MinBlockEncoding: mo.Some(blockchain.BinaryBlockEncodingV1),
+			},
+			args: args{
+				hash: "v1:" +
+					"248:" +
+					"86:" +
+					"00199f967ae02dd65f3d18a27cf783d479b1022d26be502054a00297d04a4c87",
+			},
+			wantDifficulty: 7,
+			wantErr:        assert.NoError,
+		},
+		{
+			name:   "success with the SHA-512 hash algorithm",
+			fields: fields{TargetBit: 23},
+			args: args{
+				hash: "v1:" +
+					"sha512:" +
+					"504:" +
+					"65:" +
+					"0021340c2c75f7bc26adf9dece529446181f8e25222d80b8586ebd6750d32b02" +
+					"3721d1bf1b79e49d2b493acd3aa10306543a53d623fa72361656e4fe001c800b",
+			},
+			wantDifficulty: 7,
+			wantErr:        assert.NoError,
+		},
+		{
+			name: "error with the legacy block encoding older than the minimal one",
+			fields: fields{
+				TargetBit:        23,
+				MinBlockEncoding: mo.Some(blockchain.BinaryBlockEncodingV1),
+			},
+			args: args{
+				hash: "248:" +
+					"26:" +
+					"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrUnsupportedBlockEncoding)
+			},
+		},
+		{
+			name:   "error with the unknown hash algorithm",
+			fields: fields{TargetBit: 23},
+			args: args{
+				hash: "v1:" +
+					"md5:" +
+					"120:" +
+					"86:" +
+					"00199f967ae02dd65f3d18a27cf783d4",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name:   "error with the target bit exceeding the maximal one",
+			fields: fields{TargetBit: 23},
+			args: args{
+				hash: "300:" +
+					"26:" +
+					"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+			},
+			wantDifficulty: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
 		{
 			name:   "incorrect hash structure",
 			fields: fields{TargetBit: 23},
@@ -505,7 +1232,10 @@ func TestProofOfWork_Difficulty(test *testing.T) {
 		},
 	} {
 		test.Run(data.name, func(test *testing.T) {
-			proofer := ProofOfWork{TargetBit: data.fields.TargetBit}
+			proofer := ProofOfWork{
+				TargetBit:        data.fields.TargetBit,
+				MinBlockEncoding: data.fields.MinBlockEncoding,
+			}
 			gotDifficulty, gotErr := proofer.Difficulty(data.args.hash)
 
 			assert.Equal(test, data.wantDifficulty, gotDifficulty)
@@ -514,6 +1244,98 @@ func TestProofOfWork_Difficulty(test *testing.T) {
 	}
 }
 
+func TestProofOfWork_Work(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name     string
+		args     args
+		wantWork *big.Int
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				hash: "248:" +
+					"26:" +
+					"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+			},
+			wantWork: big.NewInt(256),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "error/target bit exceeds the maximal one",
+			args: args{
+				hash: "300:" +
+					"26:" +
+					"00c4c39529ced1cb3e32086b19b753831f6396c9fa79079bc93c1c76a6244191",
+			},
+			wantWork: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotWork, gotErr := ProofOfWork{}.Work(data.args.hash)
+
+			assert.Equal(test, data.wantWork, gotWork)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func Test_hashParts_difficulty(test *testing.T) {
+	targetBitIndex, err := powValueTypes.NewTargetBitIndex(300)
+	require.NoError(test, err)
+
+	hashParts := hashParts{
+		hashAlgorithm:  SHA256,
+		targetBitIndex: targetBitIndex,
+	}
+	gotDifficulty, gotErr := hashParts.difficulty()
+
+	assert.Zero(test, gotDifficulty)
+	assert.ErrorIs(test, gotErr, ErrInvalidParameters)
+}
+
+func Test_expectedWork(test *testing.T) {
+	type args struct {
+		difficulty int
+	}
+
+	for _, data := range []struct {
+		name     string
+		args     args
+		wantWork *big.Int
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "success",
+			args:     args{difficulty: 7},
+			wantWork: big.NewInt(256),
+			wantErr:  assert.NoError,
+		},
+		{
+			name:     "error",
+			args:     args{difficulty: -45},
+			wantWork: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			gotWork, gotErr := expectedWork(data.args.difficulty)
+
+			assert.Equal(test, data.wantWork, gotWork)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
 func clock() time.Time {
 	year, month, day := 2006, time.January, 2
 	hour, minute, second := 15, 4, 5
@@ -0,0 +1,265 @@
+package proofers
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+const defaultMaxTargetBitShift = 1
+
+// errUnexpectedBlock is returned by the lookup by a height if the found
+// blocks aren't the expected ones (e.g., they belong to another fork),
+// so the loader should be paged instead
+var errUnexpectedBlock = errors.New("unexpected block")
+
+// TargetBitSchedule ...
+//
+// It computes the target bit required for a block from the timestamps
+// of the previous blocks provided by the loader (from the last one backward).
+// If the loader implements the [blockchain.IndexedLoader] interface
+// and the height of the block is known, the previous blocks are looked up
+// by a height; otherwise, the loader is paged from the last block.
+// In both cases, the previous blocks should be linked by their hashes.
+//
+// The target bit of the previous block is shifted by the binary logarithm
+// of the ratio of the actual interval between the previous blocks
+// to the target one, so the blocks mined too fast make the next one harder.
+// The shift is limited by the maximal one (one bit by default).
+// While the history is shorter than the block count, the target bit
+// of the previous block is kept.
+type TargetBitSchedule struct {
+	Loader              blockchain.Loader
+	ChunkSize           int
+	InitialTargetBit    int
+	BlockCount          int
+	TargetBlockInterval time.Duration
+	MaxTargetBitShift   mo.Option[int]
+}
+
+// TargetBit ...
+//
+// It returns the target bit required for the block following the block
+// with the specified hash. The empty hash corresponds to the genesis block.
+func (schedule TargetBitSchedule) TargetBit(prevHash string) (int, error) {
+	return schedule.targetBit(prevHash, mo.None[int](), nil)
+}
+
+// TargetBitEx ...
+//
+// It returns the target bit required for the specified block. The previous
+// blocks are taken from the ancestry first (see [blockchain.AncestryProofer])
+// and then from the loader.
+func (schedule TargetBitSchedule) TargetBitEx(
+	block blockchain.Block,
+	ancestry blockchain.BlockGroup,
+) (int, error) {
+	return schedule.targetBit(block.PrevHash, mo.Some(block.Height-1), ancestry)
+}
+
+func (schedule TargetBitSchedule) targetBit(
+	prevHash string,
+	prevHeight mo.Option[int],
+	ancestry blockchain.BlockGroup,
+) (int, error) {
+	if schedule.ChunkSize <= 0 ||
+		schedule.BlockCount < 2 ||
+		schedule.TargetBlockInterval <= 0 {
+		return 0, errors.Join(
+			errors.New("the schedule parameters are invalid"),
+			ErrInvalidParameters,
+		)
+	}
+
+	if prevHash == "" {
+		return schedule.InitialTargetBit, nil
+	}
+
+	blocks, err := schedule.loadPrevBlocks(prevHash, prevHeight, ancestry)
+	if err != nil {
+		return 0, fmt.Errorf("unable to load the previous blocks: %w", err)
+	}
+
+	prevHashParts, err := parseHash(blocks[0].Hash)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse the previous hash: %w", err)
+	}
+
+	prevTargetBit := prevHashParts.targetBitIndex.ToInt()
+	if len(blocks) < schedule.BlockCount {
+		return prevTargetBit, nil
+	}
+
+	actualInterval := blocks[0].Timestamp.Sub(blocks[len(blocks)-1].Timestamp)
+	targetInterval := schedule.TargetBlockInterval * time.Duration(len(blocks)-1)
+
+	maxTargetBitShift :=
+		schedule.MaxTargetBitShift.OrElse(defaultMaxTargetBitShift)
+	targetBitShift := -maxTargetBitShift
+	if actualInterval > 0 {
+		targetBitShift = int(math.Round(
+			math.Log2(float64(actualInterval) / float64(targetInterval)),
+		))
+		targetBitShift =
+			max(min(targetBitShift, maxTargetBitShift), -maxTargetBitShift)
+	}
+
+	maximalTargetBit, err := prevHashParts.hashAlgorithm.maximalTargetBit()
+	if err != nil {
+		return 0, fmt.Errorf("unable to get the maximal target bit: %w", err)
+	}
+
+	return max(min(prevTargetBit+targetBitShift, maximalTargetBit), 0), nil
+}
+
+// it returns the block with the specified hash
+// and the blocks preceding it, from the last one backward
+func (schedule TargetBitSchedule) loadPrevBlocks(
+	prevHash string,
+	prevHeight mo.Option[int],
+	ancestry blockchain.BlockGroup,
+) (blockchain.BlockGroup, error) {
+	var blocks blockchain.BlockGroup
+	ancestryIndex := slices.IndexFunc(ancestry, func(block blockchain.Block) bool {
+		return block.Hash == prevHash
+	})
+	if ancestryIndex != -1 {
+		lastIndex := min(ancestryIndex+schedule.BlockCount, len(ancestry))
+		blocks = slices.Clone(ancestry[ancestryIndex:lastIndex])
+	}
+
+	nextHash, nextHeight := prevHash, prevHeight
+	if len(blocks) != 0 {
+		lastBlock := blocks[len(blocks)-1]
+		nextHash, nextHeight = lastBlock.PrevHash, mo.Some(lastBlock.Height-1)
+	}
+	if len(blocks) == schedule.BlockCount || nextHash == "" {
+		return blocks, nil
+	}
+
+	storedBlocks, err := schedule.loadStoredBlocks(
+		nextHash,
+		nextHeight,
+		schedule.BlockCount-len(blocks),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(blocks, storedBlocks...), nil
+}
+
+func (schedule TargetBitSchedule) loadStoredBlocks(
+	hash string,
+	height mo.Option[int],
+	count int,
+) (blockchain.BlockGroup, error) {
+	indexedLoader, isIndexed := schedule.Loader.(blockchain.IndexedLoader)
+	if height, isPresent := height.Get(); isIndexed && isPresent {
+		blocks, err := loadBlocksByHeight(indexedLoader, hash, height, count)
+		if err == nil {
+			return blocks, nil
+		}
+		if !errors.Is(err, errors.ErrUnsupported) &&
+			!errors.Is(err, errUnexpectedBlock) {
+			return nil, err
+		}
+	}
+
+	return schedule.loadBlocksByPaging(hash, count)
+}
+
+// it returns the errUnexpectedBlock error if the blocks looked up
+// by a height aren't linked with the specified hash
+func loadBlocksByHeight(
+	loader blockchain.IndexedLoader,
+	hash string,
+	height int,
+	count int,
+) (blockchain.BlockGroup, error) {
+	var blocks blockchain.BlockGroup
+	for ; len(blocks) < count && hash != ""; height-- {
+		if height < 0 {
+			return nil, errUnexpectedBlock
+		}
+
+		block, err := loader.LoadBlockByHeight(height)
+		if err != nil {
+			if errors.Is(err, blockchain.ErrNotFound) {
+				err = errors.Join(err, errUnexpectedBlock)
+			}
+
+			return nil, fmt.Errorf(
+				"unable to load the block with height %d: %w",
+				height,
+				err,
+			)
+		}
+		if block.Hash != hash {
+			return nil, errUnexpectedBlock
+		}
+
+		blocks = append(blocks, block)
+		hash = block.PrevHash
+	}
+
+	return blocks, nil
+}
+
+// the blocks following the one with the specified hash should be linked
+// with it, like the ones looked up by a height
+func (schedule TargetBitSchedule) loadBlocksByPaging(
+	hash string,
+	count int,
+) (blockchain.BlockGroup, error) {
+	var blocks blockchain.BlockGroup
+	var cursor interface{}
+	for len(blocks) < count && hash != "" {
+		chunk, nextCursor, err :=
+			schedule.Loader.LoadBlocks(cursor, schedule.ChunkSize)
+		if err != nil {
+			return nil, err
+		}
+		if len(chunk) == 0 {
+			break
+		}
+
+		for _, block := range chunk {
+			if block.Hash != hash {
+				if len(blocks) == 0 {
+					continue
+				}
+
+				return nil, errors.Join(
+					fmt.Errorf(
+						"the block %s isn't linked with the block %s",
+						blocks[len(blocks)-1].Hash,
+						block.Hash,
+					),
+					blockchain.ErrInvalidPrevHash,
+				)
+			}
+
+			blocks = append(blocks, block)
+			hash = block.PrevHash
+			if len(blocks) == count || hash == "" {
+				break
+			}
+		}
+
+		cursor = nextCursor
+	}
+	if len(blocks) == 0 {
+		return nil, errors.Join(
+			errors.New("the previous block isn't found"),
+			blockchain.ErrNotFound,
+		)
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,640 @@
+package proofers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+	"github.com/thewizardplusplus/go-blockchain/loading/loaders"
+	"github.com/thewizardplusplus/go-blockchain/storing"
+	"github.com/thewizardplusplus/go-blockchain/storing/storages"
+)
+
+func TestTargetBitSchedule_TargetBit(test *testing.T) {
+	type fields struct {
+		Loader              blockchain.Loader
+		ChunkSize           int
+		InitialTargetBit    int
+		BlockCount          int
+		TargetBlockInterval time.Duration
+		MaxTargetBitShift   mo.Option[int]
+	}
+	type args struct {
+		prevHash string
+	}
+
+	for _, data := range []struct {
+		name    string
+		fields  fields
+		args    args
+		want    int
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/genesis block",
+			fields: fields{
+				Loader:              loaders.MemoryLoader(nil),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: "",
+			},
+			want:    248,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/short history",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{time.Second, 0},
+					[]int{246, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(1, 246),
+			},
+			want:    246,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/target interval",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{2 * time.Minute, time.Minute, 0},
+					[]int{246, 247, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want:    246,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/too fast blocks",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{30 * time.Second, 15 * time.Second, 0},
+					[]int{246, 247, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want:    245,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/too slow blocks",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{4 * time.Minute, 2 * time.Minute, 0},
+					[]int{246, 247, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want:    247,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/too slow blocks/limited shift",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{time.Hour, 30 * time.Minute, 0},
+					[]int{246, 247, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+				MaxTargetBitShift:   mo.Some(2),
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want:    248,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/equal timestamps",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{0, 0, 0},
+					[]int{246, 247, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want:    245,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/previous block is not the last one",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{time.Hour, 4 * time.Minute, 2 * time.Minute, 0},
+					[]int{200, 246, 247, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want:    247,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid parameters",
+			fields: fields{
+				Loader:              loaders.MemoryLoader(nil),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          1,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: "",
+			},
+			want: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+		{
+			name: "error/unable to load the previous blocks/regular error",
+			fields: fields{
+				Loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 2).Return(nil, nil, iotest.ErrTimeout)
+
+					return loader
+				}(),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want:    0,
+			wantErr: assert.Error,
+		},
+		{
+			name: "error/unable to load the previous blocks/not found",
+			fields: fields{
+				Loader: makeScheduledBlocks(
+					[]time.Duration{2 * time.Minute, time.Minute, 0},
+					[]int{246, 247, 248},
+				),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(23, 246),
+			},
+			want: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrNotFound)
+			},
+		},
+		{
+			name: "error/unable to load the previous blocks/unlinked blocks",
+			fields: fields{
+				Loader: func() loaders.MemoryLoader {
+					blocks := makeScheduledBlocks(
+						[]time.Duration{2 * time.Minute, time.Minute, 0},
+						[]int{246, 247, 248},
+					)
+					blocks[1].PrevHash = "hash #23"
+
+					return blocks
+				}(),
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: makeScheduledHash(2, 246),
+			},
+			want: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrInvalidPrevHash)
+			},
+		},
+		{
+			name: "error/unable to parse the previous hash",
+			fields: fields{
+				Loader: loaders.MemoryLoader{
+					{Timestamp: clock(), Hash: "hash #0"},
+				},
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			},
+			args: args{
+				prevHash: "hash #0",
+			},
+			want: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidParameters)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			schedule := TargetBitSchedule{
+				Loader:              data.fields.Loader,
+				ChunkSize:           data.fields.ChunkSize,
+				InitialTargetBit:    data.fields.InitialTargetBit,
+				BlockCount:          data.fields.BlockCount,
+				TargetBlockInterval: data.fields.TargetBlockInterval,
+				MaxTargetBitShift:   data.fields.MaxTargetBitShift,
+			}
+			got, err := schedule.TargetBit(data.args.prevHash)
+
+			if loader, ok := data.fields.Loader.(*MockLoader); ok {
+				mock.AssertExpectationsForObjects(test, loader)
+			}
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestTargetBitSchedule_TargetBitEx(test *testing.T) {
+	chain := makeScheduledChain(
+		[]time.Duration{4 * time.Minute, 2 * time.Minute, 0},
+		[]int{246, 247, 248},
+	)
+	block := blockchain.Block{
+		Timestamp: clock().Add(5 * time.Minute),
+		PrevHash:  chain[0].Hash,
+		Height:    3,
+	}
+
+	type args struct {
+		block    blockchain.Block
+		ancestry blockchain.BlockGroup
+	}
+
+	for _, data := range []struct {
+		name    string
+		loader  func() blockchain.Loader
+		args    args
+		want    int
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/ancestry",
+			loader: func() blockchain.Loader {
+				return new(MockIndexedLoader)
+			},
+			args: args{
+				block:    block,
+				ancestry: chain,
+			},
+			want:    247,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/ancestry and lookup by a height",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.On("LoadBlockByHeight", 1).Return(chain[1], nil)
+				loader.On("LoadBlockByHeight", 0).Return(chain[2], nil)
+
+				return loader
+			},
+			args: args{
+				block:    block,
+				ancestry: chain[:1],
+			},
+			want:    247,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/lookup by a height",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.On("LoadBlockByHeight", 2).Return(chain[0], nil)
+				loader.On("LoadBlockByHeight", 1).Return(chain[1], nil)
+				loader.On("LoadBlockByHeight", 0).Return(chain[2], nil)
+
+				return loader
+			},
+			args: args{
+				block:    block,
+				ancestry: nil,
+			},
+			want:    247,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/paging instead of the unexpected block",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.On("LoadBlockByHeight", 2).Return(chain[1], nil)
+				loader.On("LoadBlocks", nil, 2).Return(chain[:2], 2, nil)
+				loader.On("LoadBlocks", 2, 2).Return(chain[2:], 4, nil)
+
+				return loader
+			},
+			args: args{
+				block:    block,
+				ancestry: nil,
+			},
+			want:    247,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/paging instead of the unsupported lookup",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.
+					On("LoadBlockByHeight", 2).
+					Return(blockchain.Block{}, errors.ErrUnsupported)
+				loader.On("LoadBlocks", nil, 2).Return(chain[:2], 2, nil)
+				loader.On("LoadBlocks", 2, 2).Return(chain[2:], 4, nil)
+
+				return loader
+			},
+			args: args{
+				block:    block,
+				ancestry: nil,
+			},
+			want:    247,
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/unable to look up by a height",
+			loader: func() blockchain.Loader {
+				loader := new(MockIndexedLoader)
+				loader.On("LoadBlockByHeight", 1).Return(chain[1], nil)
+				loader.
+					On("LoadBlockByHeight", 0).
+					Return(blockchain.Block{}, iotest.ErrTimeout)
+
+				return loader
+			},
+			args: args{
+				block:    block,
+				ancestry: chain[:1],
+			},
+			want: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			loader := data.loader()
+			schedule := TargetBitSchedule{
+				Loader:              loader,
+				ChunkSize:           2,
+				InitialTargetBit:    248,
+				BlockCount:          3,
+				TargetBlockInterval: time.Minute,
+			}
+			got, err := schedule.TargetBitEx(data.args.block, data.args.ancestry)
+
+			mock.AssertExpectationsForObjects(test, loader)
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+// the blocks are ordered from the last one backward and linked by the hashes
+func makeScheduledBlocks(
+	timestampOffsets []time.Duration,
+	targetBits []int,
+) loaders.MemoryLoader {
+	var blocks loaders.MemoryLoader
+	for index, timestampOffset := range timestampOffsets {
+		height := len(timestampOffsets) - index - 1
+		blocks = append(blocks, blockchain.Block{
+			Timestamp: clock().Add(timestampOffset),
+			Hash:      makeScheduledHash(height, targetBits[index]),
+		})
+	}
+	for index := range blocks[:max(len(blocks)-1, 0)] {
+		blocks[index].PrevHash = blocks[index+1].Hash
+	}
+
+	return blocks
+}
+
+// the blocks are ordered from the last one backward and have the heights
+func makeScheduledChain(
+	timestampOffsets []time.Duration,
+	targetBits []int,
+) blockchain.BlockGroup {
+	blocks := blockchain.BlockGroup(
+		makeScheduledBlocks(timestampOffsets, targetBits),
+	)
+	for index := range blocks {
+		blocks[index].Height = len(blocks) - index - 1
+	}
+
+	return blocks
+}
+
+// the nonce is replaced by the height to make the hashes unique
+func makeScheduledHash(height int, targetBit int) string {
+	return fmt.Sprintf("v1:%d:%d:00", targetBit, height)
+}
+
+func TestProofOfWork_withTargetBitSchedule(test *testing.T) {
+	schedule := TargetBitSchedule{
+		Loader: makeScheduledBlocks(
+			[]time.Duration{4 * time.Minute, 2 * time.Minute, 0},
+			[]int{246, 247, 248},
+		),
+		ChunkSize:           2,
+		InitialTargetBit:    248,
+		BlockCount:          3,
+		TargetBlockInterval: time.Minute,
+	}
+
+	for _, data := range []struct {
+		name          string
+		minerProofer  ProofOfWork
+		prevHash      string
+		wantTargetBit int
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/scheduled target bit",
+			minerProofer: ProofOfWork{
+				TargetBit:         200,
+				TargetBitSchedule: mo.Some(schedule),
+			},
+			prevHash:      makeScheduledHash(2, 246),
+			wantTargetBit: 247,
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "success/harder target bit",
+			minerProofer:  ProofOfWork{TargetBit: 246},
+			prevHash:      makeScheduledHash(2, 246),
+			wantTargetBit: 246,
+			wantErr:       assert.NoError,
+		},
+		{
+			name:          "error/easier target bit",
+			minerProofer:  ProofOfWork{TargetBit: 248},
+			prevHash:      makeScheduledHash(2, 246),
+			wantTargetBit: 248,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInsufficientDifficulty)
+			},
+		},
+		{
+			name:          "error/unknown previous block",
+			minerProofer:  ProofOfWork{TargetBit: 248},
+			prevHash:      makeScheduledHash(23, 246),
+			wantTargetBit: 248,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrNotFound)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			block := blockchain.Block{
+				Timestamp: clock(),
+				Data:      blockchain.NewData("hash"),
+				PrevHash:  data.prevHash,
+			}
+
+			var err error
+			block.Hash, err = data.minerProofer.HashEx(context.Background(), block)
+			require.NoError(test, err)
+
+			hashParts, err := parseHash(block.Hash)
+			require.NoError(test, err)
+
+			validatorProofer := ProofOfWork{TargetBitSchedule: mo.Some(schedule)}
+			err = validatorProofer.Validate(block)
+
+			assert.Equal(test, data.wantTargetBit, hashParts.targetBitIndex.ToInt())
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestProofOfWork_withTargetBitScheduleAndBlockchainMerge(test *testing.T) {
+	timestamp := clock()
+	clock := func() time.Time {
+		timestamp = timestamp.Add(time.Hour)
+		return timestamp
+	}
+
+	// the schedule of each blockchain loads the blocks from its own storage
+	makeDependencies := func(
+		storage blockchain.GroupStorage,
+	) blockchain.Dependencies {
+		return blockchain.Dependencies{
+			BlockDependencies: blockchain.BlockDependencies{
+				Clock: clock,
+				Proofer: ProofOfWork{
+					TargetBitSchedule: mo.Some(TargetBitSchedule{
+						Loader:              storage,
+						ChunkSize:           2,
+						InitialTargetBit:    248,
+						BlockCount:          2,
+						TargetBlockInterval: time.Hour,
+					}),
+				},
+			},
+			Storage: storage,
+		}
+	}
+
+	genesisBlock, err := blockchain.NewGenesisBlockEx(
+		context.Background(),
+		blockchain.NewGenesisBlockExParams{
+			Dependencies: makeDependencies(nil).BlockDependencies,
+			Data:         blockchain.NewData("genesis block"),
+		},
+	)
+	require.NoError(test, err)
+
+	var blockchains []*blockchain.Blockchain
+	var groupStorages []blockchain.GroupStorage
+	for range 2 {
+		storage := storing.NewGroupStorage(
+			storages.NewMemoryStorage(blockchain.BlockGroup{genesisBlock}),
+		)
+		blockchainInstance, err := blockchain.NewBlockchainEx(
+			context.Background(),
+			blockchain.NewBlockchainExParams{
+				Dependencies: makeDependencies(storage),
+			},
+		)
+		require.NoError(test, err)
+
+		blockchains = append(blockchains, blockchainInstance)
+		groupStorages = append(groupStorages, storage)
+	}
+
+	for index := range 3 {
+		err := blockchains[1].AddBlockEx(
+			context.Background(),
+			blockchain.NewData(fmt.Sprintf("block #%d", index)),
+		)
+		require.NoError(test, err)
+	}
+
+	err = blockchains[0].Merge(blockchains[1], 2)
+	require.NoError(test, err)
+
+	gotBlocks, _, err := groupStorages[0].LoadBlocks(nil, 10)
+	require.NoError(test, err)
+
+	wantBlocks, _, err := groupStorages[1].LoadBlocks(nil, 10)
+	require.NoError(test, err)
+
+	assert.Len(test, gotBlocks, 4)
+	assert.Equal(test, wantBlocks, gotBlocks)
+}
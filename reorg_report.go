@@ -0,0 +1,67 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ReorgReport ...
+//
+// It describes the fork found by the merging. The removed and the added blocks
+// are ordered from the last block to the first one and are empty
+// if the fork isn't merged.
+//
+// The work values are the total work of the left and the right differences
+// (see [BlockGroup.Work]), regardless of whether the fork is merged.
+type ReorgReport struct {
+	CommonBlock   Block
+	RemovedBlocks BlockGroup
+	AddedBlocks   BlockGroup
+	LeftWork      *big.Int
+	RightWork     *big.Int
+}
+
+func newReorgReport(
+	foundFork fork,
+	isReplaced bool,
+	proofer Proofer,
+) (ReorgReport, error) {
+	report := ReorgReport{CommonBlock: foundFork.commonBlock}
+	if isReplaced {
+		report.RemovedBlocks = foundFork.leftDifferences
+		report.AddedBlocks = foundFork.rightDifferences
+	}
+
+	leftWork, err := foundFork.leftDifferences.Work(proofer)
+	if err != nil {
+		return report, fmt.Errorf(
+			"unable to calculate the work of the left differences: %w",
+			err,
+		)
+	}
+
+	rightWork, err := foundFork.rightDifferences.Work(proofer)
+	if err != nil {
+		return report, fmt.Errorf(
+			"unable to calculate the work of the right differences: %w",
+			err,
+		)
+	}
+
+	report.LeftWork, report.RightWork = leftWork, rightWork
+	return report, nil
+}
+
+// IsChanged ...
+//
+// It reports whether the fork is merged.
+func (report ReorgReport) IsChanged() bool {
+	return len(report.AddedBlocks) != 0
+}
+
+// Depth ...
+//
+// It's the count of the removed blocks.
+func (report ReorgReport) Depth() int {
+	return len(report.RemovedBlocks)
+}
@@ -0,0 +1,64 @@
+package blockchain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReorgReport_IsChanged(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		report ReorgReport
+		want   bool
+	}{
+		{
+			name: "changed",
+			report: ReorgReport{
+				AddedBlocks: BlockGroup{{Hash: "hash #1", PrevHash: "hash #0"}},
+			},
+			want: true,
+		},
+		{
+			name:   "not changed",
+			report: ReorgReport{},
+			want:   false,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.report.IsChanged()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestReorgReport_Depth(test *testing.T) {
+	for _, data := range []struct {
+		name   string
+		report ReorgReport
+		want   int
+	}{
+		{
+			name: "nonempty",
+			report: ReorgReport{
+				RemovedBlocks: BlockGroup{
+					{Hash: "hash #2", PrevHash: "hash #1"},
+					{Hash: "hash #1", PrevHash: "hash #0"},
+				},
+			},
+			want: 2,
+		},
+		{
+			name:   "empty",
+			report: ReorgReport{},
+			want:   0,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.report.Depth()
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package blockchain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidData ...
+//
+// The state engine wraps the errors of the data that can never be applied
+// (e.g., with an invalid signature) with it.
+var ErrInvalidData = errors.New("invalid data")
+
+//go:generate mockery --name=StateEngine --inpackage --case=underscore --testonly
+
+// StateEngine ...
+//
+// It's the ledger state derived from the blocks of the blockchain.
+// The ApplyBlock method should reject a block without changing the state,
+// and the UndoBlock method should revert the changes of the last applied
+// block.
+type StateEngine interface {
+	ApplyBlock(block Block) error
+	UndoBlock(block Block) error
+}
+
+//go:generate mockery --name=ValidatingStateEngine --inpackage --case=underscore --testonly
+
+// ValidatingStateEngine ...
+//
+// It's an optional extension of the [StateEngine] interface that checks
+// the block can be applied without changing the state. If it's supported,
+// the [BlockProducer] checks the pending data against it before batching
+// and drops the data rejected with the [ErrInvalidData] error.
+type ValidatingStateEngine interface {
+	StateEngine
+
+	ValidateBlock(block Block) error
+}
+
+// it undoes the deleted blocks from the last one and applies the stored blocks
+// from the first one; on a failure, it restores the previous state
+func switchState(
+	stateEngine StateEngine,
+	deletedBlocks BlockGroup,
+	storedBlocks BlockGroup,
+) error {
+	for index, block := range deletedBlocks {
+		if err := stateEngine.UndoBlock(block); err != nil {
+			err = fmt.Errorf("unable to undo the block #%d: %w", index, err)
+
+			if rollbackErr := applyBlocks(
+				stateEngine,
+				deletedBlocks[:index],
+			); rollbackErr != nil {
+				return errors.Join(err, fmt.Errorf(
+					"unable to reapply the undone blocks on the rollback: %w",
+					rollbackErr,
+				))
+			}
+
+			return err
+		}
+	}
+
+	if err := applyBlocks(stateEngine, storedBlocks); err != nil {
+		rollbackErr := applyBlocks(stateEngine, deletedBlocks)
+		if rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf(
+				"unable to reapply the undone blocks on the rollback: %w",
+				rollbackErr,
+			))
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// it applies the blocks from the first one; on a failure, it undoes
+// the applied blocks
+func applyBlocks(stateEngine StateEngine, blocks BlockGroup) error {
+	for index := len(blocks) - 1; index >= 0; index-- {
+		if err := stateEngine.ApplyBlock(blocks[index]); err != nil {
+			err = fmt.Errorf("unable to apply the block #%d: %w", index, err)
+
+			for _, block := range blocks[index+1:] {
+				if rollbackErr := stateEngine.UndoBlock(block); rollbackErr != nil {
+					return errors.Join(err, fmt.Errorf(
+						"unable to undo the applied blocks on the rollback: %w",
+						rollbackErr,
+					))
+				}
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
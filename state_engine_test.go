@@ -0,0 +1,111 @@
+package blockchain
+
+import (
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSwitchState(test *testing.T) {
+	deletedBlocks := BlockGroup{
+		{Data: NewData("deleted block #2"), Hash: "deleted hash #2"},
+		{Data: NewData("deleted block #1"), Hash: "deleted hash #1"},
+	}
+	storedBlocks := BlockGroup{
+		{Data: NewData("stored block #2"), Hash: "stored hash #2"},
+		{Data: NewData("stored block #1"), Hash: "stored hash #1"},
+	}
+
+	for _, data := range []struct {
+		name              string
+		prepareMocks      func(stateEngine *MockStateEngine)
+		wantStateSequence []string
+		wantErr           assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			prepareMocks: func(stateEngine *MockStateEngine) {
+				stateEngine.On("UndoBlock", deletedBlocks[0]).Return(nil)
+				stateEngine.On("UndoBlock", deletedBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", storedBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", storedBlocks[0]).Return(nil)
+			},
+			wantStateSequence: []string{
+				"undo deleted hash #2",
+				"undo deleted hash #1",
+				"apply stored hash #1",
+				"apply stored hash #2",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error with undoing",
+			prepareMocks: func(stateEngine *MockStateEngine) {
+				stateEngine.On("UndoBlock", deletedBlocks[0]).Return(nil)
+				stateEngine.
+					On("UndoBlock", deletedBlocks[1]).
+					Return(iotest.ErrTimeout)
+				stateEngine.On("ApplyBlock", deletedBlocks[0]).Return(nil)
+			},
+			wantStateSequence: []string{
+				"undo deleted hash #2",
+				"undo deleted hash #1",
+				"apply deleted hash #2",
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+		{
+			name: "error with applying",
+			prepareMocks: func(stateEngine *MockStateEngine) {
+				stateEngine.On("UndoBlock", deletedBlocks[0]).Return(nil)
+				stateEngine.On("UndoBlock", deletedBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", storedBlocks[1]).Return(nil)
+				stateEngine.
+					On("ApplyBlock", storedBlocks[0]).
+					Return(iotest.ErrTimeout)
+				stateEngine.On("UndoBlock", storedBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", deletedBlocks[1]).Return(nil)
+				stateEngine.On("ApplyBlock", deletedBlocks[0]).Return(nil)
+			},
+			wantStateSequence: []string{
+				"undo deleted hash #2",
+				"undo deleted hash #1",
+				"apply stored hash #1",
+				"apply stored hash #2",
+				"undo stored hash #1",
+				"apply deleted hash #1",
+				"apply deleted hash #2",
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var stateSequence []string
+			stateEngine := new(MockStateEngine)
+			data.prepareMocks(stateEngine)
+			for _, call := range stateEngine.ExpectedCalls {
+				action := "apply"
+				if call.Method == "UndoBlock" {
+					action = "undo"
+				}
+
+				call.Run(func(args mock.Arguments) {
+					block := args.Get(0).(Block)
+					stateSequence = append(stateSequence, action+" "+block.Hash)
+				})
+			}
+
+			err := switchState(stateEngine, deletedBlocks, storedBlocks)
+
+			mock.AssertExpectationsForObjects(test, stateEngine)
+			assert.Equal(test, data.wantStateSequence, stateSequence)
+			data.wantErr(test, err)
+		})
+	}
+}
@@ -7,6 +7,9 @@ import (
 // ErrEmptyStorage ...
 var ErrEmptyStorage = errors.New("empty storage")
 
+// ErrNotFound ...
+var ErrNotFound = errors.New("not found")
+
 // Storage ...
 type Storage interface {
 	Loader
@@ -25,3 +28,22 @@ type GroupStorage interface {
 	StoreBlockGroup(blocks BlockGroup) error
 	DeleteBlockGroup(blocks BlockGroup) error
 }
+
+// IndexedStorage ...
+type IndexedStorage interface {
+	Storage
+
+	LoadBlockByHeight(height int) (Block, error)
+	LoadBlockByHash(hash string) (Block, error)
+}
+
+// TransactionalStorage ...
+//
+// The ReplaceBlockGroup method deletes and stores the blocks atomically:
+// either all the changes are applied, or none of them. It returns
+// the [errors.ErrUnsupported] error if the atomic replacing isn't supported.
+type TransactionalStorage interface {
+	Storage
+
+	ReplaceBlockGroup(deletedBlocks BlockGroup, storedBlocks BlockGroup) error
+}
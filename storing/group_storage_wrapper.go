@@ -1,6 +1,7 @@
 package storing
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/thewizardplusplus/go-blockchain"
@@ -36,3 +37,34 @@ func (wrapper GroupStorageWrapper) DeleteBlockGroup(
 
 	return nil
 }
+
+// LoadBlockByHeight ...
+//
+// It returns the [errors.ErrUnsupported] error if the wrapped storage doesn't
+// implement the [blockchain.IndexedLoader] interface.
+func (wrapper GroupStorageWrapper) LoadBlockByHeight(
+	height int,
+) (blockchain.Block, error) {
+	indexedLoader, ok := wrapper.Storage.(blockchain.IndexedLoader)
+	if !ok {
+		return blockchain.Block{}, errors.ErrUnsupported
+	}
+
+	return indexedLoader.LoadBlockByHeight(height)
+}
+
+// ReplaceBlockGroup ...
+//
+// It returns the [errors.ErrUnsupported] error if the wrapped storage doesn't
+// implement the [blockchain.TransactionalStorage] interface.
+func (wrapper GroupStorageWrapper) ReplaceBlockGroup(
+	deletedBlocks blockchain.BlockGroup,
+	storedBlocks blockchain.BlockGroup,
+) error {
+	transactionalStorage, ok := wrapper.Storage.(blockchain.TransactionalStorage)
+	if !ok {
+		return errors.ErrUnsupported
+	}
+
+	return transactionalStorage.ReplaceBlockGroup(deletedBlocks, storedBlocks)
+}
@@ -1,6 +1,7 @@
 package storing
 
 import (
+	"errors"
 	"testing"
 	"testing/iotest"
 	"time"
@@ -8,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/thewizardplusplus/go-blockchain"
+	"github.com/thewizardplusplus/go-blockchain/storing/storages"
 )
 
 func TestGroupStorageWrapper_StoreBlockGroup(test *testing.T) {
@@ -286,6 +288,108 @@ func TestGroupStorageWrapper_DeleteBlockGroup(test *testing.T) {
 	}
 }
 
+func TestGroupStorageWrapper_LoadBlockByHeight(test *testing.T) {
+	block := blockchain.Block{
+		Timestamp: clock(),
+		Data:      blockchain.NewData("block #1"),
+		Hash:      "hash #1",
+		PrevHash:  "",
+		Height:    0,
+	}
+
+	for _, data := range []struct {
+		name      string
+		storage   blockchain.Storage
+		wantBlock blockchain.Block
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "success",
+			storage:   storages.NewMemoryStorage(blockchain.BlockGroup{block}),
+			wantBlock: block,
+			wantErr:   assert.NoError,
+		},
+		{
+			name:      "error",
+			storage:   new(MockStorage),
+			wantBlock: blockchain.Block{},
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.ErrorIs(test, err, errors.ErrUnsupported, msgAndArgs...)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			wrapper := GroupStorageWrapper{
+				Storage: data.storage,
+			}
+			gotBlock, gotErr := wrapper.LoadBlockByHeight(0)
+
+			assert.Equal(test, data.wantBlock, gotBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestGroupStorageWrapper_ReplaceBlockGroup(test *testing.T) {
+	blocks := blockchain.BlockGroup{
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+		},
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+	}
+
+	for _, data := range []struct {
+		name       string
+		storage    blockchain.Storage
+		wantBlocks blockchain.BlockGroup
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:       "success",
+			storage:    storages.NewMemoryStorage(blocks[1:]),
+			wantBlocks: blocks,
+			wantErr:    assert.NoError,
+		},
+		{
+			name:       "error",
+			storage:    new(MockStorage),
+			wantBlocks: nil,
+			wantErr: func(
+				test assert.TestingT,
+				err error,
+				msgAndArgs ...interface{},
+			) bool {
+				return assert.ErrorIs(test, err, errors.ErrUnsupported, msgAndArgs...)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			wrapper := GroupStorageWrapper{
+				Storage: data.storage,
+			}
+			gotErr := wrapper.ReplaceBlockGroup(nil, blocks[:1])
+
+			if data.wantBlocks != nil {
+				gotBlocks, _, _ := wrapper.LoadBlocks(nil, 10)
+				assert.Equal(test, data.wantBlocks, gotBlocks)
+			}
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
 func clock() time.Time {
 	year, month, day := 2006, time.January, 2
 	hour, minute, second := 15, 4, 5
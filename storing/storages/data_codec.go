@@ -0,0 +1,54 @@
+package storages
+
+import (
+	"encoding"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+//go:generate mockery --name=DataCodec --inpackage --case=underscore --testonly
+
+// DataCodec ...
+type DataCodec interface {
+	EncodeData(data blockchain.Data) ([]byte, error)
+	DecodeData(rawData []byte) (blockchain.Data, error)
+}
+
+// TextDataCodec ...
+//
+// It stores the text representation of block data and restores it
+// as a string wrapped via the [blockchain.NewData] function.
+type TextDataCodec struct{}
+
+// EncodeData ...
+func (codec TextDataCodec) EncodeData(data blockchain.Data) ([]byte, error) {
+	// explicitly check this interface to prioritize its use
+	if marshaler, ok := data.(encoding.TextMarshaler); ok {
+		return marshaler.MarshalText()
+	}
+
+	return []byte(data.String()), nil
+}
+
+// DecodeData ...
+func (codec TextDataCodec) DecodeData(rawData []byte) (blockchain.Data, error) {
+	return blockchain.NewData(string(rawData)), nil
+}
+
+// TypedDataCodec ...
+//
+// It stores block data along with its type and restores it with its concrete
+// type via the [blockchain.EncodeData] and [blockchain.DecodeData] functions.
+type TypedDataCodec struct{}
+
+// EncodeData ...
+func (codec TypedDataCodec) EncodeData(data blockchain.Data) ([]byte, error) {
+	return blockchain.EncodeData(data)
+}
+
+// DecodeData ...
+func (codec TypedDataCodec) DecodeData(
+	rawData []byte,
+) (blockchain.Data, error) {
+	return blockchain.DecodeData(rawData)
+}
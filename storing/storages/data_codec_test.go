@@ -0,0 +1,92 @@
+package storages
+
+import (
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestTextDataCodec_EncodeData(test *testing.T) {
+	type args struct {
+		data blockchain.Data
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		want    []byte
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success with the encoding.TextMarshaler interface",
+			args: args{
+				data: blockchain.NewData("data"),
+			},
+			want:    []byte("data"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success without the encoding.TextMarshaler interface",
+			args: args{
+				data: func() blockchain.Data {
+					data := new(MockData)
+					data.On("String").Return("data")
+
+					return data
+				}(),
+			},
+			want:    []byte("data"),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				data: blockchain.NewData(errorTextMarshaler{}),
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, gotErr := TextDataCodec{}.EncodeData(data.args.data)
+
+			if mockData, ok := data.args.data.(*MockData); ok {
+				mock.AssertExpectationsForObjects(test, mockData)
+			}
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestTextDataCodec_DecodeData(test *testing.T) {
+	got, gotErr := TextDataCodec{}.DecodeData([]byte("data"))
+
+	assert.Equal(test, blockchain.NewData("data"), got)
+	assert.NoError(test, gotErr)
+}
+
+type errorTextMarshaler struct{}
+
+func (errorTextMarshaler) MarshalText() ([]byte, error) {
+	return nil, iotest.ErrTimeout
+}
+
+func TestTypedDataCodec(test *testing.T) {
+	data := blockchain.NewMerkleData([]blockchain.Data{
+		blockchain.NewData("one"),
+		blockchain.NewData("two"),
+	})
+
+	rawData, err := TypedDataCodec{}.EncodeData(data)
+	require.NoError(test, err)
+
+	got, gotErr := TypedDataCodec{}.DecodeData(rawData)
+
+	assert.Equal(test, data, got)
+	assert.NoError(test, gotErr)
+}
@@ -0,0 +1,633 @@
+package storages
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+const (
+	defaultMaxSegmentSize  = 64 << 20 // 64 MiB
+	segmentFileNamePattern = "%08d.segment"
+	segmentFileMode        = 0o644
+	directoryMode          = 0o755
+	recordHeaderSize       = 8 // payload length and its checksum
+)
+
+type recordKind byte
+
+const (
+	storingRecordKind recordKind = iota + 1
+	deletingRecordKind
+)
+
+var (
+	// ErrCorruptedSegment ...
+	ErrCorruptedSegment = errors.New("corrupted segment")
+
+	errTornRecord = errors.New("torn record")
+)
+
+// FileStorageParams ...
+type FileStorageParams struct {
+	Directory      string
+	DataCodec      DataCodec
+	MaxSegmentSize mo.Option[int64]
+}
+
+// FileStorage ...
+//
+// It appends every change to segment files and keeps the actual state
+// in memory, so the block loading has the same semantics
+// as the [MemoryStorage] one.
+type FileStorage struct {
+	params        FileStorageParams
+	memoryStorage *MemoryStorage
+	segmentNumber int
+	segmentFile   *os.File
+	segmentSize   int64
+}
+
+// NewFileStorage ...
+func NewFileStorage(params FileStorageParams) (*FileStorage, error) {
+	if err := os.MkdirAll(params.Directory, directoryMode); err != nil {
+		return nil, fmt.Errorf("unable to create the directory: %w", err)
+	}
+
+	segmentNumbers, err := listSegmentNumbers(params.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list the segments: %w", err)
+	}
+
+	storage := &FileStorage{
+		params:        params,
+		memoryStorage: NewMemoryStorage(nil),
+		segmentNumber: 1,
+	}
+	for index, segmentNumber := range segmentNumbers {
+		isLastSegment := index == len(segmentNumbers)-1
+		if err := storage.replaySegment(segmentNumber, isLastSegment); err != nil {
+			return nil, fmt.Errorf(
+				"unable to replay segment #%d: %w",
+				segmentNumber,
+				err,
+			)
+		}
+
+		storage.segmentNumber = segmentNumber
+	}
+
+	if err := storage.openSegment(); err != nil {
+		return nil, fmt.Errorf("unable to open the segment: %w", err)
+	}
+
+	return storage, nil
+}
+
+// LoadBlocks ...
+func (storage *FileStorage) LoadBlocks(cursor interface{}, count int) (
+	blocks blockchain.BlockGroup,
+	nextCursor interface{},
+	err error,
+) {
+	return storage.memoryStorage.LoadBlocks(cursor, count)
+}
+
+// LoadLastBlock ...
+func (storage *FileStorage) LoadLastBlock() (blockchain.Block, error) {
+	return storage.memoryStorage.LoadLastBlock()
+}
+
+// StoreBlock ...
+func (storage *FileStorage) StoreBlock(block blockchain.Block) error {
+	blocks := blockchain.BlockGroup{block}
+	if err := storage.appendRecord(storingRecordKind, blocks); err != nil {
+		return fmt.Errorf("unable to append the record: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlock ...
+func (storage *FileStorage) DeleteBlock(block blockchain.Block) error {
+	blocks := blockchain.BlockGroup{block}
+	if err := storage.appendRecord(deletingRecordKind, blocks); err != nil {
+		return fmt.Errorf("unable to append the record: %w", err)
+	}
+
+	return nil
+}
+
+// StoreBlockGroup ...
+//
+// The whole block group is written as a single record,
+// so it is either stored completely or not stored at all.
+func (storage *FileStorage) StoreBlockGroup(
+	blocks blockchain.BlockGroup,
+) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	if err := storage.appendRecord(storingRecordKind, blocks); err != nil {
+		return fmt.Errorf("unable to append the record: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlockGroup ...
+//
+// The whole block group is written as a single record,
+// so it is either deleted completely or not deleted at all.
+func (storage *FileStorage) DeleteBlockGroup(
+	blocks blockchain.BlockGroup,
+) error {
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	if err := storage.appendRecord(deletingRecordKind, blocks); err != nil {
+		return fmt.Errorf("unable to append the record: %w", err)
+	}
+
+	return nil
+}
+
+// LoadBlockByHeight ...
+func (storage *FileStorage) LoadBlockByHeight(
+	height int,
+) (blockchain.Block, error) {
+	return storage.memoryStorage.LoadBlockByHeight(height)
+}
+
+// LoadBlockByHash ...
+func (storage *FileStorage) LoadBlockByHash(
+	hash string,
+) (blockchain.Block, error) {
+	return storage.memoryStorage.LoadBlockByHash(hash)
+}
+
+// Close ...
+func (storage *FileStorage) Close() error {
+	if err := storage.segmentFile.Close(); err != nil {
+		return fmt.Errorf("unable to close the segment: %w", err)
+	}
+
+	return nil
+}
+
+func (storage *FileStorage) replaySegment(
+	segmentNumber int,
+	isLastSegment bool,
+) error {
+	segmentPath := storage.segmentPath(segmentNumber)
+	content, err := os.ReadFile(segmentPath)
+	if err != nil {
+		return fmt.Errorf("unable to read the segment: %w", err)
+	}
+
+	var offset int
+	for offset < len(content) {
+		payload, recordSize, err := readRecord(content[offset:])
+		if err != nil {
+			// only the last record of the last segment can be torn by a crash
+			if errors.Is(err, errTornRecord) && isLastSegment {
+				break
+			}
+
+			return fmt.Errorf(
+				"unable to read the record at offset %d: %w",
+				offset,
+				errors.Join(err, ErrCorruptedSegment),
+			)
+		}
+
+		kind, blocks, err := decodeRecordPayload(payload, storage.params.DataCodec)
+		if err != nil {
+			return fmt.Errorf(
+				"unable to decode the record at offset %d: %w",
+				offset,
+				errors.Join(err, ErrCorruptedSegment),
+			)
+		}
+
+		storage.applyRecord(kind, blocks)
+		offset += recordSize
+	}
+
+	if offset < len(content) {
+		if err := truncateFile(segmentPath, int64(offset)); err != nil {
+			return fmt.Errorf("unable to truncate the torn record: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (storage *FileStorage) openSegment() error {
+	segmentFile, err := os.OpenFile(
+		storage.segmentPath(storage.segmentNumber),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		segmentFileMode,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to open the segment file: %w", err)
+	}
+
+	segmentInfo, err := segmentFile.Stat()
+	if err != nil {
+		segmentFile.Close() // nolint: errcheck, gosec
+		return fmt.Errorf("unable to get the segment file info: %w", err)
+	}
+
+	if err := syncDirectory(storage.params.Directory); err != nil {
+		segmentFile.Close() // nolint: errcheck, gosec
+		return fmt.Errorf("unable to sync the directory: %w", err)
+	}
+
+	storage.segmentFile = segmentFile
+	storage.segmentSize = segmentInfo.Size()
+
+	return nil
+}
+
+func (storage *FileStorage) rotateSegment() error {
+	// open the next segment before closing the current one,
+	// so the storage stays appendable if the opening fails
+	prevSegmentFile := storage.segmentFile
+	storage.segmentNumber++
+	if err := storage.openSegment(); err != nil {
+		storage.segmentNumber--
+		return fmt.Errorf("unable to open the segment: %w", err)
+	}
+
+	if err := prevSegmentFile.Close(); err != nil {
+		return fmt.Errorf("unable to close the previous segment file: %w", err)
+	}
+
+	return nil
+}
+
+func (storage *FileStorage) appendRecord(
+	kind recordKind,
+	blocks blockchain.BlockGroup,
+) error {
+	payload, err := encodeRecordPayload(kind, blocks, storage.params.DataCodec)
+	if err != nil {
+		return fmt.Errorf("unable to encode the record: %w", err)
+	}
+
+	record := make([]byte, recordHeaderSize, recordHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(payload))
+	record = append(record, payload...)
+
+	recordSize := int64(len(record))
+	if storage.segmentSize != 0 &&
+		storage.segmentSize+recordSize > storage.maxSegmentSize() {
+		if err := storage.rotateSegment(); err != nil {
+			return fmt.Errorf("unable to rotate the segment: %w", err)
+		}
+	}
+
+	if err := storage.writeRecord(record); err != nil {
+		// try to remove the partially written record
+		// to keep the segment appendable
+		if truncateErr := storage.segmentFile.Truncate(
+			storage.segmentSize,
+		); truncateErr != nil {
+			return errors.Join(
+				err,
+				fmt.Errorf("unable to truncate the record: %w", truncateErr),
+			)
+		}
+
+		return err
+	}
+
+	storage.segmentSize += recordSize
+	storage.applyRecord(kind, blocks)
+
+	return nil
+}
+
+func (storage *FileStorage) writeRecord(record []byte) error {
+	if _, err := storage.segmentFile.Write(record); err != nil {
+		return fmt.Errorf("unable to write the record: %w", err)
+	}
+
+	if err := storage.segmentFile.Sync(); err != nil {
+		return fmt.Errorf("unable to sync the segment file: %w", err)
+	}
+
+	return nil
+}
+
+func (storage *FileStorage) applyRecord(
+	kind recordKind,
+	blocks blockchain.BlockGroup,
+) {
+	// the memory storage never returns an error
+	for _, block := range blocks {
+		switch kind {
+		case storingRecordKind:
+			storage.memoryStorage.StoreBlock(block) // nolint: errcheck, gosec
+		case deletingRecordKind:
+			storage.memoryStorage.DeleteBlock(block) // nolint: errcheck, gosec
+		}
+	}
+}
+
+func (storage *FileStorage) maxSegmentSize() int64 {
+	return storage.params.MaxSegmentSize.OrElse(defaultMaxSegmentSize)
+}
+
+func (storage *FileStorage) segmentPath(segmentNumber int) string {
+	segmentFileName := fmt.Sprintf(segmentFileNamePattern, segmentNumber)
+	return filepath.Join(storage.params.Directory, segmentFileName)
+}
+
+func listSegmentNumbers(directory string) ([]int, error) {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the directory: %w", err)
+	}
+
+	var segmentNumbers []int
+	for _, entry := range entries {
+		var segmentNumber int
+		_, err := fmt.Sscanf(entry.Name(), segmentFileNamePattern, &segmentNumber)
+		if err != nil || entry.IsDir() {
+			continue
+		}
+
+		segmentNumbers = append(segmentNumbers, segmentNumber)
+	}
+
+	sort.Ints(segmentNumbers)
+	return segmentNumbers, nil
+}
+
+func readRecord(content []byte) (payload []byte, recordSize int, err error) {
+	if len(content) < recordHeaderSize {
+		return nil, 0, fmt.Errorf("the header is incomplete: %w", errTornRecord)
+	}
+
+	payloadSize := int(binary.BigEndian.Uint32(content[:4]))
+	checksum := binary.BigEndian.Uint32(content[4:recordHeaderSize])
+	if len(content)-recordHeaderSize < payloadSize {
+		// only the final record can be partially written by a crash,
+		// so the complete records after the header mean the corrupted size
+		if containsRecord(content[recordHeaderSize:]) {
+			return nil, 0, errors.New("the payload size exceeds the segment")
+		}
+
+		return nil, 0, fmt.Errorf("the payload is incomplete: %w", errTornRecord)
+	}
+
+	payload = content[recordHeaderSize : recordHeaderSize+payloadSize]
+	if crc32.ChecksumIEEE(payload) != checksum {
+		// only the final record can be partially written by a crash,
+		// otherwise the record is corrupted
+		if recordHeaderSize+payloadSize == len(content) {
+			return nil, 0, fmt.Errorf(
+				"the checksum of the final record mismatches: %w",
+				errTornRecord,
+			)
+		}
+
+		return nil, 0, errors.New("the checksum mismatches")
+	}
+
+	return payload, recordHeaderSize + payloadSize, nil
+}
+
+func containsRecord(content []byte) bool {
+	for offset := 0; offset+recordHeaderSize <= len(content); offset++ {
+		payloadSize := int(binary.BigEndian.Uint32(content[offset : offset+4]))
+		payloadOffset := offset + recordHeaderSize
+		// the records are never empty, so skip the zero bytes
+		if payloadSize == 0 || len(content)-payloadOffset < payloadSize {
+			continue
+		}
+
+		checksum := binary.BigEndian.Uint32(content[offset+4 : payloadOffset])
+		payload := content[payloadOffset : payloadOffset+payloadSize]
+		if crc32.ChecksumIEEE(payload) == checksum {
+			return true
+		}
+	}
+
+	return false
+}
+
+func encodeRecordPayload(
+	kind recordKind,
+	blocks blockchain.BlockGroup,
+	dataCodec DataCodec,
+) ([]byte, error) {
+	var buffer bytes.Buffer
+	buffer.WriteByte(byte(kind))
+	writeUvarint(&buffer, uint64(len(blocks)))
+	for index, block := range blocks {
+		if err := encodeBlock(&buffer, block, dataCodec); err != nil {
+			return nil, fmt.Errorf("unable to encode block #%d: %w", index, err)
+		}
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func decodeRecordPayload(payload []byte, dataCodec DataCodec) (
+	kind recordKind,
+	blocks blockchain.BlockGroup,
+	err error,
+) {
+	reader := bytes.NewReader(payload)
+	rawKind, err := reader.ReadByte()
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read the record kind: %w", err)
+	}
+
+	kind = recordKind(rawKind)
+	if kind != storingRecordKind && kind != deletingRecordKind {
+		return 0, nil, fmt.Errorf("unknown record kind %d", kind)
+	}
+
+	blockCount, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return 0, nil, fmt.Errorf("unable to read the block count: %w", err)
+	}
+	// each block takes at least one byte per field
+	if blockCount > uint64(reader.Len()) {
+		return 0, nil, fmt.Errorf("the block count %d is too big", blockCount)
+	}
+
+	blocks = make(blockchain.BlockGroup, 0, blockCount)
+	for index := uint64(0); index < blockCount; index++ {
+		block, err := decodeBlock(reader, dataCodec)
+		if err != nil {
+			return 0, nil, fmt.Errorf("unable to decode block #%d: %w", index, err)
+		}
+
+		blocks = append(blocks, block)
+	}
+
+	return kind, blocks, nil
+}
+
+func encodeBlock(
+	buffer *bytes.Buffer,
+	block blockchain.Block,
+	dataCodec DataCodec,
+) error {
+	timestamp, err := block.Timestamp.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("unable to encode the timestamp: %w", err)
+	}
+
+	data, err := dataCodec.EncodeData(block.Data)
+	if err != nil {
+		return fmt.Errorf("unable to encode the data: %w", err)
+	}
+
+	writeBytes(buffer, timestamp)
+	writeBytes(buffer, data)
+	writeBytes(buffer, []byte(block.Hash))
+	writeBytes(buffer, []byte(block.PrevHash))
+	writeVarint(buffer, int64(block.Height))
+
+	return nil
+}
+
+func decodeBlock(
+	reader *bytes.Reader,
+	dataCodec DataCodec,
+) (blockchain.Block, error) {
+	rawTimestamp, err := readBytes(reader)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf("unable to read the timestamp: %w", err)
+	}
+
+	var timestamp time.Time
+	if err := timestamp.UnmarshalBinary(rawTimestamp); err != nil {
+		return blockchain.Block{}, fmt.Errorf(
+			"unable to decode the timestamp: %w",
+			err,
+		)
+	}
+
+	rawData, err := readBytes(reader)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf("unable to read the data: %w", err)
+	}
+
+	data, err := dataCodec.DecodeData(rawData)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf("unable to decode the data: %w", err)
+	}
+
+	hash, err := readBytes(reader)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf("unable to read the hash: %w", err)
+	}
+
+	prevHash, err := readBytes(reader)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf(
+			"unable to read the previous hash: %w",
+			err,
+		)
+	}
+
+	height, err := binary.ReadVarint(reader)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf("unable to read the height: %w", err)
+	}
+
+	block := blockchain.Block{
+		Timestamp: timestamp,
+		Data:      data,
+		Hash:      string(hash),
+		PrevHash:  string(prevHash),
+		Height:    int(height),
+	}
+	return block, nil
+}
+
+func writeUvarint(buffer *bytes.Buffer, value uint64) {
+	var rawValue [binary.MaxVarintLen64]byte
+	size := binary.PutUvarint(rawValue[:], value)
+	buffer.Write(rawValue[:size])
+}
+
+func writeVarint(buffer *bytes.Buffer, value int64) {
+	var rawValue [binary.MaxVarintLen64]byte
+	size := binary.PutVarint(rawValue[:], value)
+	buffer.Write(rawValue[:size])
+}
+
+func writeBytes(buffer *bytes.Buffer, value []byte) {
+	writeUvarint(buffer, uint64(len(value)))
+	buffer.Write(value)
+}
+
+func readBytes(reader *bytes.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the size: %w", err)
+	}
+	if size > uint64(reader.Len()) {
+		const message = "the size %d is too big: %w"
+		return nil, fmt.Errorf(message, size, io.ErrUnexpectedEOF)
+	}
+
+	value := make([]byte, size)
+	// the size was checked above, so the reading cannot fail
+	reader.Read(value) // nolint: errcheck, gosec
+
+	return value, nil
+}
+
+func truncateFile(path string, size int64) error {
+	file, err := os.OpenFile(path, os.O_WRONLY, segmentFileMode)
+	if err != nil {
+		return fmt.Errorf("unable to open the file: %w", err)
+	}
+	defer file.Close() // nolint: errcheck
+
+	if err := file.Truncate(size); err != nil {
+		return fmt.Errorf("unable to truncate the file: %w", err)
+	}
+
+	if err := file.Sync(); err != nil {
+		return fmt.Errorf("unable to sync the file: %w", err)
+	}
+
+	return nil
+}
+
+func syncDirectory(directory string) error {
+	directoryFile, err := os.Open(directory)
+	if err != nil {
+		return fmt.Errorf("unable to open the directory: %w", err)
+	}
+	defer directoryFile.Close() // nolint: errcheck
+
+	if err := directoryFile.Sync(); err != nil {
+		return fmt.Errorf("unable to sync the directory: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,724 @@
+package storages
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestNewFileStorage(test *testing.T) {
+	type args struct {
+		dataCodec DataCodec
+	}
+
+	for _, data := range []struct {
+		name          string
+		prepare       func(test *testing.T, directory string)
+		args          args
+		wantBlocks    blockchain.BlockGroup
+		wantFileSizes map[string]int64
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "success without segments",
+			prepare: func(test *testing.T, directory string) {},
+			args: args{
+				dataCodec: TextDataCodec{},
+			},
+			wantBlocks:    nil,
+			wantFileSizes: map[string]int64{"00000001.segment": 0},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "success with segments",
+			prepare: func(test *testing.T, directory string) {
+				storage := openFileStorage(test, directory, mo.None[int64]())
+				defer storage.Close() // nolint: errcheck
+
+				require.NoError(test, storage.StoreBlockGroup(blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+				}))
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3"),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				}))
+				require.NoError(test, storage.DeleteBlock(blockchain.Block{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3"),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				}))
+			},
+			args: args{
+				dataCodec: TextDataCodec{},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success with a torn tail record",
+			prepare: func(test *testing.T, directory string) {
+				storage := openFileStorage(test, directory, mo.None[int64]())
+				defer storage.Close() // nolint: errcheck
+
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				}))
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				}))
+
+				// emulate the crash during writing of the second record
+				segmentPath := filepath.Join(directory, "00000001.segment")
+				require.NoError(test, os.Truncate(segmentPath, 58))
+			},
+			args: args{
+				dataCodec: TextDataCodec{},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantFileSizes: map[string]int64{"00000001.segment": 45},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "success with a torn tail record by the checksum",
+			prepare: func(test *testing.T, directory string) {
+				storage := openFileStorage(test, directory, mo.None[int64]())
+				defer storage.Close() // nolint: errcheck
+
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				}))
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				}))
+
+				// emulate the crash before flushing the payload of the second record
+				segmentPath := filepath.Join(directory, "00000001.segment")
+				corruptFile(test, segmentPath, -1)
+			},
+			args: args{
+				dataCodec: TextDataCodec{},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantFileSizes: map[string]int64{"00000001.segment": 45},
+			wantErr:       assert.NoError,
+		},
+		{
+			name: "error with a corrupted record in the middle of the segment",
+			prepare: func(test *testing.T, directory string) {
+				storage := openFileStorage(test, directory, mo.None[int64]())
+				defer storage.Close() // nolint: errcheck
+
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				}))
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				}))
+
+				// the corrupted record isn't the final one, so it isn't torn
+				segmentPath := filepath.Join(directory, "00000001.segment")
+				corruptFile(test, segmentPath, 20)
+			},
+			args: args{
+				dataCodec: TextDataCodec{},
+			},
+			wantBlocks:    nil,
+			wantFileSizes: map[string]int64{"00000001.segment": 97},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrCorruptedSegment)
+			},
+		},
+		{
+			name: "error with a corrupted size of the record in the middle",
+			prepare: func(test *testing.T, directory string) {
+				storage := openFileStorage(test, directory, mo.None[int64]())
+				defer storage.Close() // nolint: errcheck
+
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				}))
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				}))
+
+				// the corrupted size exceeds the segment, but the record isn't torn,
+				// because the complete second record follows it
+				segmentPath := filepath.Join(directory, "00000001.segment")
+				corruptFile(test, segmentPath, 2)
+			},
+			args: args{
+				dataCodec: TextDataCodec{},
+			},
+			wantBlocks:    nil,
+			wantFileSizes: map[string]int64{"00000001.segment": 97},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrCorruptedSegment)
+			},
+		},
+		{
+			name: "error with a corrupted segment",
+			prepare: func(test *testing.T, directory string) {
+				storage := openFileStorage(test, directory, mo.Some[int64](1))
+				defer storage.Close() // nolint: errcheck
+
+				for _, block := range (blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+				}) {
+					require.NoError(test, storage.StoreBlock(block))
+				}
+
+				// only the last segment is allowed to contain a torn record
+				segmentPath := filepath.Join(directory, "00000001.segment")
+				require.NoError(test, os.Truncate(segmentPath, 23))
+			},
+			args: args{
+				dataCodec: TextDataCodec{},
+			},
+			wantBlocks: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrCorruptedSegment)
+			},
+		},
+		{
+			name: "error with the data decoding",
+			prepare: func(test *testing.T, directory string) {
+				storage := openFileStorage(test, directory, mo.None[int64]())
+				defer storage.Close() // nolint: errcheck
+
+				require.NoError(test, storage.StoreBlock(blockchain.Block{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				}))
+			},
+			args: args{
+				dataCodec: func() DataCodec {
+					dataCodec := new(MockDataCodec)
+					dataCodec.
+						On("DecodeData", []byte("block #1")).
+						Return(nil, iotest.ErrTimeout)
+
+					return dataCodec
+				}(),
+			},
+			wantBlocks: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			directory := test.TempDir()
+			data.prepare(test, directory)
+
+			storage, gotErr := NewFileStorage(FileStorageParams{
+				Directory: directory,
+				DataCodec: data.args.dataCodec,
+			})
+			if gotErr == nil {
+				defer storage.Close() // nolint: errcheck
+
+				assert.Equal(test, data.wantBlocks, loadAllBlocks(test, storage))
+			}
+
+			if dataCodec, ok := data.args.dataCodec.(*MockDataCodec); ok {
+				mock.AssertExpectationsForObjects(test, dataCodec)
+			}
+			for fileName, wantFileSize := range data.wantFileSizes {
+				fileInfo, err := os.Stat(filepath.Join(directory, fileName))
+				if assert.NoError(test, err) {
+					assert.Equal(test, wantFileSize, fileInfo.Size())
+				}
+			}
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestFileStorage_LoadLastBlock(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		blocks        blockchain.BlockGroup
+		wantLastBlock blockchain.Block
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "with an empty storage",
+			blocks:        nil,
+			wantLastBlock: blockchain.Block{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrEmptyStorage)
+			},
+		},
+		{
+			name: "with a nonempty storage",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+			},
+			wantLastBlock: blockchain.Block{
+				Timestamp: clock().Add(time.Hour),
+				Data:      blockchain.NewData("block #2"),
+				Hash:      "hash #2",
+				PrevHash:  "hash #1",
+			},
+			wantErr: assert.NoError,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			directory := test.TempDir()
+			storage := openFileStorage(test, directory, mo.None[int64]())
+			require.NoError(test, storage.StoreBlockGroup(data.blocks))
+			require.NoError(test, storage.Close())
+
+			storage = openFileStorage(test, directory, mo.None[int64]())
+			defer storage.Close() // nolint: errcheck
+
+			gotLastBlock, gotErr := storage.LoadLastBlock()
+
+			assert.Equal(test, data.wantLastBlock, gotLastBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestFileStorage_StoreBlock(test *testing.T) {
+	for _, data := range []struct {
+		name           string
+		maxSegmentSize mo.Option[int64]
+		blocks         blockchain.BlockGroup
+		wantBlocks     blockchain.BlockGroup
+		wantSegments   []string
+	}{
+		{
+			name:           "with one segment",
+			maxSegmentSize: mo.None[int64](),
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantSegments: []string{"00000001.segment"},
+		},
+		{
+			name:           "with several segments",
+			maxSegmentSize: mo.Some[int64](1),
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3"),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3"),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantSegments: []string{
+				"00000001.segment",
+				"00000002.segment",
+				"00000003.segment",
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			directory := test.TempDir()
+			storage := openFileStorage(test, directory, data.maxSegmentSize)
+			for _, block := range data.blocks {
+				require.NoError(test, storage.StoreBlock(block))
+			}
+			assert.Equal(test, data.wantBlocks, loadAllBlocks(test, storage))
+			require.NoError(test, storage.Close())
+
+			storage = openFileStorage(test, directory, data.maxSegmentSize)
+			defer storage.Close() // nolint: errcheck
+
+			gotSegments, err := filepath.Glob(filepath.Join(directory, "*.segment"))
+			require.NoError(test, err)
+			for index, gotSegment := range gotSegments {
+				gotSegments[index] = filepath.Base(gotSegment)
+			}
+
+			assert.Equal(test, data.wantBlocks, loadAllBlocks(test, storage))
+			assert.Equal(test, data.wantSegments, gotSegments)
+		})
+	}
+}
+
+func TestFileStorage_StoreBlock_withError(test *testing.T) {
+	dataCodec := new(MockDataCodec)
+	dataCodec.On("EncodeData", mock.Anything).Return(nil, iotest.ErrTimeout)
+
+	storage, err := NewFileStorage(FileStorageParams{
+		Directory: test.TempDir(),
+		DataCodec: dataCodec,
+	})
+	require.NoError(test, err)
+	defer storage.Close() // nolint: errcheck
+
+	gotErr := storage.StoreBlock(blockchain.Block{
+		Timestamp: clock(),
+		Data:      blockchain.NewData("block #1"),
+		Hash:      "hash #1",
+		PrevHash:  "",
+	})
+
+	mock.AssertExpectationsForObjects(test, dataCodec)
+	assert.Equal(test, blockchain.BlockGroup(nil), loadAllBlocks(test, storage))
+	assert.ErrorIs(test, gotErr, iotest.ErrTimeout)
+}
+
+func TestFileStorage_StoreBlock_withRotationError(test *testing.T) {
+	directory := test.TempDir()
+	storage := openFileStorage(test, directory, mo.Some[int64](50))
+	defer storage.Close() // nolint: errcheck
+
+	blocks := blockchain.BlockGroup{
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+		},
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+	}
+	require.NoError(test, storage.StoreBlock(blocks[1]))
+
+	// the next segment can't be opened as a file
+	nextSegmentPath := filepath.Join(directory, "00000002.segment")
+	require.NoError(test, os.Mkdir(nextSegmentPath, 0o755))
+
+	gotErr := storage.StoreBlock(blocks[0])
+	assert.Error(test, gotErr)
+
+	require.NoError(test, os.Remove(nextSegmentPath))
+
+	gotErr = storage.StoreBlock(blocks[0])
+	assert.NoError(test, gotErr)
+	assert.Equal(test, blocks, loadAllBlocks(test, storage))
+}
+
+func TestFileStorage_DeleteBlockGroup(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		blocks        blockchain.BlockGroup
+		deletedBlocks blockchain.BlockGroup
+		wantBlocks    blockchain.BlockGroup
+	}{
+		{
+			name: "without deleted blocks",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			deletedBlocks: nil,
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+		},
+		{
+			name: "with deleted blocks",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3"),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+			},
+			deletedBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3"),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			directory := test.TempDir()
+			storage := openFileStorage(test, directory, mo.None[int64]())
+			require.NoError(test, storage.StoreBlockGroup(data.blocks))
+
+			gotErr := storage.DeleteBlockGroup(data.deletedBlocks)
+			assert.Equal(test, data.wantBlocks, loadAllBlocks(test, storage))
+			require.NoError(test, storage.Close())
+
+			storage = openFileStorage(test, directory, mo.None[int64]())
+			defer storage.Close() // nolint: errcheck
+
+			assert.Equal(test, data.wantBlocks, loadAllBlocks(test, storage))
+			assert.NoError(test, gotErr)
+		})
+	}
+}
+
+func openFileStorage(
+	test *testing.T,
+	directory string,
+	maxSegmentSize mo.Option[int64],
+) *FileStorage {
+	storage, err := NewFileStorage(FileStorageParams{
+		Directory:      directory,
+		DataCodec:      TextDataCodec{},
+		MaxSegmentSize: maxSegmentSize,
+	})
+	require.NoError(test, err)
+
+	return storage
+}
+
+func loadAllBlocks(
+	test *testing.T,
+	storage blockchain.Loader,
+) blockchain.BlockGroup {
+	blocks, _, err := storage.LoadBlocks(nil, 100)
+	require.NoError(test, err)
+
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	return blocks
+}
+
+func TestFileStorage_LoadBlockByHeight(test *testing.T) {
+	directory := test.TempDir()
+	storage := openFileStorage(test, directory, mo.None[int64]())
+	require.NoError(test, storage.StoreBlockGroup(blockchain.BlockGroup{
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+			Height:    0,
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+			Height:    1,
+		},
+	}))
+	require.NoError(test, storage.Close())
+
+	storage = openFileStorage(test, directory, mo.None[int64]())
+	defer storage.Close() // nolint: errcheck
+
+	gotBlock, gotErr := storage.LoadBlockByHeight(1)
+
+	assert.Equal(test, blockchain.Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      blockchain.NewData("block #2"),
+		Hash:      "hash #2",
+		PrevHash:  "hash #1",
+		Height:    1,
+	}, gotBlock)
+	assert.NoError(test, gotErr)
+
+	_, gotErr = storage.LoadBlockByHash("hash #3")
+
+	assert.ErrorIs(test, gotErr, blockchain.ErrNotFound)
+}
+
+// the negative offset is counted from the end of the file
+func corruptFile(test *testing.T, path string, offset int) {
+	content, err := os.ReadFile(path)
+	require.NoError(test, err)
+
+	if offset < 0 {
+		offset += len(content)
+	}
+	content[offset] ^= 0xff
+
+	require.NoError(test, os.WriteFile(path, content, 0o644))
+}
@@ -2,16 +2,28 @@ package storages
 
 import (
 	"sort"
+	"sync"
 
 	"github.com/thewizardplusplus/go-blockchain"
 	"github.com/thewizardplusplus/go-blockchain/loading/loaders"
 )
 
 // MemoryStorage ...
+//
+// It's safe for concurrent use: even the loading of blocks changes its state,
+// because the sorting and the indexing are performed lazily.
 type MemoryStorage struct {
+	lock sync.Mutex
+
 	blocks    blockchain.BlockGroup
 	lastBlock blockchain.Block
 	isSorted  bool
+
+	// the indices are built lazily and updated on the appending of blocks;
+	// they are reset on the deleting of an indexed block (e.g., on the replacing
+	// of blocks), because the duplicates should be indexed instead
+	heightIndex map[int]blockchain.Block
+	hashIndex   map[string]blockchain.Block
 }
 
 // NewMemoryStorage ...
@@ -32,11 +44,16 @@ func (storage *MemoryStorage) LoadBlocks(cursor interface{}, count int) (
 	nextCursor interface{},
 	err error,
 ) {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
 	storage.sortIfNeed()
 
 	loader := loaders.MemoryLoader(storage.blocks)
-	// the memory loader never returns an error
-	blocks, nextCursor, _ = loader.LoadBlocks(cursor, count) // nolint: gosec
+	blocks, nextCursor, err = loader.LoadBlocks(cursor, count)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	copiedBlocks := make(blockchain.BlockGroup, len(blocks))
 	copy(copiedBlocks, blocks)
@@ -45,7 +62,10 @@ func (storage *MemoryStorage) LoadBlocks(cursor interface{}, count int) (
 }
 
 // LoadLastBlock ...
-func (storage MemoryStorage) LoadLastBlock() (blockchain.Block, error) {
+func (storage *MemoryStorage) LoadLastBlock() (blockchain.Block, error) {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
 	if len(storage.blocks) == 0 {
 		return blockchain.Block{}, blockchain.ErrEmptyStorage
 	}
@@ -55,6 +75,78 @@ func (storage MemoryStorage) LoadLastBlock() (blockchain.Block, error) {
 
 // StoreBlock ...
 func (storage *MemoryStorage) StoreBlock(block blockchain.Block) error {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	storage.storeBlock(block)
+	return nil
+}
+
+// DeleteBlock ...
+func (storage *MemoryStorage) DeleteBlock(block blockchain.Block) error {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	storage.deleteBlock(block)
+	return nil
+}
+
+// ReplaceBlockGroup ...
+//
+// The replacing is atomic, because the memory storage operations never fail.
+func (storage *MemoryStorage) ReplaceBlockGroup(
+	deletedBlocks blockchain.BlockGroup,
+	storedBlocks blockchain.BlockGroup,
+) error {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	for _, block := range deletedBlocks {
+		storage.deleteBlock(block)
+	}
+
+	for _, block := range storedBlocks {
+		storage.storeBlock(block)
+	}
+
+	return nil
+}
+
+// LoadBlockByHeight ...
+func (storage *MemoryStorage) LoadBlockByHeight(
+	height int,
+) (blockchain.Block, error) {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	storage.indexIfNeed()
+
+	block, isFound := storage.heightIndex[height]
+	if !isFound {
+		return blockchain.Block{}, blockchain.ErrNotFound
+	}
+
+	return block, nil
+}
+
+// LoadBlockByHash ...
+func (storage *MemoryStorage) LoadBlockByHash(
+	hash string,
+) (blockchain.Block, error) {
+	storage.lock.Lock()
+	defer storage.lock.Unlock()
+
+	storage.indexIfNeed()
+
+	block, isFound := storage.hashIndex[hash]
+	if !isFound {
+		return blockchain.Block{}, blockchain.ErrNotFound
+	}
+
+	return block, nil
+}
+
+func (storage *MemoryStorage) storeBlock(block blockchain.Block) {
 	// this check should follow before appending the new block
 	if len(storage.blocks) == 0 ||
 		block.Timestamp.After(storage.lastBlock.Timestamp) {
@@ -64,11 +156,12 @@ func (storage *MemoryStorage) StoreBlock(block blockchain.Block) error {
 	storage.blocks = append(storage.blocks, block)
 	storage.isSorted = false
 
-	return nil
+	if storage.isIndexed() {
+		storage.indexBlock(block)
+	}
 }
 
-// DeleteBlock ...
-func (storage *MemoryStorage) DeleteBlock(block blockchain.Block) error {
+func (storage *MemoryStorage) deleteBlock(block blockchain.Block) {
 	storage.sortIfNeed()
 
 	index := sort.Search(len(storage.blocks), func(index int) bool {
@@ -77,18 +170,20 @@ func (storage *MemoryStorage) DeleteBlock(block blockchain.Block) error {
 	})
 	if index == len(storage.blocks) ||
 		storage.blocks[index].IsEqual(block) != nil {
-		return nil
+		return
 	}
 
 	// https://github.com/golang/go/wiki/SliceTricks#delete
 	copiedCount := copy(storage.blocks[index:], storage.blocks[index+1:])
 	storage.blocks = storage.blocks[:index+copiedCount]
 
+	if storage.isIndexed() && storage.isIndexedBlock(block) {
+		storage.resetIndices()
+	}
+
 	if len(storage.blocks) != 0 {
 		storage.lastBlock = storage.blocks[0]
 	}
-
-	return nil
 }
 
 func (storage *MemoryStorage) sortIfNeed() {
@@ -102,3 +197,53 @@ func (storage *MemoryStorage) sortIfNeed() {
 	})
 	storage.isSorted = true
 }
+
+func (storage *MemoryStorage) indexIfNeed() {
+	if storage.isIndexed() {
+		return
+	}
+
+	storage.heightIndex = make(map[int]blockchain.Block, len(storage.blocks))
+	storage.hashIndex = make(map[string]blockchain.Block, len(storage.blocks))
+	for _, block := range storage.blocks {
+		storage.indexBlock(block)
+	}
+}
+
+func (storage *MemoryStorage) isIndexed() bool {
+	return storage.heightIndex != nil && storage.hashIndex != nil
+}
+
+// it prefers the latest block in case of duplicates
+func (storage *MemoryStorage) indexBlock(block blockchain.Block) {
+	indexedBlock, isFound := storage.heightIndex[block.Height]
+	if !isFound || block.Timestamp.After(indexedBlock.Timestamp) {
+		storage.heightIndex[block.Height] = block
+	}
+
+	indexedBlock, isFound = storage.hashIndex[block.Hash]
+	if !isFound || block.Timestamp.After(indexedBlock.Timestamp) {
+		storage.hashIndex[block.Hash] = block
+	}
+}
+
+// it's sufficient to compare the hashes and the timestamps of the blocks,
+// because a false positive result only causes the rebuilding of the indices
+func (storage *MemoryStorage) isIndexedBlock(block blockchain.Block) bool {
+	for _, indexedBlock := range []blockchain.Block{
+		storage.heightIndex[block.Height],
+		storage.hashIndex[block.Hash],
+	} {
+		if indexedBlock.Hash == block.Hash &&
+			indexedBlock.Timestamp.Equal(block.Timestamp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (storage *MemoryStorage) resetIndices() {
+	storage.heightIndex = nil
+	storage.hashIndex = nil
+}
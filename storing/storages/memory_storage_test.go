@@ -1,11 +1,13 @@
 package storages
 
 import (
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/thewizardplusplus/go-blockchain"
 )
 
@@ -1247,6 +1249,78 @@ func TestMemoryStorage_DeleteBlock(test *testing.T) {
 	}
 }
 
+func TestMemoryStorage_ReplaceBlockGroup(test *testing.T) {
+	storage := NewMemoryStorage(blockchain.BlockGroup{
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+		},
+	})
+	err := storage.ReplaceBlockGroup(
+		blockchain.BlockGroup{
+			{
+				Timestamp: clock().Add(time.Hour),
+				Data:      blockchain.NewData("block #2"),
+				Hash:      "hash #2",
+				PrevHash:  "hash #1",
+			},
+		},
+		blockchain.BlockGroup{
+			{
+				Timestamp: clock().Add(2 * time.Hour),
+				Data:      blockchain.NewData("block #3.1"),
+				Hash:      "hash #3.1",
+				PrevHash:  "hash #2.1",
+			},
+			{
+				Timestamp: clock().Add(90 * time.Minute),
+				Data:      blockchain.NewData("block #2.1"),
+				Hash:      "hash #2.1",
+				PrevHash:  "hash #1",
+			},
+		},
+	)
+	require.NoError(test, err)
+
+	gotBlocks, _, _ := storage.LoadBlocks(nil, 10)
+	gotLastBlock, _ := storage.LoadLastBlock()
+
+	assert.Equal(test, blockchain.BlockGroup{
+		{
+			Timestamp: clock().Add(2 * time.Hour),
+			Data:      blockchain.NewData("block #3.1"),
+			Hash:      "hash #3.1",
+			PrevHash:  "hash #2.1",
+		},
+		{
+			Timestamp: clock().Add(90 * time.Minute),
+			Data:      blockchain.NewData("block #2.1"),
+			Hash:      "hash #2.1",
+			PrevHash:  "hash #1",
+		},
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+	}, gotBlocks)
+	assert.Equal(test, blockchain.Block{
+		Timestamp: clock().Add(2 * time.Hour),
+		Data:      blockchain.NewData("block #3.1"),
+		Hash:      "hash #3.1",
+		PrevHash:  "hash #2.1",
+	}, gotLastBlock)
+}
+
 func clock() time.Time {
 	year, month, day := 2006, time.January, 2
 	hour, minute, second := 15, 4, 5
@@ -1257,3 +1331,256 @@ func clock() time.Time {
 		time.UTC, // location
 	)
 }
+
+func TestMemoryStorage_LoadBlockByHeight(test *testing.T) {
+	type args struct {
+		height int
+	}
+
+	for _, data := range []struct {
+		name      string
+		blocks    blockchain.BlockGroup
+		args      args
+		wantBlock blockchain.Block
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+					Height:    0,
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+					Height:    1,
+				},
+			},
+			args: args{
+				height: 1,
+			},
+			wantBlock: blockchain.Block{
+				Timestamp: clock().Add(time.Hour),
+				Data:      new(MockData),
+				Hash:      "hash #2",
+				PrevHash:  "hash #1",
+				Height:    1,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+					Height:    0,
+				},
+			},
+			args: args{
+				height: 1,
+			},
+			wantBlock: blockchain.Block{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrNotFound)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var storage MemoryStorage
+			for _, block := range data.blocks {
+				require.NoError(test, storage.StoreBlock(block))
+			}
+
+			gotBlock, gotErr := storage.LoadBlockByHeight(data.args.height)
+
+			for _, block := range data.blocks {
+				mock.AssertExpectationsForObjects(test, block.Data)
+			}
+			assert.Equal(test, data.wantBlock, gotBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestMemoryStorage_LoadBlockByHash(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name      string
+		blocks    blockchain.BlockGroup
+		args      args
+		wantBlock blockchain.Block
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+					Height:    0,
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      new(MockData),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+					Height:    1,
+				},
+			},
+			args: args{
+				hash: "hash #1",
+			},
+			wantBlock: blockchain.Block{
+				Timestamp: clock(),
+				Data:      new(MockData),
+				Hash:      "hash #1",
+				PrevHash:  "",
+				Height:    0,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      new(MockData),
+					Hash:      "hash #1",
+					PrevHash:  "",
+					Height:    0,
+				},
+			},
+			args: args{
+				hash: "hash #2",
+			},
+			wantBlock: blockchain.Block{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrNotFound)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var storage MemoryStorage
+			for _, block := range data.blocks {
+				require.NoError(test, storage.StoreBlock(block))
+			}
+
+			gotBlock, gotErr := storage.LoadBlockByHash(data.args.hash)
+
+			for _, block := range data.blocks {
+				mock.AssertExpectationsForObjects(test, block.Data)
+			}
+			assert.Equal(test, data.wantBlock, gotBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestMemoryStorage_LoadBlockByHash_afterUpdating(test *testing.T) {
+	var storage MemoryStorage
+	require.NoError(test, storage.StoreBlock(blockchain.Block{
+		Timestamp: clock(),
+		Data:      new(MockData),
+		Hash:      "hash #1",
+		PrevHash:  "",
+		Height:    0,
+	}))
+
+	_, err := storage.LoadBlockByHash("hash #2")
+	require.ErrorIs(test, err, blockchain.ErrNotFound)
+
+	require.NoError(test, storage.StoreBlock(blockchain.Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      new(MockData),
+		Hash:      "hash #2",
+		PrevHash:  "hash #1",
+		Height:    1,
+	}))
+
+	gotBlock, gotErr := storage.LoadBlockByHash("hash #2")
+
+	assert.Equal(test, blockchain.Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      new(MockData),
+		Hash:      "hash #2",
+		PrevHash:  "hash #1",
+		Height:    1,
+	}, gotBlock)
+	assert.NoError(test, gotErr)
+}
+
+func TestMemoryStorage_indices(test *testing.T) {
+	blocks := blockchain.BlockGroup{
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+			Height:    0,
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+			Height:    1,
+		},
+		{
+			Timestamp: clock().Add(2 * time.Hour),
+			Data:      blockchain.NewData("block #3.1"),
+			Hash:      "hash #3.1",
+			PrevHash:  "hash #2",
+			Height:    2,
+		},
+		{
+			Timestamp: clock().Add(3 * time.Hour),
+			Data:      blockchain.NewData("block #3.2"),
+			Hash:      "hash #3.2",
+			PrevHash:  "hash #2",
+			Height:    2,
+		},
+	}
+
+	storage := NewMemoryStorage(slices.Clone(blocks[:2]))
+	_, err := storage.LoadBlockByHeight(0)
+	require.NoError(test, err)
+
+	// the appended blocks are indexed without the rebuilding
+	for _, block := range blocks[2:] {
+		require.NoError(test, storage.StoreBlock(block))
+		require.True(test, storage.isIndexed())
+	}
+
+	gotBlock, err := storage.LoadBlockByHeight(2)
+	require.NoError(test, err)
+	assert.Equal(test, blocks[3], gotBlock)
+
+	gotBlock, err = storage.LoadBlockByHash("hash #3.1")
+	require.NoError(test, err)
+	assert.Equal(test, blocks[2], gotBlock)
+
+	// the indexed block is replaced by its duplicate after the rebuilding
+	require.NoError(test, storage.DeleteBlock(blocks[3]))
+	require.False(test, storage.isIndexed())
+
+	gotBlock, err = storage.LoadBlockByHeight(2)
+	require.NoError(test, err)
+	assert.Equal(test, blocks[2], gotBlock)
+
+	_, err = storage.LoadBlockByHash("hash #3.2")
+	assert.ErrorIs(test, err, blockchain.ErrNotFound)
+}
@@ -0,0 +1,446 @@
+package storages
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// SQLDialect ...
+type SQLDialect int
+
+// ...
+const (
+	SQLiteDialect SQLDialect = iota
+	PostgreSQLDialect
+)
+
+const blockColumns = "timestamp_text, data, hash, prev_hash, height"
+
+type sqlExecutor interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+type sqlScanner interface {
+	Scan(dest ...any) error
+}
+
+// SQLStorageParams ...
+type SQLStorageParams struct {
+	DB        *sql.DB
+	Dialect   SQLDialect
+	DataCodec DataCodec
+}
+
+// SQLCursor ...
+//
+// It points to the last loaded block; the next loading starts
+// from the block that precedes it.
+type SQLCursor struct {
+	TimestampNs int64
+	Hash        string
+}
+
+// SQLStorage ...
+type SQLStorage struct {
+	params SQLStorageParams
+}
+
+// NewSQLStorage ...
+//
+// It migrates the database schema to the latest version.
+func NewSQLStorage(params SQLStorageParams) (*SQLStorage, error) {
+	storage := &SQLStorage{params: params}
+	if err := storage.migrate(); err != nil {
+		return nil, fmt.Errorf("unable to migrate the schema: %w", err)
+	}
+
+	return storage, nil
+}
+
+// LoadBlocks ...
+func (storage *SQLStorage) LoadBlocks(cursor interface{}, count int) (
+	blocks blockchain.BlockGroup,
+	nextCursor interface{},
+	err error,
+) {
+	var rows *sql.Rows
+	if cursor == nil {
+		rows, err = storage.params.DB.Query(
+			"SELECT "+blockColumns+" FROM blocks "+
+				"ORDER BY timestamp_ns DESC, hash DESC "+
+				"LIMIT $1",
+			count,
+		)
+	} else {
+		typedCursor, ok := cursor.(SQLCursor)
+		if !ok {
+			return nil, nil, errors.Join(
+				fmt.Errorf("unsupported cursor type %T", cursor),
+				blockchain.ErrInvalidCursor,
+			)
+		}
+
+		rows, err = storage.params.DB.Query(
+			"SELECT "+blockColumns+" FROM blocks "+
+				"WHERE timestamp_ns < $1 OR (timestamp_ns = $1 AND hash < $2) "+
+				"ORDER BY timestamp_ns DESC, hash DESC "+
+				"LIMIT $3",
+			typedCursor.TimestampNs,
+			typedCursor.Hash,
+			count,
+		)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to query the blocks: %w", err)
+	}
+	defer rows.Close() // nolint: errcheck
+
+	for rows.Next() {
+		block, err := storage.scanBlock(rows)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to scan the block: %w", err)
+		}
+
+		blocks = append(blocks, block)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, fmt.Errorf("unable to iterate over the blocks: %w", err)
+	}
+
+	if len(blocks) == 0 {
+		return nil, cursor, nil
+	}
+
+	lastBlock := blocks[len(blocks)-1]
+	nextCursor = SQLCursor{
+		TimestampNs: lastBlock.Timestamp.UnixNano(),
+		Hash:        lastBlock.Hash,
+	}
+	return blocks, nextCursor, nil
+}
+
+// LoadLastBlock ...
+func (storage *SQLStorage) LoadLastBlock() (blockchain.Block, error) {
+	row := storage.params.DB.QueryRow(
+		"SELECT " + blockColumns + " FROM blocks " +
+			"ORDER BY timestamp_ns DESC, hash DESC " +
+			"LIMIT 1",
+	)
+	block, err := storage.scanBlock(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return blockchain.Block{}, blockchain.ErrEmptyStorage
+		}
+
+		return blockchain.Block{}, fmt.Errorf("unable to scan the block: %w", err)
+	}
+
+	return block, nil
+}
+
+// LoadBlockByHeight ...
+func (storage *SQLStorage) LoadBlockByHeight(
+	height int,
+) (blockchain.Block, error) {
+	return storage.loadBlockBy("height", height)
+}
+
+// LoadBlockByHash ...
+func (storage *SQLStorage) LoadBlockByHash(
+	hash string,
+) (blockchain.Block, error) {
+	return storage.loadBlockBy("hash", hash)
+}
+
+// StoreBlock ...
+func (storage *SQLStorage) StoreBlock(block blockchain.Block) error {
+	if err := storage.storeBlock(storage.params.DB, block); err != nil {
+		return fmt.Errorf("unable to store the block: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBlock ...
+//
+// The block is matched by its timestamp and hash only, so its data
+// isn't encoded, and the block stored with another encoding of the data
+// is deleted too.
+func (storage *SQLStorage) DeleteBlock(block blockchain.Block) error {
+	if err := storage.deleteBlock(storage.params.DB, block); err != nil {
+		return fmt.Errorf("unable to delete the block: %w", err)
+	}
+
+	return nil
+}
+
+// StoreBlockGroup ...
+//
+// The whole block group is stored in a single transaction.
+func (storage *SQLStorage) StoreBlockGroup(blocks blockchain.BlockGroup) error {
+	return storage.inTransaction(func(tx *sql.Tx) error {
+		for index, block := range blocks {
+			if err := storage.storeBlock(tx, block); err != nil {
+				return fmt.Errorf("unable to store block #%d: %w", index, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteBlockGroup ...
+//
+// The whole block group is deleted in a single transaction.
+func (storage *SQLStorage) DeleteBlockGroup(
+	blocks blockchain.BlockGroup,
+) error {
+	return storage.inTransaction(func(tx *sql.Tx) error {
+		for index, block := range blocks {
+			if err := storage.deleteBlock(tx, block); err != nil {
+				return fmt.Errorf("unable to delete block #%d: %w", index, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ReplaceBlockGroup ...
+//
+// The blocks are deleted and stored in a single transaction.
+func (storage *SQLStorage) ReplaceBlockGroup(
+	deletedBlocks blockchain.BlockGroup,
+	storedBlocks blockchain.BlockGroup,
+) error {
+	return storage.inTransaction(func(tx *sql.Tx) error {
+		for index, block := range deletedBlocks {
+			if err := storage.deleteBlock(tx, block); err != nil {
+				return fmt.Errorf("unable to delete block #%d: %w", index, err)
+			}
+		}
+
+		for index, block := range storedBlocks {
+			if err := storage.storeBlock(tx, block); err != nil {
+				return fmt.Errorf("unable to store block #%d: %w", index, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (storage *SQLStorage) loadBlockBy(
+	column string,
+	value any,
+) (blockchain.Block, error) {
+	// prefer the latest block in case of duplicates
+	row := storage.params.DB.QueryRow(
+		"SELECT "+blockColumns+" FROM blocks "+
+			"WHERE "+column+" = $1 "+
+			"ORDER BY timestamp_ns DESC, hash DESC "+
+			"LIMIT 1",
+		value,
+	)
+	block, err := storage.scanBlock(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return blockchain.Block{}, blockchain.ErrNotFound
+		}
+
+		return blockchain.Block{}, fmt.Errorf("unable to scan the block: %w", err)
+	}
+
+	return block, nil
+}
+
+func (storage *SQLStorage) migrate() error {
+	db := storage.params.DB
+	if _, err := db.Exec(
+		"CREATE TABLE IF NOT EXISTS block_schema_migrations " +
+			"(version INTEGER NOT NULL)",
+	); err != nil {
+		return fmt.Errorf("unable to create the migration table: %w", err)
+	}
+
+	var version int
+	if err := db.
+		QueryRow("SELECT COALESCE(MAX(version), 0) FROM block_schema_migrations").
+		Scan(&version); err != nil {
+		return fmt.Errorf("unable to get the schema version: %w", err)
+	}
+
+	migrations := storage.migrations()
+	for ; version < len(migrations); version++ {
+		if err := storage.inTransaction(func(tx *sql.Tx) error {
+			for _, statement := range migrations[version] {
+				if _, err := tx.Exec(statement); err != nil {
+					return fmt.Errorf("unable to execute the statement: %w", err)
+				}
+			}
+
+			if _, err := tx.Exec(
+				"INSERT INTO block_schema_migrations (version) VALUES ($1)",
+				version+1,
+			); err != nil {
+				return fmt.Errorf("unable to update the schema version: %w", err)
+			}
+
+			return nil
+		}); err != nil {
+			return fmt.Errorf("unable to apply migration #%d: %w", version+1, err)
+		}
+	}
+
+	return nil
+}
+
+func (storage *SQLStorage) migrations() [][]string {
+	var binaryType string
+	switch storage.params.Dialect {
+	case SQLiteDialect:
+		binaryType = "BLOB"
+	case PostgreSQLDialect:
+		binaryType = "BYTEA"
+	}
+
+	return [][]string{
+		{
+			"CREATE TABLE blocks (" +
+				"timestamp_ns BIGINT NOT NULL, " +
+				"timestamp_text TEXT NOT NULL, " +
+				"data " + binaryType + " NOT NULL, " +
+				"hash TEXT NOT NULL, " +
+				"prev_hash TEXT NOT NULL" +
+				")",
+			"CREATE INDEX blocks_timestamp_index ON blocks (timestamp_ns, hash)",
+		},
+		{
+			"ALTER TABLE blocks ADD COLUMN height BIGINT NOT NULL DEFAULT 0",
+			"CREATE INDEX blocks_height_index ON blocks (height)",
+			"CREATE INDEX blocks_hash_index ON blocks (hash)",
+		},
+	}
+}
+
+func (storage *SQLStorage) inTransaction(handler func(tx *sql.Tx) error) error {
+	tx, err := storage.params.DB.Begin()
+	if err != nil {
+		return fmt.Errorf("unable to begin the transaction: %w", err)
+	}
+
+	if err := handler(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			err = errors.Join(
+				err,
+				fmt.Errorf("unable to roll back the transaction: %w", rollbackErr),
+			)
+		}
+
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("unable to commit the transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (storage *SQLStorage) storeBlock(
+	executor sqlExecutor,
+	block blockchain.Block,
+) error {
+	timestampText, data, err := storage.encodeBlock(block)
+	if err != nil {
+		return err
+	}
+
+	if _, err := executor.Exec(
+		"INSERT INTO blocks "+
+			"(timestamp_ns, timestamp_text, data, hash, prev_hash, height) "+
+			"VALUES ($1, $2, $3, $4, $5, $6)",
+		block.Timestamp.UnixNano(),
+		timestampText,
+		data,
+		block.Hash,
+		block.PrevHash,
+		block.Height,
+	); err != nil {
+		return fmt.Errorf("unable to insert the block: %w", err)
+	}
+
+	return nil
+}
+
+func (storage *SQLStorage) deleteBlock(
+	executor sqlExecutor,
+	block blockchain.Block,
+) error {
+	if _, err := executor.Exec(
+		"DELETE FROM blocks WHERE timestamp_ns = $1 AND hash = $2",
+		block.Timestamp.UnixNano(),
+		block.Hash,
+	); err != nil {
+		return fmt.Errorf("unable to delete the block: %w", err)
+	}
+
+	return nil
+}
+
+func (storage *SQLStorage) encodeBlock(block blockchain.Block) (
+	timestampText string,
+	data []byte,
+	err error,
+) {
+	rawTimestampText, err := block.Timestamp.MarshalText()
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to encode the timestamp: %w", err)
+	}
+
+	data, err = storage.params.DataCodec.EncodeData(block.Data)
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to encode the data: %w", err)
+	}
+
+	return string(rawTimestampText), data, nil
+}
+
+func (storage *SQLStorage) scanBlock(
+	scanner sqlScanner,
+) (blockchain.Block, error) {
+	var timestampText string
+	var rawData []byte
+	var block blockchain.Block
+	if err := scanner.Scan(
+		&timestampText,
+		&rawData,
+		&block.Hash,
+		&block.PrevHash,
+		&block.Height,
+	); err != nil {
+		return blockchain.Block{}, err
+	}
+
+	timestamp, err := time.Parse(time.RFC3339Nano, timestampText)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf(
+			"unable to decode the timestamp: %w",
+			err,
+		)
+	}
+
+	data, err := storage.params.DataCodec.DecodeData(rawData)
+	if err != nil {
+		return blockchain.Block{}, fmt.Errorf("unable to decode the data: %w", err)
+	}
+
+	block.Timestamp = timestamp
+	block.Data = data
+
+	return block, nil
+}
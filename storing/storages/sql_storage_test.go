@@ -0,0 +1,786 @@
+package storages
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+	_ "modernc.org/sqlite"
+)
+
+func TestNewSQLStorage(test *testing.T) {
+	db := openSQLiteDB(test)
+
+	for i := 0; i < 2; i++ {
+		storage, err := NewSQLStorage(SQLStorageParams{
+			DB:        db,
+			Dialect:   SQLiteDialect,
+			DataCodec: TextDataCodec{},
+		})
+		require.NoError(test, err)
+
+		assert.Equal(test, blockchain.BlockGroup(nil), loadAllBlocks(test, storage))
+	}
+
+	var versions []int
+	rows, err := db.Query("SELECT version FROM block_schema_migrations")
+	require.NoError(test, err)
+	defer rows.Close() // nolint: errcheck
+
+	for rows.Next() {
+		var version int
+		require.NoError(test, rows.Scan(&version))
+
+		versions = append(versions, version)
+	}
+	require.NoError(test, rows.Err())
+
+	assert.Equal(test, []int{1, 2}, versions)
+}
+
+func TestSQLStorage_LoadBlocks(test *testing.T) {
+	blocks := blockchain.BlockGroup{
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+		{
+			Timestamp: clock().Add(2 * time.Hour),
+			Data:      blockchain.NewData("block #3"),
+			Hash:      "hash #3",
+			PrevHash:  "hash #2",
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+		},
+	}
+
+	type args struct {
+		cursor interface{}
+		count  int
+	}
+
+	for _, data := range []struct {
+		name           string
+		args           args
+		wantBlocks     blockchain.BlockGroup
+		wantNextCursor interface{}
+		wantErr        assert.ErrorAssertionFunc
+	}{
+		{
+			name: "without a cursor",
+			args: args{
+				cursor: nil,
+				count:  2,
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3"),
+					Hash:      "hash #3",
+					PrevHash:  "hash #2",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+			},
+			wantNextCursor: SQLCursor{
+				TimestampNs: clock().Add(time.Hour).UnixNano(),
+				Hash:        "hash #2",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "with a cursor",
+			args: args{
+				cursor: SQLCursor{
+					TimestampNs: clock().Add(time.Hour).UnixNano(),
+					Hash:        "hash #2",
+				},
+				count: 2,
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantNextCursor: SQLCursor{
+				TimestampNs: clock().UnixNano(),
+				Hash:        "hash #1",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "with a cursor after the last block",
+			args: args{
+				cursor: SQLCursor{
+					TimestampNs: clock().UnixNano(),
+					Hash:        "hash #1",
+				},
+				count: 2,
+			},
+			wantBlocks: nil,
+			wantNextCursor: SQLCursor{
+				TimestampNs: clock().UnixNano(),
+				Hash:        "hash #1",
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "with a cursor of an unsupported type",
+			args: args{
+				cursor: 23,
+				count:  2,
+			},
+			wantBlocks:     nil,
+			wantNextCursor: nil,
+			wantErr:        assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := openSQLStorage(test, TextDataCodec{})
+			require.NoError(test, storage.StoreBlockGroup(blocks))
+
+			gotBlocks, gotNextCursor, gotErr :=
+				storage.LoadBlocks(data.args.cursor, data.args.count)
+
+			assert.Equal(test, data.wantBlocks, gotBlocks)
+			assert.Equal(test, data.wantNextCursor, gotNextCursor)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestSQLStorage_LoadLastBlock(test *testing.T) {
+	for _, data := range []struct {
+		name          string
+		blocks        blockchain.BlockGroup
+		wantLastBlock blockchain.Block
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name:          "with an empty storage",
+			blocks:        nil,
+			wantLastBlock: blockchain.Block{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrEmptyStorage)
+			},
+		},
+		{
+			name: "with a nonempty storage",
+			blocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantLastBlock: blockchain.Block{
+				Timestamp: clock().Add(time.Hour),
+				Data:      blockchain.NewData("block #2"),
+				Hash:      "hash #2",
+				PrevHash:  "hash #1",
+			},
+			wantErr: assert.NoError,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := openSQLStorage(test, TextDataCodec{})
+			for _, block := range data.blocks {
+				require.NoError(test, storage.StoreBlock(block))
+			}
+
+			gotLastBlock, gotErr := storage.LoadLastBlock()
+
+			assert.Equal(test, data.wantLastBlock, gotLastBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestSQLStorage_LoadBlockByHeight(test *testing.T) {
+	type args struct {
+		height int
+	}
+
+	for _, data := range []struct {
+		name      string
+		args      args
+		wantBlock blockchain.Block
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				height: 1,
+			},
+			wantBlock: blockchain.Block{
+				Timestamp: clock().Add(time.Hour),
+				Data:      blockchain.NewData("block #2"),
+				Hash:      "hash #2",
+				PrevHash:  "hash #1",
+				Height:    1,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				height: 2,
+			},
+			wantBlock: blockchain.Block{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrNotFound)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := openSQLStorage(test, TextDataCodec{})
+			require.NoError(test, storage.StoreBlockGroup(sqlIndexedBlocks()))
+
+			gotBlock, gotErr := storage.LoadBlockByHeight(data.args.height)
+
+			assert.Equal(test, data.wantBlock, gotBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestSQLStorage_LoadBlockByHash(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name      string
+		args      args
+		wantBlock blockchain.Block
+		wantErr   assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				hash: "hash #1",
+			},
+			wantBlock: blockchain.Block{
+				Timestamp: clock(),
+				Data:      blockchain.NewData("block #1"),
+				Hash:      "hash #1",
+				PrevHash:  "",
+				Height:    0,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				hash: "hash #3",
+			},
+			wantBlock: blockchain.Block{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrNotFound)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := openSQLStorage(test, TextDataCodec{})
+			require.NoError(test, storage.StoreBlockGroup(sqlIndexedBlocks()))
+
+			gotBlock, gotErr := storage.LoadBlockByHash(data.args.hash)
+
+			assert.Equal(test, data.wantBlock, gotBlock)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestSQLStorage_DeleteBlock(test *testing.T) {
+	storage := openSQLStorage(test, TextDataCodec{})
+	require.NoError(test, storage.StoreBlockGroup(blockchain.BlockGroup{
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+		},
+	}))
+
+	gotErr := storage.DeleteBlock(blockchain.Block{
+		Timestamp: clock().Add(time.Hour),
+		Data:      blockchain.NewData("block #2"),
+		Hash:      "hash #2",
+		PrevHash:  "hash #1",
+	})
+
+	wantBlocks := blockchain.BlockGroup{
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+		},
+	}
+	assert.Equal(test, wantBlocks, loadAllBlocks(test, storage))
+	assert.NoError(test, gotErr)
+}
+
+func TestSQLStorage_DeleteBlock_withAnotherData(test *testing.T) {
+	storage := openSQLStorage(test, TextDataCodec{})
+	require.NoError(test, storage.StoreBlock(blockchain.Block{
+		Timestamp: clock(),
+		Data:      blockchain.NewData("block #1"),
+		Hash:      "hash #1",
+		PrevHash:  "",
+	}))
+
+	gotErr := storage.DeleteBlock(blockchain.Block{
+		Timestamp: clock(),
+		Data:      blockchain.NewData("another block #1"),
+		Hash:      "hash #1",
+		PrevHash:  "",
+	})
+
+	assert.Equal(test, blockchain.BlockGroup(nil), loadAllBlocks(test, storage))
+	assert.NoError(test, gotErr)
+}
+
+func TestSQLStorage_StoreBlockGroup(test *testing.T) {
+	type args struct {
+		blocks blockchain.BlockGroup
+	}
+
+	for _, data := range []struct {
+		name       string
+		dataCodec  DataCodec
+		args       args
+		wantBlocks blockchain.BlockGroup
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name:      "success",
+			dataCodec: TextDataCodec{},
+			args: args{
+				blocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+				},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error with rolling back",
+			dataCodec: func() DataCodec {
+				dataCodec := new(MockDataCodec)
+				dataCodec.
+					On("EncodeData", blockchain.NewData("block #1")).
+					Return([]byte("block #1"), nil)
+				dataCodec.
+					On("EncodeData", blockchain.NewData("block #2")).
+					Return(nil, iotest.ErrTimeout)
+
+				return dataCodec
+			}(),
+			args: args{
+				blocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+				},
+			},
+			wantBlocks: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			storage := openSQLStorage(test, data.dataCodec)
+			gotErr := storage.StoreBlockGroup(data.args.blocks)
+
+			if dataCodec, ok := data.dataCodec.(*MockDataCodec); ok {
+				mock.AssertExpectationsForObjects(test, dataCodec)
+			}
+			assert.Equal(test, data.wantBlocks, loadAllBlocks(test, storage))
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestSQLStorage_DeleteBlockGroup(test *testing.T) {
+	type args struct {
+		blocks blockchain.BlockGroup
+	}
+
+	for _, data := range []struct {
+		name       string
+		dataCodec  func() DataCodec
+		prepareDB  func(test *testing.T, db *sql.DB)
+		args       args
+		wantBlocks blockchain.BlockGroup
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			dataCodec: func() DataCodec {
+				return TextDataCodec{}
+			},
+			args: args{
+				blocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+				},
+			},
+			wantBlocks: nil,
+			wantErr:    assert.NoError,
+		},
+		{
+			name: "error with rolling back",
+			dataCodec: func() DataCodec {
+				return TextDataCodec{}
+			},
+			prepareDB: func(test *testing.T, db *sql.DB) {
+				_, err := db.Exec(
+					"CREATE TRIGGER abort_deleting BEFORE DELETE ON blocks " +
+						"WHEN OLD.hash = 'hash #2' " +
+						"BEGIN SELECT RAISE(ABORT, 'unable to delete'); END",
+				)
+				require.NoError(test, err)
+			},
+			args: args{
+				blocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock(),
+						Data:      blockchain.NewData("block #1"),
+						Hash:      "hash #1",
+						PrevHash:  "",
+					},
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+				},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorContains(test, err, "unable to delete")
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			dataCodec := data.dataCodec()
+			storage := openSQLStorage(test, dataCodec)
+			require.NoError(test, storage.StoreBlockGroup(blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+			}))
+			if data.prepareDB != nil {
+				data.prepareDB(test, storage.params.DB)
+			}
+
+			gotErr := storage.DeleteBlockGroup(data.args.blocks)
+			gotBlocks := loadAllBlocks(test, storage)
+
+			if mockDataCodec, ok := dataCodec.(*MockDataCodec); ok {
+				mock.AssertExpectationsForObjects(test, mockDataCodec)
+			}
+			assert.Equal(test, data.wantBlocks, gotBlocks)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestSQLStorage_ReplaceBlockGroup(test *testing.T) {
+	type args struct {
+		deletedBlocks blockchain.BlockGroup
+		storedBlocks  blockchain.BlockGroup
+	}
+
+	for _, data := range []struct {
+		name       string
+		dataCodec  func() DataCodec
+		args       args
+		wantBlocks blockchain.BlockGroup
+		wantErr    assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			dataCodec: func() DataCodec {
+				return TextDataCodec{}
+			},
+			args: args{
+				deletedBlocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+				},
+				storedBlocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      blockchain.NewData("block #3.1"),
+						Hash:      "hash #3.1",
+						PrevHash:  "hash #2.1",
+					},
+					{
+						Timestamp: clock().Add(90 * time.Minute),
+						Data:      blockchain.NewData("block #2.1"),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+				},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(2 * time.Hour),
+					Data:      blockchain.NewData("block #3.1"),
+					Hash:      "hash #3.1",
+					PrevHash:  "hash #2.1",
+				},
+				{
+					Timestamp: clock().Add(90 * time.Minute),
+					Data:      blockchain.NewData("block #2.1"),
+					Hash:      "hash #2.1",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error with rolling back",
+			dataCodec: func() DataCodec {
+				dataCodec := new(MockDataCodec)
+				for _, text := range []string{"block #1", "block #2", "block #2.1"} {
+					dataCodec.
+						On("EncodeData", blockchain.NewData(text)).
+						Return([]byte(text), nil)
+				}
+				dataCodec.
+					On("EncodeData", blockchain.NewData("block #3.1")).
+					Return(nil, iotest.ErrTimeout)
+				dataCodec.
+					On("DecodeData", mock.Anything).
+					Return(func(rawData []byte) (blockchain.Data, error) {
+						return blockchain.NewData(string(rawData)), nil
+					})
+
+				return dataCodec
+			},
+			args: args{
+				deletedBlocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock().Add(time.Hour),
+						Data:      blockchain.NewData("block #2"),
+						Hash:      "hash #2",
+						PrevHash:  "hash #1",
+					},
+				},
+				storedBlocks: blockchain.BlockGroup{
+					{
+						Timestamp: clock().Add(90 * time.Minute),
+						Data:      blockchain.NewData("block #2.1"),
+						Hash:      "hash #2.1",
+						PrevHash:  "hash #1",
+					},
+					{
+						Timestamp: clock().Add(2 * time.Hour),
+						Data:      blockchain.NewData("block #3.1"),
+						Hash:      "hash #3.1",
+						PrevHash:  "hash #2.1",
+					},
+				},
+			},
+			wantBlocks: blockchain.BlockGroup{
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			dataCodec := data.dataCodec()
+			storage := openSQLStorage(test, dataCodec)
+			require.NoError(test, storage.StoreBlockGroup(blockchain.BlockGroup{
+				{
+					Timestamp: clock(),
+					Data:      blockchain.NewData("block #1"),
+					Hash:      "hash #1",
+					PrevHash:  "",
+				},
+				{
+					Timestamp: clock().Add(time.Hour),
+					Data:      blockchain.NewData("block #2"),
+					Hash:      "hash #2",
+					PrevHash:  "hash #1",
+				},
+			}))
+
+			gotErr := storage.ReplaceBlockGroup(
+				data.args.deletedBlocks,
+				data.args.storedBlocks,
+			)
+			gotBlocks := loadAllBlocks(test, storage)
+
+			if mockDataCodec, ok := dataCodec.(*MockDataCodec); ok {
+				mock.AssertExpectationsForObjects(test, mockDataCodec)
+			}
+			assert.Equal(test, data.wantBlocks, gotBlocks)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func openSQLiteDB(test *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(test.TempDir(), "blocks.db"))
+	require.NoError(test, err)
+	test.Cleanup(func() { db.Close() }) // nolint: errcheck, gosec
+
+	return db
+}
+
+func openSQLStorage(test *testing.T, dataCodec DataCodec) *SQLStorage {
+	storage, err := NewSQLStorage(SQLStorageParams{
+		DB:        openSQLiteDB(test),
+		Dialect:   SQLiteDialect,
+		DataCodec: dataCodec,
+	})
+	require.NoError(test, err)
+
+	return storage
+}
+
+func sqlIndexedBlocks() blockchain.BlockGroup {
+	return blockchain.BlockGroup{
+		{
+			Timestamp: clock().Add(time.Hour),
+			Data:      blockchain.NewData("block #2"),
+			Hash:      "hash #2",
+			PrevHash:  "hash #1",
+			Height:    1,
+		},
+		{
+			Timestamp: clock(),
+			Data:      blockchain.NewData("block #1"),
+			Hash:      "hash #1",
+			PrevHash:  "",
+			Height:    0,
+		},
+	}
+}
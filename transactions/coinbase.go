@@ -0,0 +1,147 @@
+package transactions
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// CoinbaseDataType ...
+const CoinbaseDataType = "coinbase"
+
+const (
+	coinbasePrefix = "coinbase"
+	coinbaseSize   = ed25519.PublicKeySize + 2*8
+)
+
+// ...
+var (
+	ErrInvalidCoinbase = errors.New("invalid coinbase")
+	ErrExcessReward    = errors.New("excess reward")
+)
+
+// Coinbase ...
+//
+// It's the reward entry that pays the amount to the miner of the block.
+// The height binds it to the block, so each coinbase is unique.
+type Coinbase struct {
+	Receiver ed25519.PublicKey
+	Amount   uint64
+	Height   int
+}
+
+func init() {
+	blockchain.RegisterDataDecoder(
+		CoinbaseDataType,
+		func(rawData []byte) (blockchain.Data, error) {
+			var coinbase Coinbase
+			if err := coinbase.UnmarshalBinary(rawData); err != nil {
+				return nil, err
+			}
+
+			return coinbase, nil
+		},
+	)
+}
+
+// CoinbaseHeight ...
+func (coinbase Coinbase) CoinbaseHeight() int {
+	return coinbase.Height
+}
+
+// String ...
+//
+// It returns "coinbase:<height>:<receiver>:<amount>".
+func (coinbase Coinbase) String() string {
+	coinbaseParts := []string{
+		coinbasePrefix,
+		strconv.Itoa(coinbase.Height),
+		hex.EncodeToString(coinbase.Receiver),
+		strconv.FormatUint(coinbase.Amount, 10),
+	}
+	return strings.Join(coinbaseParts, transferPartSeparator)
+}
+
+// DataType ...
+func (coinbase Coinbase) DataType() string {
+	return CoinbaseDataType
+}
+
+// MarshalBinary ...
+func (coinbase Coinbase) MarshalBinary() ([]byte, error) {
+	if err := coinbase.check(); err != nil {
+		return nil, err
+	}
+
+	buffer := make([]byte, 0, coinbaseSize)
+	buffer = append(buffer, coinbase.Receiver...)
+	buffer = binary.BigEndian.AppendUint64(buffer, coinbase.Amount)
+	buffer = binary.BigEndian.AppendUint64(buffer, uint64(coinbase.Height))
+
+	return buffer, nil
+}
+
+// UnmarshalBinary ...
+func (coinbase *Coinbase) UnmarshalBinary(rawData []byte) error {
+	if len(rawData) != coinbaseSize {
+		return errors.Join(
+			fmt.Errorf("the coinbase size %d is invalid", len(rawData)),
+			ErrInvalidCoinbase,
+		)
+	}
+
+	height := binary.BigEndian.Uint64(rawData[ed25519.PublicKeySize+8:])
+	if height > math.MaxInt {
+		return errors.Join(
+			fmt.Errorf("the height %d is out of range", height),
+			ErrInvalidCoinbase,
+		)
+	}
+
+	*coinbase = Coinbase{
+		Receiver: bytes.Clone(rawData[:ed25519.PublicKeySize]),
+		Amount:   binary.BigEndian.Uint64(rawData[ed25519.PublicKeySize:]),
+		Height:   int(height),
+	}
+	return nil
+}
+
+// Equal ...
+func (coinbase Coinbase) Equal(data blockchain.Data) bool {
+	anotherCoinbase, ok := data.(Coinbase)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(coinbase.Receiver, anotherCoinbase.Receiver) &&
+		coinbase.Amount == anotherCoinbase.Amount &&
+		coinbase.Height == anotherCoinbase.Height
+}
+
+func (coinbase Coinbase) check() error {
+	if len(coinbase.Receiver) != ed25519.PublicKeySize {
+		return errors.Join(
+			fmt.Errorf(
+				"the receiver key size %d is invalid",
+				len(coinbase.Receiver),
+			),
+			ErrInvalidCoinbase,
+		)
+	}
+	if coinbase.Height < 0 {
+		return errors.Join(
+			fmt.Errorf("the height %d is negative", coinbase.Height),
+			ErrInvalidCoinbase,
+		)
+	}
+
+	return nil
+}
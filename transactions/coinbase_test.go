@@ -0,0 +1,192 @@
+package transactions
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestCoinbase_String(test *testing.T) {
+	coinbase := Coinbase{
+		Receiver: ed25519.PublicKey{0x01, 0x02},
+		Amount:   23,
+		Height:   42,
+	}
+	got := coinbase.String()
+
+	assert.Equal(test, "coinbase:42:0102:23", got)
+}
+
+func TestCoinbase_binaryMarshalling(test *testing.T) {
+	coinbase := Coinbase{
+		Receiver: testPublicKey(1),
+		Amount:   23,
+		Height:   42,
+	}
+
+	rawData, err := blockchain.EncodeData(coinbase)
+	require.NoError(test, err)
+
+	got, err := blockchain.DecodeData(rawData)
+	require.NoError(test, err)
+
+	assert.Equal(test, coinbase, got)
+}
+
+func TestCoinbase_MarshalBinary(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		coinbase Coinbase
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			coinbase: Coinbase{
+				Receiver: testPublicKey(1),
+				Amount:   23,
+				Height:   42,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid receiver",
+			coinbase: Coinbase{
+				Receiver: ed25519.PublicKey("invalid"),
+				Amount:   23,
+				Height:   42,
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+		{
+			name: "error/negative height",
+			coinbase: Coinbase{
+				Receiver: testPublicKey(1),
+				Amount:   23,
+				Height:   -1,
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := data.coinbase.MarshalBinary()
+
+			data.wantErr(test, err)
+			if err == nil {
+				assert.Len(test, got, coinbaseSize)
+			}
+		})
+	}
+}
+
+func TestCoinbase_UnmarshalBinary(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		rawData []byte
+		want    Coinbase
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "error/invalid size",
+			rawData: func() []byte {
+				rawData, err := Coinbase{
+					Receiver: testPublicKey(1),
+					Amount:   23,
+					Height:   42,
+				}.MarshalBinary()
+				require.NoError(test, err)
+
+				return rawData[:len(rawData)-1]
+			}(),
+			want: Coinbase{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+		{
+			name: "error/height out of range",
+			rawData: func() []byte {
+				rawData, err := Coinbase{
+					Receiver: testPublicKey(1),
+					Amount:   23,
+				}.MarshalBinary()
+				require.NoError(test, err)
+
+				rawData[len(rawData)-8] = 0x80
+				return rawData
+			}(),
+			want: Coinbase{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var got Coinbase
+			err := got.UnmarshalBinary(data.rawData)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestCoinbase_Equal(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		coinbase     Coinbase
+		anotherData  blockchain.Data
+		wantEquality assert.BoolAssertionFunc
+	}{
+		{
+			name: "equal",
+			coinbase: Coinbase{
+				Receiver: testPublicKey(1),
+				Amount:   23,
+				Height:   42,
+			},
+			anotherData: Coinbase{
+				Receiver: testPublicKey(1),
+				Amount:   23,
+				Height:   42,
+			},
+			wantEquality: assert.True,
+		},
+		{
+			name: "not equal/another coinbase",
+			coinbase: Coinbase{
+				Receiver: testPublicKey(1),
+				Amount:   23,
+				Height:   42,
+			},
+			anotherData: Coinbase{
+				Receiver: testPublicKey(1),
+				Amount:   23,
+				Height:   43,
+			},
+			wantEquality: assert.False,
+		},
+		{
+			name: "not equal/another data type",
+			coinbase: Coinbase{
+				Receiver: testPublicKey(1),
+				Amount:   23,
+				Height:   42,
+			},
+			anotherData:  blockchain.NewData("data"),
+			wantEquality: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.coinbase.Equal(data.anotherData)
+
+			data.wantEquality(test, got)
+		})
+	}
+}
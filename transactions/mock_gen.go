@@ -0,0 +1,35 @@
+package transactions
+
+import (
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+//go:generate mockery --name=Proofer --inpackage --case=underscore --testonly
+
+// Proofer ...
+//
+// It's used only for mock generating.
+//
+type Proofer interface {
+	blockchain.Proofer
+}
+
+//go:generate mockery --name=Loader --inpackage --case=underscore --testonly
+
+// Loader ...
+//
+// It's used only for mock generating.
+//
+type Loader interface {
+	blockchain.Loader
+}
+
+//go:generate mockery --name=AncestryProofer --inpackage --case=underscore --testonly
+
+// AncestryProofer ...
+//
+// It's used only for mock generating.
+//
+type AncestryProofer interface {
+	blockchain.AncestryProofer
+}
@@ -0,0 +1,58 @@
+package transactions
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// RewardSchedule ...
+//
+// The reward for a block is the initial one halved every interval
+// of the block heights. A non-positive interval means no halving.
+type RewardSchedule struct {
+	InitialReward   uint64
+	HalvingInterval int
+}
+
+// Reward ...
+func (schedule RewardSchedule) Reward(height int) uint64 {
+	if schedule.HalvingInterval <= 0 || height < 0 {
+		return schedule.InitialReward
+	}
+
+	// the shift by the uint64 size or more results in zero
+	halvingCount := height / schedule.HalvingInterval
+	return schedule.InitialReward >> halvingCount
+}
+
+// CoinbaseFactory ...
+//
+// It returns the factory of the coinbases paying the full reward
+// to the miner.
+func (schedule RewardSchedule) CoinbaseFactory(
+	miner ed25519.PublicKey,
+) (blockchain.CoinbaseFactory, error) {
+	if len(miner) != ed25519.PublicKeySize {
+		return nil, errors.Join(
+			fmt.Errorf("the miner key size %d is invalid", len(miner)),
+			ErrInvalidCoinbase,
+		)
+	}
+
+	coinbaseFactory := func(height int) (blockchain.CoinbaseData, error) {
+		coinbase := Coinbase{
+			Receiver: miner,
+			Amount:   schedule.Reward(height),
+			Height:   height,
+		}
+		if err := coinbase.check(); err != nil {
+			return nil, err
+		}
+
+		return coinbase, nil
+	}
+	return coinbaseFactory, nil
+}
@@ -0,0 +1,130 @@
+package transactions
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewardSchedule_Reward(test *testing.T) {
+	type fields struct {
+		initialReward   uint64
+		halvingInterval int
+	}
+	type args struct {
+		height int
+	}
+
+	for _, data := range []struct {
+		name   string
+		fields fields
+		args   args
+		want   uint64
+	}{
+		{
+			name: "before the first halving",
+			fields: fields{
+				initialReward:   50,
+				halvingInterval: 10,
+			},
+			args: args{
+				height: 9,
+			},
+			want: 50,
+		},
+		{
+			name: "after the first halving",
+			fields: fields{
+				initialReward:   50,
+				halvingInterval: 10,
+			},
+			args: args{
+				height: 10,
+			},
+			want: 25,
+		},
+		{
+			name: "after several halvings",
+			fields: fields{
+				initialReward:   50,
+				halvingInterval: 10,
+			},
+			args: args{
+				height: 42,
+			},
+			want: 3,
+		},
+		{
+			name: "after all the halvings",
+			fields: fields{
+				initialReward:   50,
+				halvingInterval: 10,
+			},
+			args: args{
+				height: 1000,
+			},
+			want: 0,
+		},
+		{
+			name: "without halving",
+			fields: fields{
+				initialReward:   50,
+				halvingInterval: 0,
+			},
+			args: args{
+				height: 1000,
+			},
+			want: 50,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			schedule := RewardSchedule{
+				InitialReward:   data.fields.initialReward,
+				HalvingInterval: data.fields.halvingInterval,
+			}
+			got := schedule.Reward(data.args.height)
+
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestRewardSchedule_CoinbaseFactory(test *testing.T) {
+	schedule := RewardSchedule{
+		InitialReward:   50,
+		HalvingInterval: 10,
+	}
+
+	test.Run("success", func(test *testing.T) {
+		coinbaseFactory, err := schedule.CoinbaseFactory(testPublicKey(1))
+		require.NoError(test, err)
+
+		coinbase, err := coinbaseFactory(23)
+		require.NoError(test, err)
+
+		wantCoinbase := Coinbase{
+			Receiver: testPublicKey(1),
+			Amount:   12,
+			Height:   23,
+		}
+		assert.Equal(test, wantCoinbase, coinbase)
+		assert.Equal(test, 23, coinbase.CoinbaseHeight())
+	})
+
+	test.Run("error/invalid miner", func(test *testing.T) {
+		_, err := schedule.CoinbaseFactory(ed25519.PublicKey("invalid"))
+
+		assert.ErrorIs(test, err, ErrInvalidCoinbase)
+	})
+
+	test.Run("error/negative height", func(test *testing.T) {
+		coinbaseFactory, err := schedule.CoinbaseFactory(testPublicKey(1))
+		require.NoError(test, err)
+
+		_, err = coinbaseFactory(-1)
+
+		assert.ErrorIs(test, err, ErrInvalidCoinbase)
+	})
+}
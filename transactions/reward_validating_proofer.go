@@ -0,0 +1,143 @@
+package transactions
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// RewardValidatingProofer ...
+//
+// It wraps the proofer and additionally validates the coinbase of the block
+// against the reward schedule, so it's applied by the validation of block
+// groups (see [blockchain.BlockGroup.IsValid]) too.
+//
+// A block may have no coinbase, but no more than one. The coinbase should
+// have the height of the block and shouldn't claim more than the reward
+// for this height. The height of the block is tied to the previous block
+// by [blockchain.Block.IsValid] (except for the blocks
+// in the legacy encoding).
+type RewardValidatingProofer struct {
+	blockchain.Proofer
+
+	Schedule RewardSchedule
+}
+
+// Validate ...
+func (proofer RewardValidatingProofer) Validate(block blockchain.Block) error {
+	if err := proofer.Proofer.Validate(block); err != nil {
+		return fmt.Errorf("unable to validate the block via the proofer: %w", err)
+	}
+
+	return proofer.validateCoinbase(block)
+}
+
+// ValidateWithAncestry ...
+//
+// It passes the ancestry to the wrapped proofer if the latter implements
+// the [blockchain.AncestryProofer] interface, and ignores it otherwise.
+func (proofer RewardValidatingProofer) ValidateWithAncestry(
+	block blockchain.Block,
+	ancestry blockchain.BlockGroup,
+) error {
+	ancestryProofer, ok := proofer.Proofer.(blockchain.AncestryProofer)
+	if !ok {
+		return proofer.Validate(block)
+	}
+
+	if err := ancestryProofer.ValidateWithAncestry(block, ancestry); err != nil {
+		return fmt.Errorf("unable to validate the block via the proofer: %w", err)
+	}
+
+	return proofer.validateCoinbase(block)
+}
+
+// Work ...
+//
+// It delegates to the wrapped proofer if the latter implements
+// the [blockchain.WorkProofer] interface, and returns the difficulty otherwise
+// (like [blockchain.BlockGroup.Work] does).
+func (proofer RewardValidatingProofer) Work(hash string) (*big.Int, error) {
+	if workProofer, ok := proofer.Proofer.(blockchain.WorkProofer); ok {
+		return workProofer.Work(hash)
+	}
+
+	difficulty, err := proofer.Proofer.Difficulty(hash)
+	if err != nil {
+		return nil, err
+	}
+
+	return big.NewInt(int64(difficulty)), nil
+}
+
+// BlockEncoding ...
+//
+// It delegates to the wrapped proofer if the latter implements
+// the [blockchain.EncodingProofer] interface, and detects the encoding
+// by the hash otherwise (see [blockchain.HashBlockEncoding]).
+func (proofer RewardValidatingProofer) BlockEncoding(
+	hash string,
+) blockchain.BlockEncodingVersion {
+	if encodingProofer, ok := proofer.Proofer.(blockchain.EncodingProofer); ok {
+		return encodingProofer.BlockEncoding(hash)
+	}
+
+	return blockchain.HashBlockEncoding(hash)
+}
+
+func (proofer RewardValidatingProofer) validateCoinbase(
+	block blockchain.Block,
+) error {
+	var coinbases []Coinbase
+	for _, item := range blockItems(block.Data) {
+		if coinbase, ok := item.(Coinbase); ok {
+			coinbases = append(coinbases, coinbase)
+		}
+	}
+	if len(coinbases) == 0 {
+		return nil
+	}
+	if len(coinbases) > 1 {
+		return errors.Join(
+			fmt.Errorf("the block has %d coinbases", len(coinbases)),
+			ErrInvalidCoinbase,
+		)
+	}
+
+	coinbase := coinbases[0]
+	if err := coinbase.check(); err != nil {
+		return err
+	}
+	if coinbase.Height != block.Height {
+		return errors.Join(
+			fmt.Errorf(
+				"the coinbase height %d differs from the block height %d",
+				coinbase.Height,
+				block.Height,
+			),
+			ErrInvalidCoinbase,
+		)
+	}
+	if reward := proofer.Schedule.Reward(block.Height); coinbase.Amount > reward {
+		return errors.Join(
+			fmt.Errorf(
+				"the coinbase amount %d exceeds the reward %d",
+				coinbase.Amount,
+				reward,
+			),
+			ErrExcessReward,
+		)
+	}
+
+	return nil
+}
+
+func blockItems(data blockchain.Data) []blockchain.Data {
+	if merkleData, ok := data.(blockchain.MerkleData); ok {
+		return merkleData.Items()
+	}
+
+	return []blockchain.Data{data}
+}
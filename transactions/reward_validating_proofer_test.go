@@ -0,0 +1,448 @@
+package transactions
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+	"github.com/thewizardplusplus/go-blockchain/loading/loaders"
+	"github.com/thewizardplusplus/go-blockchain/proofers"
+	"github.com/thewizardplusplus/go-blockchain/storing"
+	"github.com/thewizardplusplus/go-blockchain/storing/storages"
+)
+
+func TestRewardValidatingProofer_Validate(test *testing.T) {
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/without a coinbase",
+			args: args{
+				block: blockchain.Block{
+					Data:   blockchain.NewData("data"),
+					Height: 23,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a coinbase",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						Coinbase{Receiver: testPublicKey(1), Amount: 12, Height: 23},
+						blockchain.NewData("data"),
+					}),
+					Height: 23,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with a partial reward",
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: testPublicKey(1), Amount: 5, Height: 23},
+					Height: 23,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid block proof",
+			args: args{
+				block: blockchain.Block{
+					Data:   blockchain.NewData("invalid"),
+					Height: 23,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+		{
+			name: "error/several coinbases",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						Coinbase{Receiver: testPublicKey(1), Amount: 12, Height: 23},
+						Coinbase{Receiver: testPublicKey(2), Amount: 12, Height: 23},
+					}),
+					Height: 23,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+		{
+			name: "error/invalid coinbase",
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: []byte("invalid"), Amount: 12, Height: 23},
+					Height: 23,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+		{
+			name: "error/another height",
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: testPublicKey(1), Amount: 12, Height: 22},
+					Height: 23,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+		{
+			name: "error/excess reward",
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: testPublicKey(1), Amount: 13, Height: 23},
+					Height: 23,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrExcessReward)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			innerProofer := new(MockProofer)
+			if data.args.block.Data.Equal(blockchain.NewData("invalid")) {
+				innerProofer.On("Validate", data.args.block).Return(iotest.ErrTimeout)
+			} else {
+				innerProofer.On("Validate", data.args.block).Return(nil)
+			}
+
+			proofer := RewardValidatingProofer{
+				Proofer: innerProofer,
+				Schedule: RewardSchedule{
+					InitialReward:   50,
+					HalvingInterval: 10,
+				},
+			}
+			err := proofer.Validate(data.args.block)
+
+			mock.AssertExpectationsForObjects(test, innerProofer)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestRewardValidatingProofer_ValidateWithAncestry(test *testing.T) {
+	ancestry := blockchain.BlockGroup{{Hash: "hash #22", Height: 22}}
+
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name         string
+		innerProofer func(block blockchain.Block) blockchain.Proofer
+		args         args
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/ancestry proofer",
+			innerProofer: func(block blockchain.Block) blockchain.Proofer {
+				innerProofer := new(MockAncestryProofer)
+				innerProofer.On("ValidateWithAncestry", block, ancestry).Return(nil)
+
+				return innerProofer
+			},
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: testPublicKey(1), Amount: 12, Height: 23},
+					Height: 23,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/regular proofer",
+			innerProofer: func(block blockchain.Block) blockchain.Proofer {
+				innerProofer := new(MockProofer)
+				innerProofer.On("Validate", block).Return(nil)
+
+				return innerProofer
+			},
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: testPublicKey(1), Amount: 12, Height: 23},
+					Height: 23,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid block proof",
+			innerProofer: func(block blockchain.Block) blockchain.Proofer {
+				innerProofer := new(MockAncestryProofer)
+				innerProofer.
+					On("ValidateWithAncestry", block, ancestry).
+					Return(iotest.ErrTimeout)
+
+				return innerProofer
+			},
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: testPublicKey(1), Amount: 12, Height: 23},
+					Height: 23,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+		{
+			name: "error/excess reward",
+			innerProofer: func(block blockchain.Block) blockchain.Proofer {
+				innerProofer := new(MockAncestryProofer)
+				innerProofer.On("ValidateWithAncestry", block, ancestry).Return(nil)
+
+				return innerProofer
+			},
+			args: args{
+				block: blockchain.Block{
+					Data:   Coinbase{Receiver: testPublicKey(1), Amount: 13, Height: 23},
+					Height: 23,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrExcessReward)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			innerProofer := data.innerProofer(data.args.block)
+			proofer := RewardValidatingProofer{
+				Proofer: innerProofer,
+				Schedule: RewardSchedule{
+					InitialReward:   50,
+					HalvingInterval: 10,
+				},
+			}
+			err := proofer.ValidateWithAncestry(data.args.block, ancestry)
+
+			mock.AssertExpectationsForObjects(test, innerProofer)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestRewardValidatingProofer_Work(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name         string
+		innerProofer blockchain.Proofer
+		args         args
+		want         *big.Int
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "success/work proofer",
+			innerProofer: proofers.ProofOfWork{TargetBit: 248},
+			args: args{
+				hash: "v1:248:23:" +
+					"00000000000000000000000000000000" +
+					"00000000000000000000000000000000",
+			},
+			want:    big.NewInt(256),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/regular proofer",
+			innerProofer: func() blockchain.Proofer {
+				innerProofer := new(MockProofer)
+				innerProofer.On("Difficulty", "hash").Return(23, nil)
+
+				return innerProofer
+			}(),
+			args: args{
+				hash: "hash",
+			},
+			want:    big.NewInt(23),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error",
+			innerProofer: func() blockchain.Proofer {
+				innerProofer := new(MockProofer)
+				innerProofer.On("Difficulty", "hash").Return(0, iotest.ErrTimeout)
+
+				return innerProofer
+			}(),
+			args: args{
+				hash: "hash",
+			},
+			want:    nil,
+			wantErr: assert.Error,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := RewardValidatingProofer{Proofer: data.innerProofer}
+			got, err := proofer.Work(data.args.hash)
+
+			if innerProofer, ok := data.innerProofer.(*MockProofer); ok {
+				mock.AssertExpectationsForObjects(test, innerProofer)
+			}
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestRewardValidatingProofer_BlockEncoding(test *testing.T) {
+	type args struct {
+		hash string
+	}
+
+	for _, data := range []struct {
+		name         string
+		innerProofer blockchain.Proofer
+		args         args
+		want         blockchain.BlockEncodingVersion
+	}{
+		{
+			name:         "encoding proofer",
+			innerProofer: proofers.ProofOfAuthority{},
+			args: args{
+				hash: "poa:23:public key:signature",
+			},
+			want: blockchain.BinaryBlockEncodingV1,
+		},
+		{
+			name:         "regular proofer/versioned hash",
+			innerProofer: new(MockProofer),
+			args: args{
+				hash: "v1:hash",
+			},
+			want: blockchain.BinaryBlockEncodingV1,
+		},
+		{
+			name:         "regular proofer/legacy hash",
+			innerProofer: new(MockProofer),
+			args: args{
+				hash: "hash",
+			},
+			want: blockchain.LegacyBlockEncoding,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			proofer := RewardValidatingProofer{Proofer: data.innerProofer}
+			got := proofer.BlockEncoding(data.args.hash)
+
+			if innerProofer, ok := data.innerProofer.(*MockProofer); ok {
+				mock.AssertExpectationsForObjects(test, innerProofer)
+			}
+			assert.Equal(test, data.want, got)
+		})
+	}
+}
+
+func TestRewardValidatingProofer_withBlockchainMerge(test *testing.T) {
+	genesisBlock := blockchain.Block{
+		Timestamp: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		Data:      Coinbase{Receiver: testPublicKey(1), Amount: 50, Height: 0},
+		Hash:      "v1:hash #0",
+	}
+
+	for _, data := range []struct {
+		name         string
+		remoteHeight int
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name:         "success",
+			remoteHeight: 1,
+			wantErr:      assert.NoError,
+		},
+		{
+			name:         "error/height of the genesis block",
+			remoteHeight: 0,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrInvalidHeight)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			// the block claims the full reward of its height
+			remoteBlock := blockchain.Block{
+				Timestamp: genesisBlock.Timestamp.Add(time.Hour),
+				Data: Coinbase{
+					Receiver: testPublicKey(2),
+					Amount:   50 >> data.remoteHeight,
+					Height:   data.remoteHeight,
+				},
+				Hash:     "v1:remote hash #1",
+				PrevHash: "v1:hash #0",
+				Height:   data.remoteHeight,
+			}
+
+			innerProofer := new(MockProofer)
+			innerProofer.
+				On("Validate", mock.AnythingOfType("blockchain.Block")).
+				Return(nil)
+			innerProofer.On("Difficulty", mock.AnythingOfType("string")).Return(1, nil)
+
+			storage := storing.NewGroupStorage(storages.NewMemoryStorage(
+				blockchain.BlockGroup{genesisBlock},
+			))
+			blockchainInstance, err := blockchain.NewBlockchainEx(
+				context.Background(),
+				blockchain.NewBlockchainExParams{
+					Dependencies: blockchain.Dependencies{
+						BlockDependencies: blockchain.BlockDependencies{
+							Proofer: RewardValidatingProofer{
+								Proofer: innerProofer,
+								Schedule: RewardSchedule{
+									InitialReward:   50,
+									HalvingInterval: 1,
+								},
+							},
+						},
+						Storage: storage,
+					},
+				},
+			)
+			require.NoError(test, err)
+
+			err = blockchainInstance.Merge(
+				loaders.MemoryLoader(blockchain.BlockGroup{remoteBlock, genesisBlock}),
+				10,
+			)
+			data.wantErr(test, err)
+
+			wantLastBlock := genesisBlock
+			if err == nil {
+				wantLastBlock = remoteBlock
+			}
+
+			gotLastBlock, err := storage.LoadLastBlock()
+			require.NoError(test, err)
+
+			assert.Equal(test, wantLastBlock, gotLastBlock)
+		})
+	}
+}
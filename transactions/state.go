@@ -0,0 +1,332 @@
+package transactions
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"sync"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// ...
+var (
+	ErrDoubleSpend         = errors.New("double spend")
+	ErrInsufficientBalance = errors.New("insufficient balance")
+)
+
+// the data rejected for these reasons can never be applied, so the block
+// producer can drop it
+func markInvalidData(err error) error {
+	if errors.Is(err, ErrInvalidSignature) || errors.Is(err, ErrDoubleSpend) {
+		return errors.Join(err, blockchain.ErrInvalidData)
+	}
+
+	return err
+}
+
+// Account ...
+type Account struct {
+	Balance uint64
+	// it's the nonce of the last applied transfer of the account
+	Nonce uint64
+}
+
+// State ...
+//
+// It holds the accounts indexed by their public keys and changes them
+// by applying the transfers and the coinbases. They are extracted
+// from the block data directly or from the items of
+// the [blockchain.MerkleData] one; other block data is ignored.
+//
+// The coinbases are credited as is, so their amounts should be validated
+// by the [RewardValidatingProofer].
+//
+// A transfer is rejected if its signature is invalid, if its nonce
+// is already used by the sender (i.e., it's a double spend), or if the sender
+// has an insufficient balance. The changes of a rejected block (group)
+// are discarded entirely.
+//
+// The state remembers the previous versions of the accounts changed by each
+// applied block, so the block can be undone. It implements
+// the [blockchain.ValidatingStateEngine] interface, so
+// the [blockchain.Blockchain] rejects the blocks with invalid transfers
+// and switches the state between the forks on the merging.
+//
+// It's safe for concurrent use.
+type State struct {
+	lock     sync.RWMutex
+	accounts map[string]Account
+	// it maps the hashes of the applied blocks to the previous versions
+	// of the accounts changed by them
+	undoRecords map[string]map[string]Account
+}
+
+// NewState ...
+func NewState() *State {
+	return &State{
+		accounts:    make(map[string]Account),
+		undoRecords: make(map[string]map[string]Account),
+	}
+}
+
+// Clone ...
+func (state *State) Clone() *State {
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+
+	return state.clone()
+}
+
+// Account ...
+func (state *State) Account(publicKey ed25519.PublicKey) Account {
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+
+	return state.accounts[string(publicKey)]
+}
+
+// Credit ...
+//
+// It adds the amount to the balance of the account without a transfer,
+// e.g., for the initial allocation of funds. Such a change can't be undone.
+func (state *State) Credit(publicKey ed25519.PublicKey, amount uint64) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	return state.credit(publicKey, amount)
+}
+
+// ApplyTransfer ...
+//
+// The transfer applied outside a block can't be undone.
+func (state *State) ApplyTransfer(transfer Transfer) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	return state.applyTransfer(transfer)
+}
+
+// ApplyBlock ...
+func (state *State) ApplyBlock(block blockchain.Block) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	nextState := state.clone()
+	if err := nextState.applyBlock(block); err != nil {
+		return err
+	}
+
+	state.accounts = nextState.accounts
+	state.undoRecords = nextState.undoRecords
+
+	return nil
+}
+
+// ApplyBlockGroup ...
+//
+// It replays the blocks from the first one to the last one, i.e.,
+// in reverse to the order of the block group.
+func (state *State) ApplyBlockGroup(blocks blockchain.BlockGroup) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	nextState := state.clone()
+	if err := nextState.applyBlockGroup(blocks); err != nil {
+		return err
+	}
+
+	state.accounts = nextState.accounts
+	state.undoRecords = nextState.undoRecords
+
+	return nil
+}
+
+// ValidateBlock ...
+//
+// It checks that the block can be applied without changing the state.
+func (state *State) ValidateBlock(block blockchain.Block) error {
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+
+	return markInvalidData(state.clone().applyBlock(block))
+}
+
+// ValidateBlockGroup ...
+//
+// It checks that the blocks can be applied without changing the state.
+func (state *State) ValidateBlockGroup(blocks blockchain.BlockGroup) error {
+	state.lock.RLock()
+	defer state.lock.RUnlock()
+
+	return state.clone().applyBlockGroup(blocks)
+}
+
+// UndoBlock ...
+//
+// It reverts the changes of the applied block. The later blocks can change
+// the same accounts, so the blocks should be undone from the last one.
+func (state *State) UndoBlock(block blockchain.Block) error {
+	state.lock.Lock()
+	defer state.lock.Unlock()
+
+	prevAccounts, ok := state.undoRecords[block.Hash]
+	if !ok {
+		return errors.Join(
+			fmt.Errorf("the block %s isn't applied", block.Hash),
+			ErrUnexpectedBlock,
+		)
+	}
+
+	for key, account := range prevAccounts {
+		// the account was absent before the block
+		if account == (Account{}) {
+			delete(state.accounts, key)
+			continue
+		}
+
+		state.accounts[key] = account
+	}
+	delete(state.undoRecords, block.Hash)
+
+	return nil
+}
+
+// it should be called under the lock
+func (state *State) clone() *State {
+	return &State{
+		accounts:    maps.Clone(state.accounts),
+		undoRecords: maps.Clone(state.undoRecords),
+	}
+}
+
+// it should be called under the lock
+func (state *State) credit(publicKey ed25519.PublicKey, amount uint64) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return errors.Join(
+			fmt.Errorf("the key size %d is invalid", len(publicKey)),
+			ErrInvalidTransfer,
+		)
+	}
+
+	account := state.accounts[string(publicKey)]
+	if account.Balance > math.MaxUint64-amount {
+		return errors.Join(
+			errors.New("the balance overflows"),
+			ErrInvalidTransfer,
+		)
+	}
+
+	account.Balance += amount
+	state.accounts[string(publicKey)] = account
+
+	return nil
+}
+
+// it should be called under the lock
+func (state *State) applyTransfer(transfer Transfer) error {
+	if err := transfer.Verify(); err != nil {
+		return fmt.Errorf("unable to verify the transfer: %w", err)
+	}
+
+	sender := state.accounts[string(transfer.Sender)]
+	if transfer.Nonce <= sender.Nonce {
+		return errors.Join(
+			fmt.Errorf("the nonce %d is already used", transfer.Nonce),
+			ErrDoubleSpend,
+		)
+	}
+	if transfer.Nonce != sender.Nonce+1 {
+		return errors.Join(
+			fmt.Errorf(
+				"the nonce %d doesn't follow the previous one %d",
+				transfer.Nonce,
+				sender.Nonce,
+			),
+			ErrInvalidTransfer,
+		)
+	}
+	if sender.Balance < transfer.Amount {
+		return errors.Join(
+			fmt.Errorf(
+				"the amount %d exceeds the balance %d",
+				transfer.Amount,
+				sender.Balance,
+			),
+			ErrInsufficientBalance,
+		)
+	}
+
+	sender.Balance -= transfer.Amount
+	sender.Nonce = transfer.Nonce
+
+	// the sender can be the receiver, so it's loaded after the debiting
+	receiver := sender
+	if !transfer.Sender.Equal(transfer.Receiver) {
+		receiver = state.accounts[string(transfer.Receiver)]
+	}
+	if receiver.Balance > math.MaxUint64-transfer.Amount {
+		return errors.Join(
+			errors.New("the balance of the receiver overflows"),
+			ErrInvalidTransfer,
+		)
+	}
+	receiver.Balance += transfer.Amount
+
+	state.accounts[string(transfer.Sender)] = sender
+	state.accounts[string(transfer.Receiver)] = receiver
+
+	return nil
+}
+
+// it should be called under the lock
+func (state *State) applyBlockGroup(blocks blockchain.BlockGroup) error {
+	for index := len(blocks) - 1; index >= 0; index-- {
+		if err := state.applyBlock(blocks[index]); err != nil {
+			return fmt.Errorf("unable to apply the block #%d: %w", index, err)
+		}
+	}
+
+	return nil
+}
+
+// it should be called under the lock; on a failure, the state is partially
+// changed, so it should be called on a clone
+func (state *State) applyBlock(block blockchain.Block) error {
+	if _, ok := state.undoRecords[block.Hash]; ok {
+		return errors.Join(
+			fmt.Errorf("the block %s is already applied", block.Hash),
+			ErrUnexpectedBlock,
+		)
+	}
+
+	prevAccounts := make(map[string]Account)
+	rememberAccount := func(publicKey ed25519.PublicKey) {
+		if _, ok := prevAccounts[string(publicKey)]; !ok {
+			prevAccounts[string(publicKey)] = state.accounts[string(publicKey)]
+		}
+	}
+
+	for index, item := range blockItems(block.Data) {
+		var err error
+		switch item := item.(type) {
+		case Coinbase:
+			rememberAccount(item.Receiver)
+			err = state.credit(item.Receiver, item.Amount)
+		case Transfer:
+			rememberAccount(item.Sender)
+			rememberAccount(item.Receiver)
+			err = state.applyTransfer(item)
+		default:
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("unable to apply the item #%d: %w", index, err)
+		}
+	}
+
+	state.undoRecords[block.Hash] = prevAccounts
+	return nil
+}
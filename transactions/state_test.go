@@ -0,0 +1,656 @@
+package transactions
+
+import (
+	"context"
+	"crypto/ed25519"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+	"github.com/thewizardplusplus/go-blockchain/loading/loaders"
+	"github.com/thewizardplusplus/go-blockchain/storing"
+	"github.com/thewizardplusplus/go-blockchain/storing/storages"
+)
+
+func TestState_Credit(test *testing.T) {
+	type args struct {
+		publicKey ed25519.PublicKey
+		amount    uint64
+	}
+
+	for _, data := range []struct {
+		name        string
+		args        args
+		wantAccount Account
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				publicKey: testPublicKey(1),
+				amount:    23,
+			},
+			wantAccount: Account{Balance: 65},
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "error/invalid key",
+			args: args{
+				publicKey: ed25519.PublicKey("invalid"),
+				amount:    23,
+			},
+			wantAccount: Account{Balance: 42},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+		{
+			name: "error/balance overflow",
+			args: args{
+				publicKey: testPublicKey(1),
+				amount:    math.MaxUint64,
+			},
+			wantAccount: Account{Balance: 42},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			state := NewState()
+			err := state.Credit(testPublicKey(1), 42)
+			require.NoError(test, err)
+
+			gotErr := state.Credit(data.args.publicKey, data.args.amount)
+
+			assert.Equal(test, data.wantAccount, state.Account(testPublicKey(1)))
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestState_ApplyTransfer(test *testing.T) {
+	type args struct {
+		transfer Transfer
+	}
+
+	for _, data := range []struct {
+		name         string
+		args         args
+		wantSender   Account
+		wantReceiver Account
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/to another account",
+			args: args{
+				transfer: makeTransfer(test, 1, 2, 23, 2),
+			},
+			wantSender:   Account{Balance: 19, Nonce: 2},
+			wantReceiver: Account{Balance: 35},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "success/to the same account",
+			args: args{
+				transfer: makeTransfer(test, 1, 1, 23, 2),
+			},
+			wantSender:   Account{Balance: 42, Nonce: 2},
+			wantReceiver: Account{Balance: 12},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "error/invalid signature",
+			args: args{
+				transfer: func() Transfer {
+					transfer := makeTransfer(test, 1, 2, 23, 2)
+					transfer.Amount = 5
+
+					return transfer
+				}(),
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "error/double spend",
+			args: args{
+				transfer: makeTransfer(test, 1, 2, 23, 1),
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrDoubleSpend)
+			},
+		},
+		{
+			name: "error/nonce gap",
+			args: args{
+				transfer: makeTransfer(test, 1, 2, 23, 3),
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+		{
+			name: "error/insufficient balance",
+			args: args{
+				transfer: makeTransfer(test, 1, 2, 43, 2),
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInsufficientBalance)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			state := makeState(map[byte]Account{
+				1: {Balance: 42, Nonce: 1},
+				2: {Balance: 12},
+			})
+
+			gotErr := state.ApplyTransfer(data.args.transfer)
+
+			assert.Equal(test, data.wantSender, state.Account(testPublicKey(1)))
+			assert.Equal(test, data.wantReceiver, state.Account(testPublicKey(2)))
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestState_ApplyBlock(test *testing.T) {
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name         string
+		args         args
+		wantSender   Account
+		wantReceiver Account
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with a transfer",
+			args: args{
+				block: blockchain.Block{
+					Data: makeTransfer(test, 1, 2, 23, 2),
+				},
+			},
+			wantSender:   Account{Balance: 19, Nonce: 2},
+			wantReceiver: Account{Balance: 35},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "success/with Merkle data",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						makeTransfer(test, 1, 2, 23, 2),
+						blockchain.NewData("data"),
+						makeTransfer(test, 2, 1, 5, 1),
+					}),
+				},
+			},
+			wantSender:   Account{Balance: 24, Nonce: 2},
+			wantReceiver: Account{Balance: 30, Nonce: 1},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "success/with a coinbase",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						Coinbase{Receiver: testPublicKey(2), Amount: 50, Height: 1},
+						makeTransfer(test, 2, 1, 55, 1),
+					}),
+				},
+			},
+			wantSender:   Account{Balance: 97, Nonce: 1},
+			wantReceiver: Account{Balance: 7, Nonce: 1},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "success/without transfers",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewData("data"),
+				},
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "error/double spend within the block",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						makeTransfer(test, 1, 2, 23, 2),
+						makeTransfer(test, 1, 2, 23, 2),
+					}),
+				},
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrDoubleSpend)
+			},
+		},
+		{
+			name: "error/invalid signature",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						makeTransfer(test, 1, 2, 23, 2),
+						func() Transfer {
+							transfer := makeTransfer(test, 2, 1, 5, 1)
+							transfer.Receiver = testPublicKey(3)
+
+							return transfer
+						}(),
+					}),
+				},
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			state := makeState(map[byte]Account{
+				1: {Balance: 42, Nonce: 1},
+				2: {Balance: 12},
+			})
+			validationErr := state.ValidateBlock(data.args.block)
+			gotErr := state.ApplyBlock(data.args.block)
+
+			assert.Equal(test, data.wantSender, state.Account(testPublicKey(1)))
+			assert.Equal(test, data.wantReceiver, state.Account(testPublicKey(2)))
+			data.wantErr(test, validationErr)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestState_ValidateBlock_withInvalidData(test *testing.T) {
+	for _, data := range []struct {
+		name    string
+		block   blockchain.Block
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "double spend",
+			block: blockchain.Block{
+				Data: makeTransfer(test, 1, 2, 23, 1),
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, blockchain.ErrInvalidData)
+			},
+		},
+		{
+			name: "insufficient balance",
+			block: blockchain.Block{
+				Data: makeTransfer(test, 1, 2, 43, 2),
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInsufficientBalance) &&
+					assert.NotErrorIs(test, err, blockchain.ErrInvalidData)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			state := makeState(map[byte]Account{
+				1: {Balance: 42, Nonce: 1},
+				2: {Balance: 12},
+			})
+			err := state.ValidateBlock(data.block)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestState_ApplyBlockGroup(test *testing.T) {
+	type args struct {
+		blocks blockchain.BlockGroup
+	}
+
+	for _, data := range []struct {
+		name         string
+		args         args
+		wantSender   Account
+		wantReceiver Account
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				blocks: blockchain.BlockGroup{
+					{Data: makeTransfer(test, 2, 1, 50, 1), Hash: "hash #2"},
+					{Data: makeTransfer(test, 1, 2, 40, 2), Hash: "hash #1"},
+					{Data: blockchain.NewData("genesis"), Hash: "hash #0"},
+				},
+			},
+			wantSender:   Account{Balance: 52, Nonce: 2},
+			wantReceiver: Account{Balance: 2, Nonce: 1},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "error",
+			args: args{
+				blocks: blockchain.BlockGroup{
+					{Data: makeTransfer(test, 1, 2, 40, 3), Hash: "hash #1"},
+					{Data: makeTransfer(test, 2, 1, 50, 1), Hash: "hash #0"},
+				},
+			},
+			wantSender:   Account{Balance: 42, Nonce: 1},
+			wantReceiver: Account{Balance: 12},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInsufficientBalance)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			state := makeState(map[byte]Account{
+				1: {Balance: 42, Nonce: 1},
+				2: {Balance: 12},
+			})
+			validationErr := state.ValidateBlockGroup(data.args.blocks)
+			gotErr := state.ApplyBlockGroup(data.args.blocks)
+
+			assert.Equal(test, data.wantSender, state.Account(testPublicKey(1)))
+			assert.Equal(test, data.wantReceiver, state.Account(testPublicKey(2)))
+			data.wantErr(test, validationErr)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestState_UndoBlock(test *testing.T) {
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name         string
+		args         args
+		wantSender   Account
+		wantReceiver Account
+		wantErr      assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				block: blockchain.Block{Hash: "hash #1"},
+			},
+			wantSender:   Account{Balance: 30, Nonce: 1},
+			wantReceiver: Account{Balance: 20},
+			wantErr:      assert.NoError,
+		},
+		{
+			name: "error/not applied block",
+			args: args{
+				block: blockchain.Block{Hash: "hash #2"},
+			},
+			wantSender:   Account{Balance: 40, Nonce: 2},
+			wantReceiver: Account{Balance: 10, Nonce: 1},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnexpectedBlock)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			state := NewState()
+			err := state.ApplyBlockGroup(blockchain.BlockGroup{
+				{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						makeTransfer(test, 1, 2, 10, 2),
+						makeTransfer(test, 2, 1, 20, 1),
+					}),
+					Hash: "hash #1",
+				},
+				{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						Coinbase{Receiver: testPublicKey(1), Amount: 50, Height: 0},
+						makeTransfer(test, 1, 2, 20, 1),
+					}),
+					Hash: "hash #0",
+				},
+			})
+			require.NoError(test, err)
+
+			gotErr := state.UndoBlock(data.args.block)
+
+			assert.Equal(test, data.wantSender, state.Account(testPublicKey(1)))
+			assert.Equal(test, data.wantReceiver, state.Account(testPublicKey(2)))
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestState_UndoBlock_reapplying(test *testing.T) {
+	state := NewState()
+	require.NoError(test, state.ApplyBlock(blockchain.Block{
+		Data: Coinbase{Receiver: testPublicKey(1), Amount: 50, Height: 0},
+		Hash: "hash #0",
+	}))
+	require.NoError(test, state.ApplyBlock(blockchain.Block{
+		Data: makeTransfer(test, 1, 2, 20, 1),
+		Hash: "hash #1",
+	}))
+	require.NoError(test, state.UndoBlock(blockchain.Block{Hash: "hash #1"}))
+
+	// the nonce used by the undone block can be used by another one
+	err := state.ApplyBlock(blockchain.Block{
+		Data: makeTransfer(test, 1, 3, 30, 1),
+		Hash: "another hash #1",
+	})
+
+	wantAccounts := map[string]Account{
+		string(testPublicKey(1)): {Balance: 20, Nonce: 1},
+		string(testPublicKey(3)): {Balance: 30},
+	}
+	assert.Equal(test, wantAccounts, state.accounts)
+	assert.NoError(test, err)
+}
+
+func TestState_withBlockchainAddBlockEx(test *testing.T) {
+	blockchainInstance, storage, state := makeStateBlockchain(test)
+
+	err := blockchainInstance.AddBlockEx(
+		context.Background(),
+		func() Transfer {
+			transfer := makeTransfer(test, 2, 1, 5, 1)
+			transfer.Receiver = testPublicKey(3)
+
+			return transfer
+		}(),
+	)
+
+	lastBlock, loadingErr := storage.LoadLastBlock()
+	require.NoError(test, loadingErr)
+
+	wantAccounts := map[string]Account{
+		string(testPublicKey(1)): {Balance: 30, Nonce: 1},
+		string(testPublicKey(2)): {Balance: 20},
+	}
+	assert.Equal(test, "hash #1", lastBlock.Hash)
+	assert.Equal(test, wantAccounts, state.accounts)
+	assert.ErrorIs(test, err, ErrInvalidSignature)
+}
+
+func TestState_withBlockchainMerge(test *testing.T) {
+	type args struct {
+		remoteBlocks blockchain.BlockGroup
+	}
+
+	for _, data := range []struct {
+		name          string
+		args          args
+		wantLastBlock string
+		wantAccounts  map[string]Account
+		wantErr       assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				remoteBlocks: blockchain.BlockGroup{
+					{
+						Data:     makeTransfer(test, 1, 3, 10, 2),
+						Hash:     "another hash #2",
+						PrevHash: "another hash #1",
+						Height:   2,
+					},
+					{
+						Data:     makeTransfer(test, 1, 3, 20, 1),
+						Hash:     "another hash #1",
+						PrevHash: "hash #0",
+						Height:   1,
+					},
+				},
+			},
+			wantLastBlock: "another hash #2",
+			wantAccounts: map[string]Account{
+				string(testPublicKey(1)): {Balance: 20, Nonce: 2},
+				string(testPublicKey(3)): {Balance: 30},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/double spend",
+			args: args{
+				remoteBlocks: blockchain.BlockGroup{
+					{
+						Data:     makeTransfer(test, 1, 3, 20, 1),
+						Hash:     "another hash #2",
+						PrevHash: "another hash #1",
+						Height:   2,
+					},
+					{
+						Data:     makeTransfer(test, 1, 3, 20, 1),
+						Hash:     "another hash #1",
+						PrevHash: "hash #0",
+						Height:   1,
+					},
+				},
+			},
+			wantLastBlock: "hash #1",
+			wantAccounts: map[string]Account{
+				string(testPublicKey(1)): {Balance: 30, Nonce: 1},
+				string(testPublicKey(2)): {Balance: 20},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrDoubleSpend)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			blockchainInstance, storage, state := makeStateBlockchain(test)
+
+			genesisBlock := makeStateGenesisBlock()
+			remoteBlocks := data.args.remoteBlocks
+			for index := range remoteBlocks {
+				remoteBlocks[index].Timestamp = genesisBlock.Timestamp.
+					Add(time.Duration(len(remoteBlocks)-index) * time.Hour)
+			}
+
+			gotErr := blockchainInstance.Merge(
+				loaders.MemoryLoader(append(remoteBlocks, genesisBlock)),
+				10,
+			)
+
+			lastBlock, err := storage.LoadLastBlock()
+			require.NoError(test, err)
+
+			assert.Equal(test, data.wantLastBlock, lastBlock.Hash)
+			assert.Equal(test, data.wantAccounts, state.accounts)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func makeStateBlockchain(test *testing.T) (
+	*blockchain.Blockchain,
+	storing.GroupStorage,
+	*State,
+) {
+	genesisBlock := makeStateGenesisBlock()
+	localBlock := blockchain.Block{
+		Timestamp: genesisBlock.Timestamp.Add(time.Hour),
+		Data:      makeTransfer(test, 1, 2, 20, 1),
+		Hash:      "hash #1",
+		PrevHash:  "hash #0",
+		Height:    1,
+	}
+
+	state := NewState()
+	err := state.ApplyBlockGroup(blockchain.BlockGroup{localBlock, genesisBlock})
+	require.NoError(test, err)
+
+	proofer := new(MockProofer)
+	proofer.
+		On("HashEx", mock.Anything, mock.AnythingOfType("blockchain.Block")).
+		Return("hash #2", nil).
+		Maybe()
+	proofer.
+		On("Validate", mock.AnythingOfType("blockchain.Block")).
+		Return(nil).
+		Maybe()
+	proofer.
+		On("Difficulty", mock.AnythingOfType("string")).
+		Return(1, nil).
+		Maybe()
+
+	storage := storing.NewGroupStorage(storages.NewMemoryStorage(
+		blockchain.BlockGroup{localBlock, genesisBlock},
+	))
+	blockchainInstance, err := blockchain.NewBlockchainEx(
+		context.Background(),
+		blockchain.NewBlockchainExParams{
+			Dependencies: blockchain.Dependencies{
+				BlockDependencies: blockchain.BlockDependencies{
+					Clock: func() time.Time {
+						return genesisBlock.Timestamp.Add(2 * time.Hour)
+					},
+					Proofer: proofer,
+				},
+				Storage:     storage,
+				StateEngine: mo.Some[blockchain.StateEngine](state),
+			},
+		},
+	)
+	require.NoError(test, err)
+
+	return blockchainInstance, storage, state
+}
+
+func makeStateGenesisBlock() blockchain.Block {
+	return blockchain.Block{
+		Timestamp: time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC),
+		Data:      Coinbase{Receiver: testPublicKey(1), Amount: 50, Height: 0},
+		Hash:      "hash #0",
+	}
+}
+
+func makeState(accounts map[byte]Account) *State {
+	state := NewState()
+	for seed, account := range accounts {
+		state.accounts[string(testPublicKey(seed))] = account
+	}
+
+	return state
+}
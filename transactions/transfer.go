@@ -0,0 +1,219 @@
+package transactions
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// TransferDataType ...
+const TransferDataType = "transfer"
+
+const (
+	transferSigningPrefix = "transfer:v1:"
+	transferPartSeparator = ":"
+	transferUnsignedSize  = 2*ed25519.PublicKeySize + 2*8
+	transferSize          = transferUnsignedSize + ed25519.SignatureSize
+)
+
+// ...
+var (
+	ErrInvalidTransfer  = errors.New("invalid transfer")
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Transfer ...
+//
+// It moves the amount from the sender to the receiver. The nonce is
+// the sequence number of the transfer among the ones of the sender,
+// starting from one, so it prevents a replay of the transfer.
+//
+// The signature is made by the private key of the sender and covers
+// all the other fields.
+type Transfer struct {
+	Sender    ed25519.PublicKey
+	Receiver  ed25519.PublicKey
+	Amount    uint64
+	Nonce     uint64
+	Signature []byte
+}
+
+// SignTransfer ...
+//
+// It sets the sender to the public key corresponding to the private key.
+func SignTransfer(
+	privateKey ed25519.PrivateKey,
+	receiver ed25519.PublicKey,
+	amount uint64,
+	nonce uint64,
+) (Transfer, error) {
+	if len(privateKey) != ed25519.PrivateKeySize {
+		return Transfer{}, errors.Join(
+			fmt.Errorf("the private key size %d is invalid", len(privateKey)),
+			ErrInvalidTransfer,
+		)
+	}
+
+	transfer := Transfer{
+		Sender:   privateKey.Public().(ed25519.PublicKey),
+		Receiver: receiver,
+		Amount:   amount,
+		Nonce:    nonce,
+	}
+	if err := transfer.checkKeys(); err != nil {
+		return Transfer{}, err
+	}
+
+	transfer.Signature = ed25519.Sign(privateKey, transfer.signingData())
+	return transfer, nil
+}
+
+func init() {
+	blockchain.RegisterDataDecoder(
+		TransferDataType,
+		func(rawData []byte) (blockchain.Data, error) {
+			var transfer Transfer
+			if err := transfer.UnmarshalBinary(rawData); err != nil {
+				return nil, err
+			}
+
+			return transfer, nil
+		},
+	)
+}
+
+// Verify ...
+//
+// It checks the sizes of the keys and the signature of the sender.
+func (transfer Transfer) Verify() error {
+	if err := transfer.checkKeys(); err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(
+		transfer.Sender,
+		transfer.signingData(),
+		transfer.Signature,
+	) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// String ...
+//
+// It includes all the fields, so the block hash covers the signature too:
+// "<sender>:<receiver>:<amount>:<nonce>:<signature>".
+func (transfer Transfer) String() string {
+	transferParts := []string{
+		hex.EncodeToString(transfer.Sender),
+		hex.EncodeToString(transfer.Receiver),
+		strconv.FormatUint(transfer.Amount, 10),
+		strconv.FormatUint(transfer.Nonce, 10),
+		hex.EncodeToString(transfer.Signature),
+	}
+	return strings.Join(transferParts, transferPartSeparator)
+}
+
+// DataType ...
+func (transfer Transfer) DataType() string {
+	return TransferDataType
+}
+
+// MarshalBinary ...
+func (transfer Transfer) MarshalBinary() ([]byte, error) {
+	if err := transfer.checkKeys(); err != nil {
+		return nil, err
+	}
+	if len(transfer.Signature) != ed25519.SignatureSize {
+		return nil, errors.Join(
+			fmt.Errorf(
+				"the signature size %d is invalid",
+				len(transfer.Signature),
+			),
+			ErrInvalidTransfer,
+		)
+	}
+
+	buffer := make([]byte, 0, transferSize)
+	buffer = transfer.appendUnsignedData(buffer)
+	buffer = append(buffer, transfer.Signature...)
+
+	return buffer, nil
+}
+
+// UnmarshalBinary ...
+func (transfer *Transfer) UnmarshalBinary(rawData []byte) error {
+	if len(rawData) != transferSize {
+		return errors.Join(
+			fmt.Errorf("the transfer size %d is invalid", len(rawData)),
+			ErrInvalidTransfer,
+		)
+	}
+
+	rawData = bytes.Clone(rawData)
+	*transfer = Transfer{
+		Sender:    rawData[:ed25519.PublicKeySize],
+		Receiver:  rawData[ed25519.PublicKeySize : 2*ed25519.PublicKeySize],
+		Amount:    binary.BigEndian.Uint64(rawData[2*ed25519.PublicKeySize:]),
+		Nonce:     binary.BigEndian.Uint64(rawData[2*ed25519.PublicKeySize+8:]),
+		Signature: rawData[transferUnsignedSize:],
+	}
+	return nil
+}
+
+// Equal ...
+func (transfer Transfer) Equal(data blockchain.Data) bool {
+	anotherTransfer, ok := data.(Transfer)
+	if !ok {
+		return false
+	}
+
+	return bytes.Equal(transfer.Sender, anotherTransfer.Sender) &&
+		bytes.Equal(transfer.Receiver, anotherTransfer.Receiver) &&
+		transfer.Amount == anotherTransfer.Amount &&
+		transfer.Nonce == anotherTransfer.Nonce &&
+		bytes.Equal(transfer.Signature, anotherTransfer.Signature)
+}
+
+func (transfer Transfer) checkKeys() error {
+	if len(transfer.Sender) != ed25519.PublicKeySize {
+		return errors.Join(
+			fmt.Errorf("the sender key size %d is invalid", len(transfer.Sender)),
+			ErrInvalidTransfer,
+		)
+	}
+	if len(transfer.Receiver) != ed25519.PublicKeySize {
+		return errors.Join(
+			fmt.Errorf(
+				"the receiver key size %d is invalid",
+				len(transfer.Receiver),
+			),
+			ErrInvalidTransfer,
+		)
+	}
+
+	return nil
+}
+
+func (transfer Transfer) signingData() []byte {
+	buffer := make([]byte, 0, len(transferSigningPrefix)+transferUnsignedSize)
+	buffer = append(buffer, transferSigningPrefix...)
+	return transfer.appendUnsignedData(buffer)
+}
+
+func (transfer Transfer) appendUnsignedData(buffer []byte) []byte {
+	buffer = append(buffer, transfer.Sender...)
+	buffer = append(buffer, transfer.Receiver...)
+	buffer = binary.BigEndian.AppendUint64(buffer, transfer.Amount)
+	buffer = binary.BigEndian.AppendUint64(buffer, transfer.Nonce)
+	return buffer
+}
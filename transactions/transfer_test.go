@@ -0,0 +1,303 @@
+package transactions
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestSignTransfer(test *testing.T) {
+	type args struct {
+		privateKey ed25519.PrivateKey
+		receiver   ed25519.PublicKey
+		amount     uint64
+		nonce      uint64
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				privateKey: testPrivateKey(1),
+				receiver:   testPublicKey(2),
+				amount:     23,
+				nonce:      1,
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid private key",
+			args: args{
+				privateKey: ed25519.PrivateKey("invalid"),
+				receiver:   testPublicKey(2),
+				amount:     23,
+				nonce:      1,
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+		{
+			name: "error/invalid receiver",
+			args: args{
+				privateKey: testPrivateKey(1),
+				receiver:   ed25519.PublicKey("invalid"),
+				amount:     23,
+				nonce:      1,
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := SignTransfer(
+				data.args.privateKey,
+				data.args.receiver,
+				data.args.amount,
+				data.args.nonce,
+			)
+
+			data.wantErr(test, err)
+			if err == nil {
+				assert.Equal(test, testPublicKey(1), got.Sender)
+				assert.Equal(test, data.args.receiver, got.Receiver)
+				assert.Equal(test, data.args.amount, got.Amount)
+				assert.Equal(test, data.args.nonce, got.Nonce)
+				assert.NoError(test, got.Verify())
+			}
+		})
+	}
+}
+
+func TestTransfer_Verify(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		transfer Transfer
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "success",
+			transfer: makeTransfer(test, 1, 2, 23, 1),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "error/invalid sender",
+			transfer: func() Transfer {
+				transfer := makeTransfer(test, 1, 2, 23, 1)
+				transfer.Sender = ed25519.PublicKey("invalid")
+
+				return transfer
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+		{
+			name: "error/changed amount",
+			transfer: func() Transfer {
+				transfer := makeTransfer(test, 1, 2, 23, 1)
+				transfer.Amount = 42
+
+				return transfer
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "error/signature of another key",
+			transfer: func() Transfer {
+				transfer := makeTransfer(test, 1, 2, 23, 1)
+				transfer.Sender = testPublicKey(3)
+
+				return transfer
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := data.transfer.Verify()
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestTransfer_String(test *testing.T) {
+	transfer := Transfer{
+		Sender:    ed25519.PublicKey{0x01, 0x02},
+		Receiver:  ed25519.PublicKey{0x03, 0x04},
+		Amount:    23,
+		Nonce:     42,
+		Signature: []byte{0x05, 0x06},
+	}
+	got := transfer.String()
+
+	assert.Equal(test, "0102:0304:23:42:0506", got)
+}
+
+func TestTransfer_binaryMarshalling(test *testing.T) {
+	transfer := makeTransfer(test, 1, 2, 23, 1)
+
+	rawData, err := blockchain.EncodeData(transfer)
+	require.NoError(test, err)
+
+	got, err := blockchain.DecodeData(rawData)
+	require.NoError(test, err)
+
+	assert.Equal(test, transfer, got)
+}
+
+func TestTransfer_MarshalBinary(test *testing.T) {
+	for _, data := range []struct {
+		name     string
+		transfer Transfer
+		wantErr  assert.ErrorAssertionFunc
+	}{
+		{
+			name:     "success",
+			transfer: makeTransfer(test, 1, 2, 23, 1),
+			wantErr:  assert.NoError,
+		},
+		{
+			name: "error/invalid receiver",
+			transfer: func() Transfer {
+				transfer := makeTransfer(test, 1, 2, 23, 1)
+				transfer.Receiver = ed25519.PublicKey("invalid")
+
+				return transfer
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+		{
+			name: "error/invalid signature",
+			transfer: func() Transfer {
+				transfer := makeTransfer(test, 1, 2, 23, 1)
+				transfer.Signature = []byte("invalid")
+
+				return transfer
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := data.transfer.MarshalBinary()
+
+			data.wantErr(test, err)
+			if err == nil {
+				assert.Len(test, got, transferSize)
+			}
+		})
+	}
+}
+
+func TestTransfer_UnmarshalBinary(test *testing.T) {
+	transfer := makeTransfer(test, 1, 2, 23, 1)
+	rawData, err := transfer.MarshalBinary()
+	require.NoError(test, err)
+
+	for _, data := range []struct {
+		name    string
+		rawData []byte
+		want    Transfer
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name:    "success",
+			rawData: rawData,
+			want:    transfer,
+			wantErr: assert.NoError,
+		},
+		{
+			name:    "error",
+			rawData: rawData[:len(rawData)-1],
+			want:    Transfer{},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidTransfer)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var got Transfer
+			err := got.UnmarshalBinary(data.rawData)
+
+			assert.Equal(test, data.want, got)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestTransfer_Equal(test *testing.T) {
+	for _, data := range []struct {
+		name         string
+		transfer     Transfer
+		anotherData  blockchain.Data
+		wantEquality assert.BoolAssertionFunc
+	}{
+		{
+			name:         "equal",
+			transfer:     makeTransfer(test, 1, 2, 23, 1),
+			anotherData:  makeTransfer(test, 1, 2, 23, 1),
+			wantEquality: assert.True,
+		},
+		{
+			name:         "not equal/another transfer",
+			transfer:     makeTransfer(test, 1, 2, 23, 1),
+			anotherData:  makeTransfer(test, 1, 2, 23, 2),
+			wantEquality: assert.False,
+		},
+		{
+			name:         "not equal/another data type",
+			transfer:     makeTransfer(test, 1, 2, 23, 1),
+			anotherData:  blockchain.NewData("data"),
+			wantEquality: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := data.transfer.Equal(data.anotherData)
+
+			data.wantEquality(test, got)
+		})
+	}
+}
+
+func testPrivateKey(seed byte) ed25519.PrivateKey {
+	return ed25519.NewKeyFromSeed(bytes.Repeat([]byte{seed}, ed25519.SeedSize))
+}
+
+func testPublicKey(seed byte) ed25519.PublicKey {
+	return testPrivateKey(seed).Public().(ed25519.PublicKey)
+}
+
+func makeTransfer(
+	test *testing.T,
+	senderSeed byte,
+	receiverSeed byte,
+	amount uint64,
+	nonce uint64,
+) Transfer {
+	transfer, err := SignTransfer(
+		testPrivateKey(senderSeed),
+		testPublicKey(receiverSeed),
+		amount,
+		nonce,
+	)
+	require.NoError(test, err)
+
+	return transfer
+}
@@ -0,0 +1,358 @@
+package transactions
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"sync"
+
+	"github.com/samber/mo"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// ...
+var (
+	ErrUnknownOutput   = errors.New("unknown output")
+	ErrUnexpectedBlock = errors.New("unexpected block")
+)
+
+// UTXOSet ...
+//
+// It indexes the unspent outputs by their points. The outputs are created
+// by the coinbases and the UTXO transactions, which are extracted
+// from the block data directly or from the items of
+// the [blockchain.MerkleData] one; other block data is ignored.
+// The outputs of a block are numbered from zero in the order of these items
+// (a coinbase creates a single output).
+//
+// A block is rejected if its transaction spends an unknown output, an output
+// spent already (i.e., it's a double spend, including the one within
+// the block), or if the signature of an input is invalid.
+// The changes of a rejected block are discarded entirely.
+//
+// The set remembers the outputs spent by each applied block, so the block
+// can be undone. It implements the [blockchain.ValidatingStateEngine]
+// interface, so the [blockchain.Blockchain] can switch it between the forks
+// on the merging, and the [blockchain.BlockProducer] can drop the rejected
+// pending transactions.
+//
+// It's safe for concurrent use.
+type UTXOSet struct {
+	lock    sync.RWMutex
+	outputs map[OutputPoint]UTXOOutput
+	// it maps the spent outputs to the hashes of the spending blocks
+	spentOutputs map[OutputPoint]string
+	// it maps the hashes of the applied blocks to the outputs spent by them
+	undoRecords map[string]map[OutputPoint]UTXOOutput
+}
+
+// NewUTXOSet ...
+func NewUTXOSet() *UTXOSet {
+	return &UTXOSet{
+		outputs:      make(map[OutputPoint]UTXOOutput),
+		spentOutputs: make(map[OutputPoint]string),
+		undoRecords:  make(map[string]map[OutputPoint]UTXOOutput),
+	}
+}
+
+// NewUTXOSetFromLoader ...
+//
+// It rebuilds the set by scanning all the blocks of the loader
+// (e.g., of a blockchain) and applying them from the first one.
+func NewUTXOSetFromLoader(
+	loader blockchain.Loader,
+	chunkSize int,
+) (*UTXOSet, error) {
+	var blockChunks []blockchain.BlockGroup
+	var cursor interface{}
+	for {
+		blocks, nextCursor, err := loader.LoadBlocks(cursor, chunkSize)
+		if err != nil {
+			const message = "unable to load the blocks corresponding to cursor %v: %w"
+			return nil, fmt.Errorf(message, cursor, err)
+		}
+		if len(blocks) == 0 {
+			break
+		}
+
+		blockChunks = append(blockChunks, blocks)
+		cursor = nextCursor
+	}
+
+	set := NewUTXOSet()
+	for index := len(blockChunks) - 1; index >= 0; index-- {
+		blocks := blockChunks[index]
+		for blockIndex := len(blocks) - 1; blockIndex >= 0; blockIndex-- {
+			if err := set.ApplyBlock(blocks[blockIndex]); err != nil {
+				return nil, fmt.Errorf(
+					"unable to apply the block %s: %w",
+					blocks[blockIndex].Hash,
+					err,
+				)
+			}
+		}
+	}
+
+	return set, nil
+}
+
+// Len ...
+func (set *UTXOSet) Len() int {
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+
+	return len(set.outputs)
+}
+
+// Output ...
+func (set *UTXOSet) Output(point OutputPoint) mo.Option[UTXOOutput] {
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+
+	output, ok := set.outputs[point]
+	return mo.TupleToOption(output, ok)
+}
+
+// UnspentOutputs ...
+//
+// It returns the unspent outputs of the receiver.
+func (set *UTXOSet) UnspentOutputs(
+	receiver ed25519.PublicKey,
+) map[OutputPoint]UTXOOutput {
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+
+	outputs := make(map[OutputPoint]UTXOOutput)
+	for point, output := range set.outputs {
+		if bytes.Equal(output.Receiver, receiver) {
+			outputs[point] = output
+		}
+	}
+
+	return outputs
+}
+
+// ApplyBlock ...
+func (set *UTXOSet) ApplyBlock(block blockchain.Block) error {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+
+	spentOutputs, createdOutputs, err := set.checkBlock(block)
+	if err != nil {
+		return err
+	}
+
+	for point := range spentOutputs {
+		delete(set.outputs, point)
+		set.spentOutputs[point] = block.Hash
+	}
+	maps.Copy(set.outputs, createdOutputs)
+	set.undoRecords[block.Hash] = spentOutputs
+
+	return nil
+}
+
+// ValidateBlock ...
+//
+// It checks that the block can be applied without changing the set.
+func (set *UTXOSet) ValidateBlock(block blockchain.Block) error {
+	set.lock.RLock()
+	defer set.lock.RUnlock()
+
+	_, _, err := set.checkBlock(block)
+	return markInvalidData(err)
+}
+
+// UndoBlock ...
+//
+// It reverts the changes of the applied block. The outputs created
+// by the block should be unspent, so the blocks should be undone
+// from the last one.
+func (set *UTXOSet) UndoBlock(block blockchain.Block) error {
+	set.lock.Lock()
+	defer set.lock.Unlock()
+
+	spentOutputs, ok := set.undoRecords[block.Hash]
+	if !ok {
+		return errors.Join(
+			fmt.Errorf("the block %s isn't applied", block.Hash),
+			ErrUnexpectedBlock,
+		)
+	}
+
+	createdOutputs, err := blockOutputs(block)
+	if err != nil {
+		return err
+	}
+	for point := range createdOutputs {
+		if spendingBlockHash, ok := set.spentOutputs[point]; ok {
+			return errors.Join(
+				fmt.Errorf(
+					"the output %s is spent by the block %s",
+					point,
+					spendingBlockHash,
+				),
+				ErrUnexpectedBlock,
+			)
+		}
+	}
+
+	for point := range createdOutputs {
+		delete(set.outputs, point)
+	}
+	for point, output := range spentOutputs {
+		set.outputs[point] = output
+		delete(set.spentOutputs, point)
+	}
+	delete(set.undoRecords, block.Hash)
+
+	return nil
+}
+
+// it should be called under the lock
+func (set *UTXOSet) checkBlock(block blockchain.Block) (
+	spentOutputs map[OutputPoint]UTXOOutput,
+	createdOutputs map[OutputPoint]UTXOOutput,
+	err error,
+) {
+	if _, ok := set.undoRecords[block.Hash]; ok {
+		return nil, nil, errors.Join(
+			fmt.Errorf("the block %s is already applied", block.Hash),
+			ErrUnexpectedBlock,
+		)
+	}
+
+	spentOutputs = make(map[OutputPoint]UTXOOutput)
+	for index, item := range blockItems(block.Data) {
+		transaction, ok := item.(UTXOTransaction)
+		if !ok {
+			continue
+		}
+
+		if err := set.checkTransaction(transaction, spentOutputs); err != nil {
+			return nil, nil, fmt.Errorf("unable to apply the item #%d: %w", index, err)
+		}
+	}
+
+	createdOutputs, err = blockOutputs(block)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return spentOutputs, createdOutputs, nil
+}
+
+// it should be called under the lock; it adds the outputs spent
+// by the transaction to the specified ones
+func (set *UTXOSet) checkTransaction(
+	transaction UTXOTransaction,
+	spentOutputs map[OutputPoint]UTXOOutput,
+) error {
+	if err := transaction.checkStructure(); err != nil {
+		return err
+	}
+
+	var inputAmount uint64
+	for index, input := range transaction.Inputs {
+		point := input.PreviousOutput
+		if _, ok := spentOutputs[point]; ok {
+			return errors.Join(
+				fmt.Errorf("the output %s is already spent within the block", point),
+				ErrDoubleSpend,
+			)
+		}
+		if spendingBlockHash, ok := set.spentOutputs[point]; ok {
+			return errors.Join(
+				fmt.Errorf(
+					"the output %s is already spent by the block %s",
+					point,
+					spendingBlockHash,
+				),
+				ErrDoubleSpend,
+			)
+		}
+
+		output, ok := set.outputs[point]
+		if !ok {
+			return errors.Join(
+				fmt.Errorf("the output %s is unknown", point),
+				ErrUnknownOutput,
+			)
+		}
+
+		if err := transaction.VerifyInput(index, output.Receiver); err != nil {
+			return fmt.Errorf("unable to verify the input #%d: %w", index, err)
+		}
+
+		if inputAmount > math.MaxUint64-output.Amount {
+			return errors.Join(
+				errors.New("the input amount overflows"),
+				ErrInvalidUTXOTransaction,
+			)
+		}
+
+		inputAmount += output.Amount
+		spentOutputs[point] = output
+	}
+
+	outputAmount, err := sumOutputs(transaction.Outputs)
+	if err != nil {
+		return err
+	}
+	if outputAmount > inputAmount {
+		return errors.Join(
+			fmt.Errorf(
+				"the output amount %d exceeds the input amount %d",
+				outputAmount,
+				inputAmount,
+			),
+			ErrInsufficientBalance,
+		)
+	}
+
+	return nil
+}
+
+func blockOutputs(block blockchain.Block) (map[OutputPoint]UTXOOutput, error) {
+	outputs := make(map[OutputPoint]UTXOOutput)
+	addOutput := func(output UTXOOutput) {
+		point := OutputPoint{BlockHash: block.Hash, Index: len(outputs)}
+		outputs[point] = output
+	}
+
+	for index, item := range blockItems(block.Data) {
+		switch item := item.(type) {
+		case Coinbase:
+			if err := item.check(); err != nil {
+				return nil, fmt.Errorf("unable to apply the item #%d: %w", index, err)
+			}
+
+			addOutput(UTXOOutput{Receiver: item.Receiver, Amount: item.Amount})
+		case UTXOTransaction:
+			for _, output := range item.Outputs {
+				addOutput(output)
+			}
+		}
+	}
+
+	return outputs, nil
+}
+
+func sumOutputs(outputs []UTXOOutput) (uint64, error) {
+	var amount uint64
+	for _, output := range outputs {
+		if amount > math.MaxUint64-output.Amount {
+			return 0, errors.Join(
+				errors.New("the output amount overflows"),
+				ErrInvalidUTXOTransaction,
+			)
+		}
+
+		amount += output.Amount
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,681 @@
+package transactions
+
+import (
+	"context"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+	"github.com/thewizardplusplus/go-blockchain/loading/loaders"
+	"github.com/thewizardplusplus/go-blockchain/storing"
+	"github.com/thewizardplusplus/go-blockchain/storing/storages"
+)
+
+func TestNewUTXOSetFromLoader(test *testing.T) {
+	type args struct {
+		loader    blockchain.Loader
+		chunkSize int
+	}
+
+	for _, data := range []struct {
+		name        string
+		args        args
+		wantOutputs map[OutputPoint]UTXOOutput
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				loader: loaders.MemoryLoader(
+					blockchain.BlockGroup{makeUTXOBlock(test), makeUTXOGenesisBlock()},
+				),
+				chunkSize: 1,
+			},
+			wantOutputs: map[OutputPoint]UTXOOutput{
+				{BlockHash: "hash #1", Index: 0}: {
+					Receiver: testPublicKey(2),
+					Amount:   30,
+				},
+				{BlockHash: "hash #1", Index: 1}: {
+					Receiver: testPublicKey(1),
+					Amount:   15,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/empty loader",
+			args: args{
+				loader:    loaders.MemoryLoader(nil),
+				chunkSize: 1,
+			},
+			wantOutputs: map[OutputPoint]UTXOOutput{},
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "error/unable to load the blocks",
+			args: args{
+				loader: func() blockchain.Loader {
+					loader := new(MockLoader)
+					loader.On("LoadBlocks", nil, 23).Return(nil, nil, iotest.ErrTimeout)
+
+					return loader
+				}(),
+				chunkSize: 23,
+			},
+			wantOutputs: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, iotest.ErrTimeout)
+			},
+		},
+		{
+			name: "error/unable to apply the block",
+			args: args{
+				loader: loaders.MemoryLoader(
+					blockchain.BlockGroup{makeUTXOBlock(test)},
+				),
+				chunkSize: 1,
+			},
+			wantOutputs: nil,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnknownOutput)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := NewUTXOSetFromLoader(data.args.loader, data.args.chunkSize)
+
+			if loader, ok := data.args.loader.(*MockLoader); ok {
+				mock.AssertExpectationsForObjects(test, loader)
+			}
+			if data.wantOutputs != nil {
+				require.NotNil(test, got)
+				assert.Equal(test, data.wantOutputs, got.outputs)
+			} else {
+				assert.Nil(test, got)
+			}
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestUTXOSet_Output(test *testing.T) {
+	set := NewUTXOSet()
+	require.NoError(test, set.ApplyBlock(makeUTXOGenesisBlock()))
+
+	got := set.Output(OutputPoint{BlockHash: "hash #0", Index: 0})
+	assert.Equal(test, mo.Some(UTXOOutput{
+		Receiver: testPublicKey(1),
+		Amount:   50,
+	}), got)
+
+	got = set.Output(OutputPoint{BlockHash: "hash #0", Index: 1})
+	assert.Equal(test, mo.None[UTXOOutput](), got)
+}
+
+func TestUTXOSet_UnspentOutputs(test *testing.T) {
+	set := NewUTXOSet()
+	require.NoError(test, set.ApplyBlock(makeUTXOGenesisBlock()))
+	require.NoError(test, set.ApplyBlock(makeUTXOBlock(test)))
+
+	got := set.UnspentOutputs(testPublicKey(1))
+
+	want := map[OutputPoint]UTXOOutput{
+		{BlockHash: "hash #1", Index: 1}: {
+			Receiver: testPublicKey(1),
+			Amount:   15,
+		},
+	}
+	assert.Equal(test, want, got)
+	assert.Equal(test, 2, set.Len())
+}
+
+func TestUTXOSet_ApplyBlock(test *testing.T) {
+	type args struct {
+		block blockchain.Block
+	}
+
+	genesisOutputs := map[OutputPoint]UTXOOutput{
+		{BlockHash: "hash #0", Index: 0}: {
+			Receiver: testPublicKey(1),
+			Amount:   50,
+		},
+		{BlockHash: "hash #0", Index: 1}: {
+			Receiver: testPublicKey(2),
+			Amount:   10,
+		},
+	}
+
+	for _, data := range []struct {
+		name        string
+		args        args
+		wantOutputs map[OutputPoint]UTXOOutput
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success/with a transaction",
+			args: args{
+				block: blockchain.Block{
+					Data: makeUTXOTransaction(
+						test,
+						[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+						[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 45}},
+						1,
+					),
+					Hash: "hash #1",
+				},
+			},
+			wantOutputs: map[OutputPoint]UTXOOutput{
+				{BlockHash: "hash #0", Index: 1}: {
+					Receiver: testPublicKey(2),
+					Amount:   10,
+				},
+				{BlockHash: "hash #1", Index: 0}: {
+					Receiver: testPublicKey(2),
+					Amount:   45,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "success/with Merkle data",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						Coinbase{Receiver: testPublicKey(3), Amount: 50, Height: 1},
+						blockchain.NewData("data"),
+						makeUTXOTransaction(
+							test,
+							[]OutputPoint{
+								{BlockHash: "hash #0", Index: 0},
+								{BlockHash: "hash #0", Index: 1},
+							},
+							[]UTXOOutput{
+								{Receiver: testPublicKey(3), Amount: 40},
+								{Receiver: testPublicKey(1), Amount: 20},
+							},
+							1,
+							2,
+						),
+					}),
+					Hash: "hash #1",
+				},
+			},
+			wantOutputs: map[OutputPoint]UTXOOutput{
+				{BlockHash: "hash #1", Index: 0}: {
+					Receiver: testPublicKey(3),
+					Amount:   50,
+				},
+				{BlockHash: "hash #1", Index: 1}: {
+					Receiver: testPublicKey(3),
+					Amount:   40,
+				},
+				{BlockHash: "hash #1", Index: 2}: {
+					Receiver: testPublicKey(1),
+					Amount:   20,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/already applied block",
+			args: args{
+				block: makeUTXOGenesisBlock(),
+			},
+			wantOutputs: genesisOutputs,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnexpectedBlock)
+			},
+		},
+		{
+			name: "error/unknown output",
+			args: args{
+				block: blockchain.Block{
+					Data: makeUTXOTransaction(
+						test,
+						[]OutputPoint{{BlockHash: "hash #0", Index: 2}},
+						[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 5}},
+						1,
+					),
+					Hash: "hash #1",
+				},
+			},
+			wantOutputs: genesisOutputs,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnknownOutput)
+			},
+		},
+		{
+			name: "error/double spend within the block",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewMerkleData([]blockchain.Data{
+						makeUTXOTransaction(
+							test,
+							[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+							[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 50}},
+							1,
+						),
+						makeUTXOTransaction(
+							test,
+							[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+							[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 50}},
+							1,
+						),
+					}),
+					Hash: "hash #1",
+				},
+			},
+			wantOutputs: genesisOutputs,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrDoubleSpend)
+			},
+		},
+		{
+			name: "error/invalid signature",
+			args: args{
+				block: blockchain.Block{
+					Data: makeUTXOTransaction(
+						test,
+						[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+						[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 50}},
+						2,
+					),
+					Hash: "hash #1",
+				},
+			},
+			wantOutputs: genesisOutputs,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "error/excess amount",
+			args: args{
+				block: blockchain.Block{
+					Data: makeUTXOTransaction(
+						test,
+						[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+						[]UTXOOutput{
+							{Receiver: testPublicKey(2), Amount: 30},
+							{Receiver: testPublicKey(3), Amount: 30},
+						},
+						1,
+					),
+					Hash: "hash #1",
+				},
+			},
+			wantOutputs: genesisOutputs,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInsufficientBalance)
+			},
+		},
+		{
+			name: "error/invalid coinbase",
+			args: args{
+				block: blockchain.Block{
+					Data: Coinbase{Receiver: []byte("invalid"), Amount: 50, Height: 1},
+					Hash: "hash #1",
+				},
+			},
+			wantOutputs: genesisOutputs,
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidCoinbase)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			set := NewUTXOSet()
+			require.NoError(test, set.ApplyBlock(blockchain.Block{
+				Data: blockchain.NewMerkleData([]blockchain.Data{
+					Coinbase{Receiver: testPublicKey(1), Amount: 50},
+					Coinbase{Receiver: testPublicKey(2), Amount: 10},
+				}),
+				Hash: "hash #0",
+			}))
+
+			validationErr := set.ValidateBlock(data.args.block)
+			gotErr := set.ApplyBlock(data.args.block)
+
+			assert.Equal(test, data.wantOutputs, set.outputs)
+			data.wantErr(test, validationErr)
+			data.wantErr(test, gotErr)
+		})
+	}
+}
+
+func TestUTXOSet_ApplyBlock_withDoubleSpend(test *testing.T) {
+	set := NewUTXOSet()
+	require.NoError(test, set.ApplyBlock(makeUTXOGenesisBlock()))
+	require.NoError(test, set.ApplyBlock(makeUTXOBlock(test)))
+
+	err := set.ApplyBlock(blockchain.Block{
+		Data: makeUTXOTransaction(
+			test,
+			[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+			[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 50}},
+			1,
+		),
+		Hash:     "hash #2",
+		PrevHash: "hash #1",
+		Height:   2,
+	})
+
+	assert.ErrorIs(test, err, ErrDoubleSpend)
+}
+
+func TestUTXOSet_ValidateBlock_withDoubleSpend(test *testing.T) {
+	set := NewUTXOSet()
+	require.NoError(test, set.ApplyBlock(makeUTXOGenesisBlock()))
+	require.NoError(test, set.ApplyBlock(makeUTXOBlock(test)))
+
+	err := set.ValidateBlock(blockchain.Block{
+		Data: makeUTXOTransaction(
+			test,
+			[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+			[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 50}},
+			1,
+		),
+		Hash:     "hash #2",
+		PrevHash: "hash #1",
+		Height:   2,
+	})
+
+	assert.ErrorIs(test, err, ErrDoubleSpend)
+	assert.ErrorIs(test, err, blockchain.ErrInvalidData)
+}
+
+func TestUTXOSet_UndoBlock(test *testing.T) {
+	type args struct {
+		block blockchain.Block
+	}
+
+	for _, data := range []struct {
+		name        string
+		args        args
+		wantOutputs map[OutputPoint]UTXOOutput
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				block: makeUTXOBlock(test),
+			},
+			wantOutputs: map[OutputPoint]UTXOOutput{
+				{BlockHash: "hash #0", Index: 0}: {
+					Receiver: testPublicKey(1),
+					Amount:   50,
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/not applied block",
+			args: args{
+				block: blockchain.Block{
+					Data: blockchain.NewData("data"),
+					Hash: "hash #2",
+				},
+			},
+			wantOutputs: map[OutputPoint]UTXOOutput{
+				{BlockHash: "hash #1", Index: 0}: {
+					Receiver: testPublicKey(2),
+					Amount:   30,
+				},
+				{BlockHash: "hash #1", Index: 1}: {
+					Receiver: testPublicKey(1),
+					Amount:   15,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnexpectedBlock)
+			},
+		},
+		{
+			name: "error/block with spent outputs",
+			args: args{
+				block: makeUTXOGenesisBlock(),
+			},
+			wantOutputs: map[OutputPoint]UTXOOutput{
+				{BlockHash: "hash #1", Index: 0}: {
+					Receiver: testPublicKey(2),
+					Amount:   30,
+				},
+				{BlockHash: "hash #1", Index: 1}: {
+					Receiver: testPublicKey(1),
+					Amount:   15,
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrUnexpectedBlock)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			set := NewUTXOSet()
+			require.NoError(test, set.ApplyBlock(makeUTXOGenesisBlock()))
+			require.NoError(test, set.ApplyBlock(makeUTXOBlock(test)))
+
+			err := set.UndoBlock(data.args.block)
+
+			assert.Equal(test, data.wantOutputs, set.outputs)
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestUTXOSet_UndoBlock_reapplying(test *testing.T) {
+	set := NewUTXOSet()
+	require.NoError(test, set.ApplyBlock(makeUTXOGenesisBlock()))
+	require.NoError(test, set.ApplyBlock(makeUTXOBlock(test)))
+	require.NoError(test, set.UndoBlock(makeUTXOBlock(test)))
+
+	// the output spent by the undone block can be spent by another one
+	err := set.ApplyBlock(blockchain.Block{
+		Data: makeUTXOTransaction(
+			test,
+			[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+			[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 50}},
+			1,
+		),
+		Hash:     "another hash #1",
+		PrevHash: "hash #0",
+		Height:   1,
+	})
+
+	wantOutputs := map[OutputPoint]UTXOOutput{
+		{BlockHash: "another hash #1", Index: 0}: {
+			Receiver: testPublicKey(3),
+			Amount:   50,
+		},
+	}
+	assert.Equal(test, wantOutputs, set.outputs)
+	assert.NoError(test, err)
+}
+
+func TestUTXOSet_withBlockchainMerge(test *testing.T) {
+	genesisBlock := makeUTXOGenesisBlock()
+	genesisBlock.Timestamp =
+		time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	localBlock := makeUTXOBlock(test)
+	localBlock.Timestamp = genesisBlock.Timestamp.Add(time.Hour)
+
+	remoteBlocks := blockchain.BlockGroup{
+		{
+			Timestamp: genesisBlock.Timestamp.Add(2 * time.Hour),
+			Data:      Coinbase{Receiver: testPublicKey(3), Amount: 10, Height: 2},
+			Hash:      "another hash #2",
+			PrevHash:  "another hash #1",
+			Height:    2,
+		},
+		{
+			Timestamp: genesisBlock.Timestamp.Add(time.Hour),
+			Data: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 50}},
+				1,
+			),
+			Hash:     "another hash #1",
+			PrevHash: "hash #0",
+			Height:   1,
+		},
+	}
+
+	storage := storing.NewGroupStorage(storages.NewMemoryStorage(
+		blockchain.BlockGroup{localBlock, genesisBlock},
+	))
+	set, err := NewUTXOSetFromLoader(storage, 10)
+	require.NoError(test, err)
+
+	proofer := new(MockProofer)
+	proofer.On("Validate", mock.AnythingOfType("blockchain.Block")).Return(nil)
+	proofer.On("Difficulty", mock.AnythingOfType("string")).Return(1, nil)
+
+	blockchainInstance, err := blockchain.NewBlockchainEx(
+		context.Background(),
+		blockchain.NewBlockchainExParams{
+			Dependencies: blockchain.Dependencies{
+				BlockDependencies: blockchain.BlockDependencies{
+					Proofer: proofer,
+				},
+				Storage:     storage,
+				StateEngine: mo.Some[blockchain.StateEngine](set),
+			},
+		},
+	)
+	require.NoError(test, err)
+
+	err = blockchainInstance.Merge(
+		loaders.MemoryLoader(append(remoteBlocks, genesisBlock)),
+		10,
+	)
+	require.NoError(test, err)
+
+	wantOutputs := map[OutputPoint]UTXOOutput{
+		{BlockHash: "another hash #1", Index: 0}: {
+			Receiver: testPublicKey(3),
+			Amount:   50,
+		},
+		{BlockHash: "another hash #2", Index: 0}: {
+			Receiver: testPublicKey(3),
+			Amount:   10,
+		},
+	}
+	assert.Equal(test, wantOutputs, set.outputs)
+}
+
+func TestUTXOSet_withBlockProducer(test *testing.T) {
+	genesisBlock := makeUTXOGenesisBlock()
+	genesisBlock.Timestamp =
+		time.Date(2006, time.January, 2, 15, 4, 5, 0, time.UTC)
+
+	localBlock := makeUTXOBlock(test)
+	localBlock.Timestamp = genesisBlock.Timestamp.Add(time.Hour)
+
+	storage := storing.NewGroupStorage(storages.NewMemoryStorage(
+		blockchain.BlockGroup{localBlock, genesisBlock},
+	))
+	set, err := NewUTXOSetFromLoader(storage, 10)
+	require.NoError(test, err)
+
+	proofer := new(MockProofer)
+	proofer.
+		On("HashEx", mock.Anything, mock.AnythingOfType("blockchain.Block")).
+		Return("hash #2", nil)
+
+	blockchainInstance, err := blockchain.NewBlockchainEx(
+		context.Background(),
+		blockchain.NewBlockchainExParams{
+			Dependencies: blockchain.Dependencies{
+				BlockDependencies: blockchain.BlockDependencies{
+					Clock: func() time.Time {
+						return genesisBlock.Timestamp.Add(2 * time.Hour)
+					},
+					Proofer: proofer,
+				},
+				Storage:     storage,
+				StateEngine: mo.Some[blockchain.StateEngine](set),
+			},
+		},
+	)
+	require.NoError(test, err)
+
+	mempool, err := blockchain.NewMempool(blockchain.MempoolParams{})
+	require.NoError(test, err)
+
+	validTransactions := []blockchain.Data{
+		makeUTXOTransaction(
+			test,
+			[]OutputPoint{{BlockHash: "hash #1", Index: 1}},
+			[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 15}},
+			1,
+		),
+		makeUTXOTransaction(
+			test,
+			[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+			[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 30}},
+			2,
+		),
+	}
+	// the output is already spent by the stored block
+	doubleSpend := makeUTXOTransaction(
+		test,
+		[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+		[]UTXOOutput{{Receiver: testPublicKey(3), Amount: 50}},
+		1,
+	)
+	for _, transaction := range []blockchain.Data{
+		validTransactions[0],
+		doubleSpend,
+		validTransactions[1],
+	} {
+		require.NoError(test, mempool.Add(transaction))
+	}
+
+	producer, err := blockchain.NewBlockProducer(blockchain.BlockProducerParams{
+		Blockchain: blockchainInstance,
+		Mempool:    mempool,
+	})
+	require.NoError(test, err)
+
+	err = producer.ProduceBlock(context.Background())
+	require.NoError(test, err)
+
+	lastBlock, err := storage.LoadLastBlock()
+	require.NoError(test, err)
+
+	assert.Equal(test, "hash #2", lastBlock.Hash)
+	assert.Equal(test, blockchain.NewMerkleData(validTransactions), lastBlock.Data)
+	assert.Empty(test, mempool.Pending(0))
+}
+
+func makeUTXOGenesisBlock() blockchain.Block {
+	return blockchain.Block{
+		Data: Coinbase{Receiver: testPublicKey(1), Amount: 50, Height: 0},
+		Hash: "hash #0",
+	}
+}
+
+func makeUTXOBlock(test *testing.T) blockchain.Block {
+	return blockchain.Block{
+		Data: makeUTXOTransaction(
+			test,
+			[]OutputPoint{{BlockHash: "hash #0", Index: 0}},
+			[]UTXOOutput{
+				{Receiver: testPublicKey(2), Amount: 30},
+				{Receiver: testPublicKey(1), Amount: 15},
+			},
+			1,
+		),
+		Hash:     "hash #1",
+		PrevHash: "hash #0",
+		Height:   1,
+	}
+}
@@ -0,0 +1,442 @@
+package transactions
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+// UTXOTransactionDataType ...
+const UTXOTransactionDataType = "utxo_transaction"
+
+const (
+	utxoTransactionSigningPrefix  = "utxo_transaction:v1:"
+	utxoTransactionPartSeparator  = ":"
+	utxoTransactionItemSeparator  = ","
+	utxoTransactionFieldSeparator = "/"
+
+	// the block hash length, the block hash, the output index and the signature
+	utxoInputMinimalSize = 1 + 8 + ed25519.SignatureSize
+	utxoOutputSize       = ed25519.PublicKeySize + 8
+)
+
+// ErrInvalidUTXOTransaction ...
+var ErrInvalidUTXOTransaction = errors.New("invalid UTXO transaction")
+
+// OutputPoint ...
+//
+// It references the output by the hash of its block and its index among
+// all the outputs of the block. The outputs are numbered in the order
+// of the coinbases and the UTXO transactions in the block data
+// (see [UTXOSet]).
+type OutputPoint struct {
+	BlockHash string
+	Index     int
+}
+
+// String ...
+func (point OutputPoint) String() string {
+	return point.BlockHash +
+		utxoTransactionFieldSeparator +
+		strconv.Itoa(point.Index)
+}
+
+// UTXOInput ...
+//
+// It spends the previous output. The signature is made by the private key
+// of the receiver of the previous output and covers the whole transaction
+// except for the signatures.
+type UTXOInput struct {
+	PreviousOutput OutputPoint
+	Signature      []byte
+}
+
+// UTXOOutput ...
+type UTXOOutput struct {
+	Receiver ed25519.PublicKey
+	Amount   uint64
+}
+
+// UTXOTransaction ...
+//
+// It spends the previous outputs by its inputs and creates the new outputs.
+// The amount of the outputs can't exceed the one of the spent outputs;
+// the rest is burned.
+type UTXOTransaction struct {
+	Inputs  []UTXOInput
+	Outputs []UTXOOutput
+}
+
+// SignUTXOTransaction ...
+//
+// The input with some index is signed by the private key with the same index.
+func SignUTXOTransaction(
+	previousOutputs []OutputPoint,
+	outputs []UTXOOutput,
+	privateKeys []ed25519.PrivateKey,
+) (UTXOTransaction, error) {
+	if len(privateKeys) != len(previousOutputs) {
+		return UTXOTransaction{}, errors.Join(
+			fmt.Errorf(
+				"the private key count %d differs from the input count %d",
+				len(privateKeys),
+				len(previousOutputs),
+			),
+			ErrInvalidUTXOTransaction,
+		)
+	}
+
+	transaction := UTXOTransaction{
+		Inputs:  make([]UTXOInput, 0, len(previousOutputs)),
+		Outputs: outputs,
+	}
+	for _, previousOutput := range previousOutputs {
+		transaction.Inputs = append(
+			transaction.Inputs,
+			UTXOInput{PreviousOutput: previousOutput},
+		)
+	}
+	if err := transaction.checkStructure(); err != nil {
+		return UTXOTransaction{}, err
+	}
+
+	signingData := transaction.signingData()
+	for index, privateKey := range privateKeys {
+		if len(privateKey) != ed25519.PrivateKeySize {
+			return UTXOTransaction{}, errors.Join(
+				fmt.Errorf(
+					"the private key #%d size %d is invalid",
+					index,
+					len(privateKey),
+				),
+				ErrInvalidUTXOTransaction,
+			)
+		}
+
+		transaction.Inputs[index].Signature =
+			ed25519.Sign(privateKey, signingData)
+	}
+
+	return transaction, nil
+}
+
+func init() {
+	blockchain.RegisterDataDecoder(
+		UTXOTransactionDataType,
+		func(rawData []byte) (blockchain.Data, error) {
+			var transaction UTXOTransaction
+			if err := transaction.UnmarshalBinary(rawData); err != nil {
+				return nil, err
+			}
+
+			return transaction, nil
+		},
+	)
+}
+
+// VerifyInput ...
+//
+// It checks the signature of the input with the specified index
+// by the receiver of the output spent by this input.
+func (transaction UTXOTransaction) VerifyInput(
+	index int,
+	owner ed25519.PublicKey,
+) error {
+	if index < 0 || index >= len(transaction.Inputs) {
+		return errors.Join(
+			fmt.Errorf("the input #%d is absent", index),
+			ErrInvalidUTXOTransaction,
+		)
+	}
+	if len(owner) != ed25519.PublicKeySize {
+		return errors.Join(
+			fmt.Errorf("the owner key size %d is invalid", len(owner)),
+			ErrInvalidUTXOTransaction,
+		)
+	}
+
+	if !ed25519.Verify(
+		owner,
+		transaction.signingData(),
+		transaction.Inputs[index].Signature,
+	) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// String ...
+//
+// It includes all the fields, so the block hash covers the signatures too:
+// "utxo:<inputs>:<outputs>", where the inputs are
+// "<block hash>/<index>/<signature>" and the outputs are
+// "<receiver>/<amount>", both separated by commas.
+func (transaction UTXOTransaction) String() string {
+	inputParts := make([]string, 0, len(transaction.Inputs))
+	for _, input := range transaction.Inputs {
+		inputParts = append(inputParts, strings.Join([]string{
+			input.PreviousOutput.String(),
+			hex.EncodeToString(input.Signature),
+		}, utxoTransactionFieldSeparator))
+	}
+
+	outputParts := make([]string, 0, len(transaction.Outputs))
+	for _, output := range transaction.Outputs {
+		outputParts = append(outputParts, strings.Join([]string{
+			hex.EncodeToString(output.Receiver),
+			strconv.FormatUint(output.Amount, 10),
+		}, utxoTransactionFieldSeparator))
+	}
+
+	transactionParts := []string{
+		"utxo",
+		strings.Join(inputParts, utxoTransactionItemSeparator),
+		strings.Join(outputParts, utxoTransactionItemSeparator),
+	}
+	return strings.Join(transactionParts, utxoTransactionPartSeparator)
+}
+
+// DataType ...
+func (transaction UTXOTransaction) DataType() string {
+	return UTXOTransactionDataType
+}
+
+// MarshalBinary ...
+func (transaction UTXOTransaction) MarshalBinary() ([]byte, error) {
+	if err := transaction.checkStructure(); err != nil {
+		return nil, err
+	}
+	for index, input := range transaction.Inputs {
+		if len(input.Signature) != ed25519.SignatureSize {
+			return nil, errors.Join(
+				fmt.Errorf(
+					"the signature size %d of the input #%d is invalid",
+					len(input.Signature),
+					index,
+				),
+				ErrInvalidUTXOTransaction,
+			)
+		}
+	}
+
+	return transaction.appendData(nil, true), nil
+}
+
+// UnmarshalBinary ...
+func (transaction *UTXOTransaction) UnmarshalBinary(rawData []byte) error {
+	decoder := utxoTransactionDecoder{rawData: bytes.Clone(rawData)}
+
+	inputCount := decoder.readCount(utxoInputMinimalSize)
+	inputs := make([]UTXOInput, 0, inputCount)
+	for range inputCount {
+		blockHash := decoder.readBytes(int(decoder.readCount(1)))
+		index := decoder.readUint64()
+		if decoder.err == nil && index > math.MaxInt {
+			decoder.err = fmt.Errorf("the output index %d is out of range", index)
+		}
+
+		inputs = append(inputs, UTXOInput{
+			PreviousOutput: OutputPoint{
+				BlockHash: string(blockHash),
+				Index:     int(index),
+			},
+			Signature: decoder.readBytes(ed25519.SignatureSize),
+		})
+	}
+
+	outputCount := decoder.readCount(utxoOutputSize)
+	outputs := make([]UTXOOutput, 0, outputCount)
+	for range outputCount {
+		outputs = append(outputs, UTXOOutput{
+			Receiver: decoder.readBytes(ed25519.PublicKeySize),
+			Amount:   decoder.readUint64(),
+		})
+	}
+
+	if decoder.err == nil && len(decoder.rawData) != 0 {
+		decoder.err = fmt.Errorf(
+			"the transaction has %d extra bytes",
+			len(decoder.rawData),
+		)
+	}
+	if decoder.err != nil {
+		return errors.Join(decoder.err, ErrInvalidUTXOTransaction)
+	}
+
+	*transaction = UTXOTransaction{Inputs: inputs, Outputs: outputs}
+	return nil
+}
+
+// Equal ...
+func (transaction UTXOTransaction) Equal(data blockchain.Data) bool {
+	anotherTransaction, ok := data.(UTXOTransaction)
+	if !ok {
+		return false
+	}
+
+	return slices.EqualFunc(
+		transaction.Inputs,
+		anotherTransaction.Inputs,
+		func(input UTXOInput, anotherInput UTXOInput) bool {
+			return input.PreviousOutput == anotherInput.PreviousOutput &&
+				bytes.Equal(input.Signature, anotherInput.Signature)
+		},
+	) && slices.EqualFunc(
+		transaction.Outputs,
+		anotherTransaction.Outputs,
+		func(output UTXOOutput, anotherOutput UTXOOutput) bool {
+			return bytes.Equal(output.Receiver, anotherOutput.Receiver) &&
+				output.Amount == anotherOutput.Amount
+		},
+	)
+}
+
+// it checks everything except for the signatures
+func (transaction UTXOTransaction) checkStructure() error {
+	if len(transaction.Inputs) == 0 {
+		return errors.Join(
+			errors.New("the transaction has no inputs"),
+			ErrInvalidUTXOTransaction,
+		)
+	}
+	if len(transaction.Outputs) == 0 {
+		return errors.Join(
+			errors.New("the transaction has no outputs"),
+			ErrInvalidUTXOTransaction,
+		)
+	}
+
+	previousOutputs := make(map[OutputPoint]struct{}, len(transaction.Inputs))
+	for index, input := range transaction.Inputs {
+		if input.PreviousOutput.Index < 0 {
+			return errors.Join(
+				fmt.Errorf(
+					"the output index %d of the input #%d is negative",
+					input.PreviousOutput.Index,
+					index,
+				),
+				ErrInvalidUTXOTransaction,
+			)
+		}
+		if _, ok := previousOutputs[input.PreviousOutput]; ok {
+			return errors.Join(
+				fmt.Errorf(
+					"the output %s is spent twice by the transaction",
+					input.PreviousOutput,
+				),
+				ErrDoubleSpend,
+			)
+		}
+
+		previousOutputs[input.PreviousOutput] = struct{}{}
+	}
+
+	for index, output := range transaction.Outputs {
+		if len(output.Receiver) != ed25519.PublicKeySize {
+			return errors.Join(
+				fmt.Errorf(
+					"the receiver key size %d of the output #%d is invalid",
+					len(output.Receiver),
+					index,
+				),
+				ErrInvalidUTXOTransaction,
+			)
+		}
+	}
+
+	return nil
+}
+
+func (transaction UTXOTransaction) signingData() []byte {
+	buffer := []byte(utxoTransactionSigningPrefix)
+	return transaction.appendData(buffer, false)
+}
+
+func (transaction UTXOTransaction) appendData(
+	buffer []byte,
+	withSignatures bool,
+) []byte {
+	buffer = binary.AppendUvarint(buffer, uint64(len(transaction.Inputs)))
+	for _, input := range transaction.Inputs {
+		blockHash := input.PreviousOutput.BlockHash
+		buffer = binary.AppendUvarint(buffer, uint64(len(blockHash)))
+		buffer = append(buffer, blockHash...)
+		buffer = binary.BigEndian.AppendUint64(
+			buffer,
+			uint64(input.PreviousOutput.Index),
+		)
+		if withSignatures {
+			buffer = append(buffer, input.Signature...)
+		}
+	}
+
+	buffer = binary.AppendUvarint(buffer, uint64(len(transaction.Outputs)))
+	for _, output := range transaction.Outputs {
+		buffer = append(buffer, output.Receiver...)
+		buffer = binary.BigEndian.AppendUint64(buffer, output.Amount)
+	}
+
+	return buffer
+}
+
+// it remembers the first error and skips the reading after it
+type utxoTransactionDecoder struct {
+	rawData []byte
+	err     error
+}
+
+// the minimal item size limits the count by the remaining data,
+// so a corrupted count doesn't cause a huge allocation
+func (decoder *utxoTransactionDecoder) readCount(minItemSize int) uint64 {
+	if decoder.err != nil {
+		return 0
+	}
+
+	count, size := binary.Uvarint(decoder.rawData)
+	if size <= 0 {
+		decoder.err = errors.New("unable to read the count")
+		return 0
+	}
+	decoder.rawData = decoder.rawData[size:]
+
+	if count > uint64(len(decoder.rawData)/minItemSize) {
+		decoder.err = fmt.Errorf("the count %d exceeds the data", count)
+		return 0
+	}
+
+	return count
+}
+
+func (decoder *utxoTransactionDecoder) readBytes(size int) []byte {
+	if decoder.err != nil {
+		return nil
+	}
+	if len(decoder.rawData) < size {
+		decoder.err = fmt.Errorf("unable to read %d bytes", size)
+		return nil
+	}
+
+	data := decoder.rawData[:size:size]
+	decoder.rawData = decoder.rawData[size:]
+
+	return data
+}
+
+func (decoder *utxoTransactionDecoder) readUint64() uint64 {
+	data := decoder.readBytes(8)
+	if data == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(data)
+}
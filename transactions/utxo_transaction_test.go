@@ -0,0 +1,514 @@
+package transactions
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/thewizardplusplus/go-blockchain"
+)
+
+func TestSignUTXOTransaction(test *testing.T) {
+	type args struct {
+		previousOutputs []OutputPoint
+		outputs         []UTXOOutput
+		privateKeys     []ed25519.PrivateKey
+	}
+
+	for _, data := range []struct {
+		name    string
+		args    args
+		wantErr assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			args: args{
+				previousOutputs: []OutputPoint{
+					{BlockHash: "hash #1", Index: 0},
+					{BlockHash: "hash #2", Index: 1},
+				},
+				outputs: []UTXOOutput{
+					{Receiver: testPublicKey(3), Amount: 23},
+				},
+				privateKeys: []ed25519.PrivateKey{
+					testPrivateKey(1),
+					testPrivateKey(2),
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/another private key count",
+			args: args{
+				previousOutputs: []OutputPoint{
+					{BlockHash: "hash #1", Index: 0},
+				},
+				outputs: []UTXOOutput{
+					{Receiver: testPublicKey(3), Amount: 23},
+				},
+				privateKeys: nil,
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+		{
+			name: "error/invalid private key",
+			args: args{
+				previousOutputs: []OutputPoint{
+					{BlockHash: "hash #1", Index: 0},
+				},
+				outputs: []UTXOOutput{
+					{Receiver: testPublicKey(3), Amount: 23},
+				},
+				privateKeys: []ed25519.PrivateKey{
+					ed25519.PrivateKey("invalid"),
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+		{
+			name: "error/no inputs",
+			args: args{
+				previousOutputs: nil,
+				outputs: []UTXOOutput{
+					{Receiver: testPublicKey(3), Amount: 23},
+				},
+				privateKeys: nil,
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+		{
+			name: "error/no outputs",
+			args: args{
+				previousOutputs: []OutputPoint{
+					{BlockHash: "hash #1", Index: 0},
+				},
+				outputs: nil,
+				privateKeys: []ed25519.PrivateKey{
+					testPrivateKey(1),
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+		{
+			name: "error/negative output index",
+			args: args{
+				previousOutputs: []OutputPoint{
+					{BlockHash: "hash #1", Index: -1},
+				},
+				outputs: []UTXOOutput{
+					{Receiver: testPublicKey(3), Amount: 23},
+				},
+				privateKeys: []ed25519.PrivateKey{
+					testPrivateKey(1),
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+		{
+			name: "error/double spend within the transaction",
+			args: args{
+				previousOutputs: []OutputPoint{
+					{BlockHash: "hash #1", Index: 0},
+					{BlockHash: "hash #1", Index: 0},
+				},
+				outputs: []UTXOOutput{
+					{Receiver: testPublicKey(3), Amount: 23},
+				},
+				privateKeys: []ed25519.PrivateKey{
+					testPrivateKey(1),
+					testPrivateKey(1),
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrDoubleSpend)
+			},
+		},
+		{
+			name: "error/invalid receiver",
+			args: args{
+				previousOutputs: []OutputPoint{
+					{BlockHash: "hash #1", Index: 0},
+				},
+				outputs: []UTXOOutput{
+					{Receiver: ed25519.PublicKey("invalid"), Amount: 23},
+				},
+				privateKeys: []ed25519.PrivateKey{
+					testPrivateKey(1),
+				},
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got, err := SignUTXOTransaction(
+				data.args.previousOutputs,
+				data.args.outputs,
+				data.args.privateKeys,
+			)
+
+			data.wantErr(test, err)
+			if err == nil {
+				for index, privateKey := range data.args.privateKeys {
+					owner := privateKey.Public().(ed25519.PublicKey)
+					assert.NoError(test, got.VerifyInput(index, owner))
+				}
+			}
+		})
+	}
+}
+
+func TestUTXOTransaction_VerifyInput(test *testing.T) {
+	type args struct {
+		index int
+		owner ed25519.PublicKey
+	}
+
+	for _, data := range []struct {
+		name        string
+		transaction UTXOTransaction
+		args        args
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			transaction: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+				1,
+			),
+			args: args{
+				index: 0,
+				owner: testPublicKey(1),
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/another owner",
+			transaction: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+				1,
+			),
+			args: args{
+				index: 0,
+				owner: testPublicKey(2),
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "error/tampered output",
+			transaction: func() UTXOTransaction {
+				transaction := makeUTXOTransaction(
+					test,
+					[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+					[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+					1,
+				)
+				transaction.Outputs = []UTXOOutput{
+					{Receiver: testPublicKey(3), Amount: 23},
+				}
+
+				return transaction
+			}(),
+			args: args{
+				index: 0,
+				owner: testPublicKey(1),
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidSignature)
+			},
+		},
+		{
+			name: "error/absent input",
+			transaction: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+				1,
+			),
+			args: args{
+				index: 1,
+				owner: testPublicKey(1),
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+		{
+			name: "error/invalid owner",
+			transaction: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+				1,
+			),
+			args: args{
+				index: 0,
+				owner: ed25519.PublicKey("invalid"),
+			},
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			err := data.transaction.VerifyInput(data.args.index, data.args.owner)
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestUTXOTransaction_String(test *testing.T) {
+	transaction := UTXOTransaction{
+		Inputs: []UTXOInput{
+			{
+				PreviousOutput: OutputPoint{BlockHash: "hash #1", Index: 2},
+				Signature:      []byte{0x01, 0x02},
+			},
+			{
+				PreviousOutput: OutputPoint{BlockHash: "hash #3", Index: 4},
+				Signature:      []byte{0x03, 0x04},
+			},
+		},
+		Outputs: []UTXOOutput{
+			{Receiver: ed25519.PublicKey{0x05, 0x06}, Amount: 23},
+			{Receiver: ed25519.PublicKey{0x07, 0x08}, Amount: 42},
+		},
+	}
+	got := transaction.String()
+
+	want := "utxo:hash #1/2/0102,hash #3/4/0304:0506/23,0708/42"
+	assert.Equal(test, want, got)
+}
+
+func TestUTXOTransaction_binaryMarshalling(test *testing.T) {
+	transaction := makeUTXOTransaction(
+		test,
+		[]OutputPoint{
+			{BlockHash: "hash #1", Index: 0},
+			{BlockHash: "", Index: 23},
+		},
+		[]UTXOOutput{
+			{Receiver: testPublicKey(3), Amount: 23},
+			{Receiver: testPublicKey(4), Amount: 42},
+		},
+		1,
+		2,
+	)
+
+	rawData, err := blockchain.EncodeData(transaction)
+	require.NoError(test, err)
+
+	got, err := blockchain.DecodeData(rawData)
+	require.NoError(test, err)
+
+	assert.Equal(test, transaction, got)
+}
+
+func TestUTXOTransaction_MarshalBinary(test *testing.T) {
+	for _, data := range []struct {
+		name        string
+		transaction UTXOTransaction
+		wantErr     assert.ErrorAssertionFunc
+	}{
+		{
+			name: "success",
+			transaction: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+				1,
+			),
+			wantErr: assert.NoError,
+		},
+		{
+			name: "error/invalid signature",
+			transaction: func() UTXOTransaction {
+				transaction := makeUTXOTransaction(
+					test,
+					[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+					[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+					1,
+				)
+				transaction.Inputs[0].Signature = []byte("invalid")
+
+				return transaction
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+		{
+			name: "error/no outputs",
+			transaction: func() UTXOTransaction {
+				transaction := makeUTXOTransaction(
+					test,
+					[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+					[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+					1,
+				)
+				transaction.Outputs = nil
+
+				return transaction
+			}(),
+			wantErr: func(test assert.TestingT, err error, msgAndArgs ...any) bool {
+				return assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+			},
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			_, err := data.transaction.MarshalBinary()
+
+			data.wantErr(test, err)
+		})
+	}
+}
+
+func TestUTXOTransaction_UnmarshalBinary(test *testing.T) {
+	validRawData, err := makeUTXOTransaction(
+		test,
+		[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+		[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+		1,
+	).MarshalBinary()
+	require.NoError(test, err)
+
+	for _, data := range []struct {
+		name    string
+		rawData []byte
+	}{
+		{
+			name:    "empty data",
+			rawData: nil,
+		},
+		{
+			name:    "truncated data",
+			rawData: validRawData[:len(validRawData)-1],
+		},
+		{
+			name:    "extra data",
+			rawData: append(append([]byte(nil), validRawData...), 0x00),
+		},
+		{
+			name: "huge input count",
+			rawData: append(
+				[]byte{0xff, 0xff, 0xff, 0xff, 0x0f},
+				validRawData[1:]...,
+			),
+		},
+		{
+			name: "output index out of range",
+			rawData: func() []byte {
+				rawData := append([]byte(nil), validRawData...)
+				// the input count, the block hash length and the block hash
+				rawData[2+len("hash #1")] = 0x80
+
+				return rawData
+			}(),
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			var got UTXOTransaction
+			err := got.UnmarshalBinary(data.rawData)
+
+			assert.Equal(test, UTXOTransaction{}, got)
+			assert.ErrorIs(test, err, ErrInvalidUTXOTransaction)
+		})
+	}
+}
+
+func TestUTXOTransaction_Equal(test *testing.T) {
+	transaction := makeUTXOTransaction(
+		test,
+		[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+		[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+		1,
+	)
+
+	for _, data := range []struct {
+		name         string
+		anotherData  blockchain.Data
+		wantEquality assert.BoolAssertionFunc
+	}{
+		{
+			name: "equal",
+			anotherData: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+				1,
+			),
+			wantEquality: assert.True,
+		},
+		{
+			name: "not equal/another input",
+			anotherData: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 1}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 23}},
+				1,
+			),
+			wantEquality: assert.False,
+		},
+		{
+			name: "not equal/another output",
+			anotherData: makeUTXOTransaction(
+				test,
+				[]OutputPoint{{BlockHash: "hash #1", Index: 0}},
+				[]UTXOOutput{{Receiver: testPublicKey(2), Amount: 42}},
+				1,
+			),
+			wantEquality: assert.False,
+		},
+		{
+			name:         "not equal/another data type",
+			anotherData:  blockchain.NewData("data"),
+			wantEquality: assert.False,
+		},
+	} {
+		test.Run(data.name, func(test *testing.T) {
+			got := transaction.Equal(data.anotherData)
+
+			data.wantEquality(test, got)
+		})
+	}
+}
+
+func makeUTXOTransaction(
+	test *testing.T,
+	previousOutputs []OutputPoint,
+	outputs []UTXOOutput,
+	ownerSeeds ...byte,
+) UTXOTransaction {
+	privateKeys := make([]ed25519.PrivateKey, 0, len(ownerSeeds))
+	for _, ownerSeed := range ownerSeeds {
+		privateKeys = append(privateKeys, testPrivateKey(ownerSeed))
+	}
+
+	transaction, err := SignUTXOTransaction(
+		previousOutputs,
+		outputs,
+		privateKeys,
+	)
+	require.NoError(test, err)
+
+	return transaction
+}